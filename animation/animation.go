@@ -0,0 +1,199 @@
+// Package animation encodes frames, e.g. of a Playwright screencast, into
+// animated GIF or APNG images using only the standard library. Frame timing is
+// taken from the time each frame was captured.
+package animation
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"time"
+)
+
+// Format is the image format written by an [Encoder].
+type Format int
+
+const (
+	// GIF writes an animated GIF with a 256 color palette per frame. Delays are
+	// rounded to hundredths of a second.
+	GIF Format = iota
+	// APNG writes an animated PNG in true color with millisecond delays. Encoded
+	// frames are kept in memory until the encoder is closed, as the frame count
+	// precedes them in the file.
+	APNG
+)
+
+// Options configures an [Encoder].
+type Options struct {
+	Format Format
+	// Size of the animation. Defaults to the size of the first frame. Frames of
+	// a different size are drawn at the top left corner and cropped.
+	Width, Height int
+	// How often the animation is played, zero means forever.
+	LoopCount int
+	// How long the last frame is shown. Defaults to one second.
+	LastFrameDelay time.Duration
+	// Color behind transparent parts of frames. Defaults to white.
+	Background color.Color
+}
+
+// Frame is an image captured at Time.
+type Frame struct {
+	Image image.Image
+	Time  time.Time
+}
+
+// Encode writes frames as an animation to w. See [Encoder] for how frames are
+// timed.
+func Encode(w io.Writer, frames []Frame, options Options) error {
+	encoder := NewEncoder(w, options)
+	for _, frame := range frames {
+		if err := encoder.Add(frame.Image, frame.Time); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// frameWriter writes frames in a format.
+type frameWriter interface {
+	// delayUnit is the resolution of frame delays, minDelay the shortest delay
+	// that is displayed reliably.
+	delayUnit() time.Duration
+	minDelay() time.Duration
+	writeFrame(frame *image.RGBA, delay time.Duration) error
+	close() error
+}
+
+// Encoder writes frames as they are captured. Each frame is shown until the
+// next one was captured, so only the last frame is held in memory. Frames that
+// would be shown shorter than the format supports are replaced by the next
+// one, and frames captured before the previous one are dropped.
+type Encoder struct {
+	w       io.Writer
+	options Options
+	writer  frameWriter
+	bounds  image.Rectangle
+	// start is the capture time of the first frame, emitted the total delay
+	// written so far. Delays are computed from them, so rounding errors don't
+	// add up.
+	start   time.Time
+	emitted time.Duration
+	pending *image.RGBA
+	at      time.Time
+	frames  int
+	err     error
+	closed  bool
+}
+
+// NewEncoder returns an encoder writing an animation to w. Nothing is written
+// before the first frame is added.
+func NewEncoder(w io.Writer, options Options) *Encoder {
+	if options.LastFrameDelay <= 0 {
+		options.LastFrameDelay = time.Second
+	}
+	if options.Background == nil {
+		options.Background = color.White
+	}
+	return &Encoder{w: w, options: options}
+}
+
+// Add adds img, captured at the given time.
+func (e *Encoder) Add(img image.Image, at time.Time) error {
+	if e.closed {
+		return errors.New("animation: encoder is closed")
+	}
+	if e.err != nil {
+		return e.err
+	}
+	if e.writer == nil {
+		e.start = at
+		if err := e.init(img.Bounds()); err != nil {
+			e.err = err
+			return err
+		}
+	} else if at.Before(e.at) {
+		return nil
+	}
+	frame := e.canvas(img)
+	if e.pending != nil {
+		delay := e.quantize(at.Sub(e.start)) - e.emitted
+		if delay >= e.writer.minDelay() {
+			if err := e.writer.writeFrame(e.pending, delay); err != nil {
+				e.err = err
+				return err
+			}
+			e.emitted += delay
+			e.frames++
+		}
+	}
+	e.pending = frame
+	e.at = at
+	return nil
+}
+
+// Frames returns the number of frames written so far, excluding the last one
+// added.
+func (e *Encoder) Frames() int {
+	return e.frames
+}
+
+// Close writes the last frame and finishes the animation. It doesn't close the
+// underlying writer.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.err != nil {
+		return e.err
+	}
+	if e.writer == nil {
+		return errors.New("animation: no frames")
+	}
+	delay := max(e.quantize(e.options.LastFrameDelay), e.writer.minDelay())
+	if err := e.writer.writeFrame(e.pending, delay); err != nil {
+		return err
+	}
+	e.frames++
+	e.pending = nil
+	return e.writer.close()
+}
+
+func (e *Encoder) init(bounds image.Rectangle) error {
+	width, height := e.options.Width, e.options.Height
+	if width <= 0 || height <= 0 {
+		width, height = bounds.Dx(), bounds.Dy()
+	}
+	if width <= 0 || height <= 0 {
+		return errors.New("animation: empty frame")
+	}
+	e.bounds = image.Rect(0, 0, width, height)
+	switch e.options.Format {
+	case GIF:
+		if width > math.MaxUint16 || height > math.MaxUint16 {
+			return errors.New("animation: frame too large for GIF")
+		}
+		e.writer = newGIFWriter(e.w, width, height, e.options.LoopCount)
+	case APNG:
+		e.writer = newAPNGWriter(e.w, width, height, e.options.LoopCount)
+	default:
+		return errors.New("animation: unknown format")
+	}
+	return nil
+}
+
+// canvas draws img onto a new image of the size of the animation.
+func (e *Encoder) canvas(img image.Image) *image.RGBA {
+	canvas := image.NewRGBA(e.bounds)
+	draw.Draw(canvas, e.bounds, image.NewUniform(e.options.Background), image.Point{}, draw.Src)
+	draw.Draw(canvas, e.bounds, img, img.Bounds().Min, draw.Over)
+	return canvas
+}
+
+func (e *Encoder) quantize(d time.Duration) time.Duration {
+	return d.Round(e.writer.delayUnit())
+}
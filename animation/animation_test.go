@@ -0,0 +1,143 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func solid(width, height int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+	blue  = color.RGBA{B: 255, A: 255}
+)
+
+func testFrames() []Frame {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []Frame{
+		{Image: solid(8, 6, red), Time: start},
+		{Image: solid(8, 6, green), Time: start.Add(333 * time.Millisecond)},
+		// Shown shorter than supported, replaced by the next frame.
+		{Image: solid(8, 6, red), Time: start.Add(338 * time.Millisecond)},
+		{Image: solid(8, 6, blue), Time: start.Add(400 * time.Millisecond)},
+		// Captured before the previous frame, dropped.
+		{Image: solid(8, 6, red), Time: start.Add(100 * time.Millisecond)},
+	}
+}
+
+func TestEncodeGIF(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, testFrames(), Options{Format: GIF, LastFrameDelay: 500 * time.Millisecond}))
+
+	decoded, err := gif.DecodeAll(&out)
+	require.NoError(t, err)
+	require.Equal(t, 0, decoded.LoopCount)
+	require.Equal(t, 8, decoded.Config.Width)
+	require.Equal(t, 6, decoded.Config.Height)
+	require.Len(t, decoded.Image, 3)
+	require.Equal(t, []int{33, 7, 50}, decoded.Delay)
+	for i, expected := range []color.Color{red, red, blue} {
+		r, g, b, _ := decoded.Image[i].At(3, 3).RGBA()
+		er, eg, eb, _ := expected.RGBA()
+		require.Equal(t, [3]uint32{er >> 8, eg >> 8, eb >> 8}, [3]uint32{r >> 8, g >> 8, b >> 8}, "frame %d", i)
+	}
+}
+
+func TestEncodeGIFManyColors(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 4), B: 128, A: 255})
+		}
+	}
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, []Frame{{Image: img, Time: time.Now()}}, Options{Format: GIF}))
+	decoded, err := gif.DecodeAll(&out)
+	require.NoError(t, err)
+	require.Len(t, decoded.Image, 1)
+	r, g, b, _ := decoded.Image[0].At(40, 20).RGBA()
+	require.InDelta(t, 160, r>>8, 16)
+	require.InDelta(t, 80, g>>8, 16)
+	require.InDelta(t, 128, b>>8, 16)
+}
+
+// pngChunks returns the names and data of the chunks of a PNG.
+func pngChunks(t *testing.T, data []byte) (names []string, chunks [][]byte) {
+	require.Equal(t, "\x89PNG\r\n\x1a\n", string(data[:8]))
+	data = data[8:]
+	for len(data) > 0 {
+		length := binary.BigEndian.Uint32(data)
+		names = append(names, string(data[4:8]))
+		chunks = append(chunks, data[8:8+length])
+		data = data[12+length:]
+	}
+	return names, chunks
+}
+
+func TestEncodeAPNG(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Encode(&out, testFrames(), Options{Format: APNG, LoopCount: 2}))
+
+	names, chunks := pngChunks(t, out.Bytes())
+	require.Equal(t, []string{"IHDR", "acTL", "fcTL", "IDAT", "fcTL", "fdAT", "fcTL", "fdAT", "IEND"}, names)
+	require.Equal(t, uint32(3), binary.BigEndian.Uint32(chunks[1]))
+	require.Equal(t, uint32(2), binary.BigEndian.Uint32(chunks[1][4:]))
+	var delays []uint16
+	var sequence []uint32
+	for i, name := range names {
+		switch name {
+		case "fcTL":
+			sequence = append(sequence, binary.BigEndian.Uint32(chunks[i]))
+			delays = append(delays, binary.BigEndian.Uint16(chunks[i][20:]))
+			require.Equal(t, uint16(1000), binary.BigEndian.Uint16(chunks[i][22:]))
+		case "fdAT":
+			sequence = append(sequence, binary.BigEndian.Uint32(chunks[i]))
+		}
+	}
+	require.Equal(t, []uint16{333, 67, 1000}, delays)
+	require.Equal(t, []uint32{0, 1, 2, 3, 4}, sequence)
+
+	// Decoders without APNG support show the first frame.
+	decoded, err := png.Decode(bytes.NewReader(out.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 8, 6), decoded.Bounds())
+	r, g, b, _ := decoded.At(7, 5).RGBA()
+	require.Equal(t, [3]uint32{0xffff, 0, 0}, [3]uint32{r, g, b})
+}
+
+func TestEncoderCanvas(t *testing.T) {
+	var out bytes.Buffer
+	encoder := NewEncoder(&out, Options{Format: GIF, Width: 4, Height: 4})
+	start := time.Now()
+	require.NoError(t, encoder.Add(solid(2, 2, red), start))
+	require.NoError(t, encoder.Add(solid(8, 8, blue), start.Add(time.Second)))
+	require.Equal(t, 1, encoder.Frames())
+	require.NoError(t, encoder.Close())
+	require.Equal(t, 2, encoder.Frames())
+	require.Error(t, encoder.Add(solid(2, 2, red), start))
+
+	decoded, err := gif.DecodeAll(&out)
+	require.NoError(t, err)
+	require.Equal(t, 4, decoded.Config.Width)
+	r, g, b, _ := decoded.Image[0].At(3, 3).RGBA()
+	require.Equal(t, [3]uint32{0xffff, 0xffff, 0xffff}, [3]uint32{r, g, b})
+
+	require.Error(t, NewEncoder(&out, Options{}).Close())
+}
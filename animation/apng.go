@@ -0,0 +1,125 @@
+package animation
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"io"
+	"time"
+)
+
+// apngWriter collects compressed frames and writes the animated PNG when it
+// is closed.
+type apngWriter struct {
+	w             io.Writer
+	width, height int
+	loopCount     int
+	frames        bytes.Buffer
+	count         int
+	sequence      uint32
+	row, previous []byte
+}
+
+func newAPNGWriter(w io.Writer, width, height, loopCount int) *apngWriter {
+	return &apngWriter{
+		w:         w,
+		width:     width,
+		height:    height,
+		loopCount: loopCount,
+		row:       make([]byte, 1+4*width),
+		previous:  make([]byte, 4*width),
+	}
+}
+
+func (a *apngWriter) delayUnit() time.Duration { return time.Millisecond }
+
+func (a *apngWriter) minDelay() time.Duration { return 10 * time.Millisecond }
+
+func (a *apngWriter) writeFrame(frame *image.RGBA, delay time.Duration) error {
+	data, err := a.compress(frame)
+	if err != nil {
+		return err
+	}
+	// Frame control: full size at the origin, no disposal, replacing the
+	// previous frame.
+	numerator, denominator := delay.Milliseconds(), int64(1000)
+	if numerator > 0xffff {
+		numerator, denominator = min(delay.Milliseconds()/10, 0xffff), 100
+	}
+	control := binary.BigEndian.AppendUint32(nil, a.nextSequence())
+	control = binary.BigEndian.AppendUint32(control, uint32(a.width))
+	control = binary.BigEndian.AppendUint32(control, uint32(a.height))
+	control = binary.BigEndian.AppendUint32(control, 0)
+	control = binary.BigEndian.AppendUint32(control, 0)
+	control = binary.BigEndian.AppendUint16(control, uint16(numerator))
+	control = binary.BigEndian.AppendUint16(control, uint16(denominator))
+	control = append(control, 0, 0)
+	writePNGChunk(&a.frames, "fcTL", control)
+	if a.count == 0 {
+		writePNGChunk(&a.frames, "IDAT", data)
+	} else {
+		writePNGChunk(&a.frames, "fdAT", append(binary.BigEndian.AppendUint32(nil, a.nextSequence()), data...))
+	}
+	a.count++
+	return nil
+}
+
+// compress returns the zlib compressed scanlines of frame, each filtered with
+// the Up filter.
+func (a *apngWriter) compress(frame *image.RGBA) ([]byte, error) {
+	var data bytes.Buffer
+	compressor := zlib.NewWriter(&data)
+	clear(a.previous)
+	a.row[0] = 2
+	for y := 0; y < a.height; y++ {
+		line := frame.Pix[y*frame.Stride : y*frame.Stride+4*a.width]
+		for i, value := range line {
+			a.row[1+i] = value - a.previous[i]
+		}
+		copy(a.previous, line)
+		if _, err := compressor.Write(a.row); err != nil {
+			return nil, err
+		}
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+func (a *apngWriter) nextSequence() uint32 {
+	sequence := a.sequence
+	a.sequence++
+	return sequence
+}
+
+func (a *apngWriter) close() error {
+	var out bytes.Buffer
+	out.WriteString("\x89PNG\r\n\x1a\n")
+	header := binary.BigEndian.AppendUint32(nil, uint32(a.width))
+	header = binary.BigEndian.AppendUint32(header, uint32(a.height))
+	// 8 bit RGBA, default compression, filtering and no interlacing.
+	header = append(header, 8, 6, 0, 0, 0)
+	writePNGChunk(&out, "IHDR", header)
+	control := binary.BigEndian.AppendUint32(nil, uint32(a.count))
+	control = binary.BigEndian.AppendUint32(control, uint32(a.loopCount))
+	writePNGChunk(&out, "acTL", control)
+	if _, err := a.w.Write(out.Bytes()); err != nil {
+		return err
+	}
+	writePNGChunk(&a.frames, "IEND", nil)
+	_, err := a.frames.WriteTo(a.w)
+	return err
+}
+
+func writePNGChunk(w *bytes.Buffer, name string, data []byte) {
+	w.Write(binary.BigEndian.AppendUint32(nil, uint32(len(data))))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(name))
+	crc.Write(data)
+	w.WriteString(name)
+	w.Write(data)
+	w.Write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+}
@@ -0,0 +1,245 @@
+package animation
+
+import (
+	"bytes"
+	"compress/lzw"
+	"encoding/binary"
+	"image"
+	"io"
+	"sort"
+	"time"
+)
+
+// gifWriter writes an animated GIF frame by frame, each with its own palette.
+type gifWriter struct {
+	w             io.Writer
+	width, height int
+	loopCount     int
+	started       bool
+	indexes       []byte
+	histogram     *colorHistogram
+	buf           bytes.Buffer
+}
+
+func newGIFWriter(w io.Writer, width, height, loopCount int) *gifWriter {
+	return &gifWriter{
+		w:         w,
+		width:     width,
+		height:    height,
+		loopCount: loopCount,
+		indexes:   make([]byte, width*height),
+		histogram: &colorHistogram{},
+	}
+}
+
+func (g *gifWriter) delayUnit() time.Duration { return 10 * time.Millisecond }
+
+// Browsers show frames with delays below 20ms for 100ms.
+func (g *gifWriter) minDelay() time.Duration { return 20 * time.Millisecond }
+
+func (g *gifWriter) writeFrame(frame *image.RGBA, delay time.Duration) error {
+	g.buf.Reset()
+	if !g.started {
+		g.started = true
+		g.buf.Write(g.header())
+	}
+	palette := g.histogram.quantize(frame, g.indexes)
+	centiseconds := uint16(min(delay/(10*time.Millisecond), 0xffff))
+
+	// Graphic control extension: no disposal, delay, no transparency.
+	b := []byte{0x21, 0xf9, 0x04, 0x04}
+	b = binary.LittleEndian.AppendUint16(b, centiseconds)
+	b = append(b, 0x00, 0x00)
+	// Image descriptor with a local color table of 256 entries.
+	b = append(b, 0x2c, 0, 0, 0, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(g.width))
+	b = binary.LittleEndian.AppendUint16(b, uint16(g.height))
+	b = append(b, 0x80|0x07)
+	b = append(b, palette[:]...)
+	// Image data, compressed with a minimum code size of 8 bits.
+	b = append(b, 8)
+	g.buf.Write(b)
+
+	blocks := &gifBlockWriter{w: &g.buf}
+	compressor := lzw.NewWriter(blocks, lzw.LSB, 8)
+	if _, err := compressor.Write(g.indexes); err != nil {
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+	if err := blocks.close(); err != nil {
+		return err
+	}
+	_, err := g.w.Write(g.buf.Bytes())
+	return err
+}
+
+func (g *gifWriter) header() []byte {
+	b := []byte("GIF89a")
+	// Logical screen descriptor without a global color table.
+	b = binary.LittleEndian.AppendUint16(b, uint16(g.width))
+	b = binary.LittleEndian.AppendUint16(b, uint16(g.height))
+	b = append(b, 0x70, 0x00, 0x00)
+	// Netscape application extension for looping.
+	b = append(b, 0x21, 0xff, 0x0b)
+	b = append(b, "NETSCAPE2.0"...)
+	b = append(b, 0x03, 0x01)
+	b = binary.LittleEndian.AppendUint16(b, uint16(min(g.loopCount, 0xffff)))
+	return append(b, 0x00)
+}
+
+func (g *gifWriter) close() error {
+	_, err := g.w.Write([]byte{0x3b})
+	return err
+}
+
+// gifBlockWriter splits data into sub-blocks of up to 255 bytes.
+type gifBlockWriter struct {
+	w   io.Writer
+	buf [256]byte
+	n   int
+}
+
+func (b *gifBlockWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(b.buf[1+b.n:], p)
+		b.n += n
+		written += n
+		p = p[n:]
+		if b.n == 255 {
+			if err := b.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (b *gifBlockWriter) flush() error {
+	if b.n == 0 {
+		return nil
+	}
+	b.buf[0] = byte(b.n)
+	_, err := b.w.Write(b.buf[:1+b.n])
+	b.n = 0
+	return err
+}
+
+// close writes the remaining data and the block terminator.
+func (b *gifBlockWriter) close() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+	_, err := b.w.Write([]byte{0x00})
+	return err
+}
+
+// colorHistogram builds a palette per frame with median cut over the colors of
+// the frame, reduced to 5 bits per channel. Frames with up to 256 such colors,
+// as is common for screen content, keep all of them.
+type colorHistogram struct {
+	counts [1 << 15]uint32
+	sums   [1 << 15][3]uint32
+	lookup [1 << 15]uint8
+}
+
+func colorBin(r, g, b uint8) int {
+	return int(r>>3)<<10 | int(g>>3)<<5 | int(b>>3)
+}
+
+// binChannel returns the 5 bit value of channel c of bin.
+func binChannel(bin, c int) int {
+	return bin >> (10 - 5*c) & 0x1f
+}
+
+// quantize writes the palette index of every pixel of frame to indexes and
+// returns the palette as 256 RGB triples.
+func (h *colorHistogram) quantize(frame *image.RGBA, indexes []byte) (palette [256 * 3]byte) {
+	clear(h.counts[:])
+	clear(h.sums[:])
+	for i := 0; i+3 < len(frame.Pix); i += 4 {
+		r, g, b := frame.Pix[i], frame.Pix[i+1], frame.Pix[i+2]
+		bin := colorBin(r, g, b)
+		h.counts[bin]++
+		h.sums[bin][0] += uint32(r)
+		h.sums[bin][1] += uint32(g)
+		h.sums[bin][2] += uint32(b)
+	}
+	var bins []int
+	for bin, count := range h.counts {
+		if count > 0 {
+			bins = append(bins, bin)
+		}
+	}
+	for i, box := range h.medianCut(bins, 256) {
+		var count uint64
+		var sums [3]uint64
+		for _, bin := range box {
+			h.lookup[bin] = uint8(i)
+			count += uint64(h.counts[bin])
+			for c := 0; c < 3; c++ {
+				sums[c] += uint64(h.sums[bin][c])
+			}
+		}
+		for c := 0; c < 3; c++ {
+			palette[i*3+c] = byte(sums[c] / count)
+		}
+	}
+	for i, j := 0, 0; i+3 < len(frame.Pix); i, j = i+4, j+1 {
+		indexes[j] = h.lookup[colorBin(frame.Pix[i], frame.Pix[i+1], frame.Pix[i+2])]
+	}
+	return palette
+}
+
+// medianCut splits bins into at most n boxes, repeatedly splitting the most
+// populated box at the median of its widest channel.
+func (h *colorHistogram) medianCut(bins []int, n int) [][]int {
+	boxes := [][]int{bins}
+	for len(boxes) < n {
+		best, bestCount := -1, uint64(0)
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			var count uint64
+			for _, bin := range box {
+				count += uint64(h.counts[bin])
+			}
+			if count > bestCount {
+				best, bestCount = i, count
+			}
+		}
+		if best < 0 {
+			break
+		}
+		box := boxes[best]
+		channel, widest := 0, -1
+		for c := 0; c < 3; c++ {
+			low, high := 31, 0
+			for _, bin := range box {
+				v := binChannel(bin, c)
+				low, high = min(low, v), max(high, v)
+			}
+			if high-low > widest {
+				channel, widest = c, high-low
+			}
+		}
+		sort.Slice(box, func(i, j int) bool {
+			return binChannel(box[i], channel) < binChannel(box[j], channel)
+		})
+		var seen uint64
+		split := 1
+		for i, bin := range box[:len(box)-1] {
+			seen += uint64(h.counts[bin])
+			split = i + 1
+			if seen*2 >= bestCount {
+				break
+			}
+		}
+		boxes[best] = box[:split]
+		boxes = append(boxes, box[split:])
+	}
+	return boxes
+}
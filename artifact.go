@@ -59,12 +59,20 @@ func (a *artifactImpl) Cancel() error {
 }
 
 func (a *artifactImpl) ReadIntoBuffer() ([]byte, error) {
+	stream, err := a.stream()
+	if err != nil {
+		return nil, err
+	}
+	return stream.ReadAll()
+}
+
+// stream returns a reader of the artifact once it is finished.
+func (a *artifactImpl) stream() (*streamImpl, error) {
 	streamChannel, err := a.channel.Send("stream")
 	if err != nil {
 		return nil, err
 	}
-	stream := fromChannel(streamChannel)
-	return stream.(*streamImpl).ReadAll()
+	return fromChannel(streamChannel).(*streamImpl), nil
 }
 
 func newArtifact(parent *channelOwner, objectType string, guid string, initializer map[string]any) *artifactImpl {
@@ -454,6 +454,9 @@ func (b *browserContextImpl) Close(options ...BrowserContextCloseOptions) error
 					return nil, err
 				}
 				if !needCompressed {
+					if err := b.tracing.redactHar(harMetaData.Path); err != nil {
+						return nil, err
+					}
 					continue
 				}
 				entries, ok := response["entries"].([]any)
@@ -468,6 +471,9 @@ func (b *browserContextImpl) Close(options ...BrowserContextCloseOptions) error
 				if err != nil {
 					return nil, err
 				}
+				if err := b.tracing.redactHar(harMetaData.Path); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			overrides["mode"] = "archive"
@@ -495,6 +501,9 @@ func (b *browserContextImpl) Close(options ...BrowserContextCloseOptions) error
 			if err := artifact.Delete(); err != nil {
 				return nil, err
 			}
+			if err := b.tracing.redactHar(harMetaData.Path); err != nil {
+				return nil, err
+			}
 		}
 		return nil, nil
 	}
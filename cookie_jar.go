@@ -0,0 +1,270 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CookieToHTTP converts a cookie of [BrowserContext.Cookies] into an
+// *http.Cookie. Domain keeps its leading dot for cookies that apply to
+// subdomains, session cookies have a zero Expires, and partitioned cookies are
+// marked as Partitioned.
+func CookieToHTTP(cookie Cookie) *http.Cookie {
+	c := &http.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Domain:   cookie.Domain,
+		Path:     cookie.Path,
+		HttpOnly: cookie.HttpOnly,
+		Secure:   cookie.Secure,
+		SameSite: sameSiteToHTTP(cookie.SameSite),
+	}
+	if cookie.Expires > 0 {
+		c.Expires = unixSecondsToTime(cookie.Expires)
+	}
+	if cookie.PartitionKey != nil {
+		setCookiePartitioned(c)
+	}
+	return c
+}
+
+// OptionalCookieToHTTP converts a cookie as passed to
+// [BrowserContext.AddCookies] into an *http.Cookie. If the cookie has a URL,
+// the domain, path and Secure attribute are derived from it the way the
+// browser does.
+func OptionalCookieToHTTP(cookie OptionalCookie) (*http.Cookie, error) {
+	c := &http.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		SameSite: sameSiteToHTTP(cookie.SameSite),
+	}
+	if cookie.URL != nil {
+		u, err := url.Parse(*cookie.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie URL %q: %w", *cookie.URL, err)
+		}
+		c.Domain = u.Hostname()
+		c.Path = defaultCookiePath(u)
+		c.Secure = u.Scheme == "https"
+	}
+	if cookie.Domain != nil {
+		c.Domain = *cookie.Domain
+	}
+	if cookie.Path != nil {
+		c.Path = *cookie.Path
+	}
+	if c.Domain == "" {
+		return nil, errors.New("cookie should have a URL or a domain")
+	}
+	if cookie.Expires != nil && *cookie.Expires > 0 {
+		c.Expires = unixSecondsToTime(*cookie.Expires)
+	}
+	if cookie.HttpOnly != nil {
+		c.HttpOnly = *cookie.HttpOnly
+	}
+	if cookie.Secure != nil {
+		c.Secure = *cookie.Secure
+	}
+	if cookie.PartitionKey != nil {
+		setCookiePartitioned(c)
+	}
+	return c, nil
+}
+
+// CookieFromHTTP converts cookie, as received from u, into a cookie for
+// [BrowserContext.AddCookies]. Cookies without a Domain attribute only apply
+// to the host of u, cookies with one to its subdomains too. u may be nil if
+// the cookie has a Domain.
+//
+// MaxAge takes precedence over Expires; a negative MaxAge results in an expiry
+// in the past. Partitioned cookies are keyed by the top-level site the
+// response was loaded in, not by the site that set them: topLevelSite is that
+// site as scheme and registrable domain, e.g. "https://example.com" for a
+// cookie set by api.example.net in a frame of www.example.com. It is required
+// for partitioned cookies and ignored for others.
+func CookieFromHTTP(cookie *http.Cookie, u *url.URL, topLevelSite string) (OptionalCookie, error) {
+	result := OptionalCookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		HttpOnly: Bool(cookie.HttpOnly),
+		Secure:   Bool(cookie.Secure),
+		SameSite: sameSiteFromHTTP(cookie.SameSite),
+	}
+	host := ""
+	if u != nil {
+		host = strings.ToLower(u.Hostname())
+	}
+	switch domain := strings.ToLower(strings.TrimPrefix(cookie.Domain, ".")); {
+	case domain != "":
+		if host != "" && host != domain && !strings.HasSuffix(host, "."+domain) {
+			return OptionalCookie{}, fmt.Errorf("cookie %q for domain %q can't be set by %s", cookie.Name, cookie.Domain, host)
+		}
+		result.Domain = String("." + domain)
+	case host != "":
+		result.Domain = String(host)
+	default:
+		return OptionalCookie{}, fmt.Errorf("cookie %q needs a domain or a URL", cookie.Name)
+	}
+	if strings.HasPrefix(cookie.Path, "/") {
+		result.Path = String(cookie.Path)
+	} else {
+		result.Path = String(defaultCookiePath(u))
+	}
+	switch {
+	case cookie.MaxAge > 0:
+		result.Expires = Float(float64(time.Now().Unix() + int64(cookie.MaxAge)))
+	case cookie.MaxAge < 0:
+		result.Expires = Float(1)
+	case !cookie.Expires.IsZero():
+		result.Expires = Float(float64(cookie.Expires.UnixMilli()) / 1000)
+	}
+	if isCookiePartitioned(cookie) {
+		if topLevelSite == "" {
+			return OptionalCookie{}, fmt.Errorf("partitioned cookie %q needs the top-level site", cookie.Name)
+		}
+		result.PartitionKey = String(topLevelSite)
+	}
+	return result, nil
+}
+
+// CookieJarOptions are the options for [NewCookieJar].
+type CookieJarOptions struct {
+	// Top-level site partitioned cookies received through the jar are stored
+	// for, e.g. "https://example.com", see [CookieFromHTTP]. Partitioned cookies
+	// are dropped if it is not set, since the site of a URL can't be told
+	// without the public suffix list.
+	TopLevelSite *string
+}
+
+// CookieJar is an [http.CookieJar] backed by the cookies of a [BrowserContext],
+// so an [http.Client] and the browser share a session in both directions.
+type CookieJar struct {
+	context      BrowserContext
+	topLevelSite string
+}
+
+var _ http.CookieJar = (*CookieJar)(nil)
+
+// NewCookieJar returns a cookie jar reading and writing the cookies of context:
+//
+//	client := &http.Client{Jar: playwright.NewCookieJar(context)}
+//
+// [http.CookieJar] can't return errors, failing browser calls are logged.
+func NewCookieJar(context BrowserContext, options ...CookieJarOptions) *CookieJar {
+	jar := &CookieJar{context: context}
+	if len(options) == 1 && options[0].TopLevelSite != nil {
+		jar.topLevelSite = *options[0].TopLevelSite
+	}
+	return jar
+}
+
+// SetCookies stores the cookies received from u in the browser context. Expired
+// cookies are removed.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	add := make([]OptionalCookie, 0, len(cookies))
+	now := time.Now()
+	for _, cookie := range cookies {
+		converted, err := CookieFromHTTP(cookie, u, j.topLevelSite)
+		if err != nil {
+			logger.Error("Error setting cookie", "error", err)
+			continue
+		}
+		if converted.Expires != nil && *converted.Expires <= float64(now.Unix()) {
+			err := j.context.ClearCookies(BrowserContextClearCookiesOptions{
+				Name:   converted.Name,
+				Domain: *converted.Domain,
+				Path:   *converted.Path,
+			})
+			if err != nil {
+				logger.Error("Error removing cookie", "error", err)
+			}
+			continue
+		}
+		add = append(add, converted)
+	}
+	if len(add) == 0 {
+		return
+	}
+	if err := j.context.AddCookies(add); err != nil {
+		logger.Error("Error setting cookies", "error", err)
+	}
+}
+
+// Cookies returns the cookies the browser would send to u. Partitioned cookies
+// are only included if u belongs to the site of their partition.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	cookies, err := j.context.Cookies(u.String())
+	if err != nil {
+		logger.Error("Error getting cookies", "error", err)
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	result := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if cookie.PartitionKey != nil && !inCookiePartition(host, *cookie.PartitionKey) {
+			continue
+		}
+		result = append(result, &http.Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return result
+}
+
+// inCookiePartition reports whether host belongs to the site partitionKey.
+func inCookiePartition(host, partitionKey string) bool {
+	site, err := url.Parse(partitionKey)
+	if err != nil {
+		return false
+	}
+	siteHost := strings.ToLower(site.Hostname())
+	return host == siteHost || strings.HasSuffix(host, "."+siteHost)
+}
+
+// defaultCookiePath is the path of cookies set without a Path attribute, see
+// https://www.rfc-editor.org/rfc/rfc6265#section-5.1.4.
+func defaultCookiePath(u *url.URL) string {
+	if u == nil {
+		return "/"
+	}
+	i := strings.LastIndex(u.Path, "/")
+	if !strings.HasPrefix(u.Path, "/") || i == 0 {
+		return "/"
+	}
+	return u.Path[:i]
+}
+
+func unixSecondsToTime(seconds float64) time.Time {
+	whole, fraction := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second)))
+}
+
+func sameSiteToHTTP(sameSite *SameSiteAttribute) http.SameSite {
+	if sameSite == nil {
+		return http.SameSiteDefaultMode
+	}
+	switch *sameSite {
+	case *SameSiteAttributeStrict:
+		return http.SameSiteStrictMode
+	case *SameSiteAttributeLax:
+		return http.SameSiteLaxMode
+	case *SameSiteAttributeNone:
+		return http.SameSiteNoneMode
+	}
+	return http.SameSiteDefaultMode
+}
+
+func sameSiteFromHTTP(sameSite http.SameSite) *SameSiteAttribute {
+	switch sameSite {
+	case http.SameSiteStrictMode:
+		return SameSiteAttributeStrict
+	case http.SameSiteLaxMode:
+		return SameSiteAttributeLax
+	case http.SameSiteNoneMode:
+		return SameSiteAttributeNone
+	}
+	return nil
+}
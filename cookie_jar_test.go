@@ -0,0 +1,169 @@
+package playwright
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieToHTTP(t *testing.T) {
+	c := CookieToHTTP(Cookie{
+		Name:         "sid",
+		Value:        "abc",
+		Domain:       ".example.com",
+		Path:         "/app",
+		Expires:      1700000000.5,
+		HttpOnly:     true,
+		Secure:       true,
+		SameSite:     SameSiteAttributeNone,
+		PartitionKey: String("https://example.com"),
+	})
+	require.Equal(t, "sid", c.Name)
+	require.Equal(t, ".example.com", c.Domain)
+	require.Equal(t, "/app", c.Path)
+	require.Equal(t, time.Unix(1700000000, 500_000_000), c.Expires)
+	require.True(t, c.HttpOnly)
+	require.True(t, c.Secure)
+	require.Equal(t, http.SameSiteNoneMode, c.SameSite)
+	require.True(t, isCookiePartitioned(c))
+
+	session := CookieToHTTP(Cookie{Name: "a", Value: "b", Domain: "example.com", Path: "/", Expires: -1})
+	require.True(t, session.Expires.IsZero())
+	require.Equal(t, http.SameSiteDefaultMode, session.SameSite)
+	require.False(t, isCookiePartitioned(session))
+}
+
+func TestOptionalCookieToHTTP(t *testing.T) {
+	c, err := OptionalCookieToHTTP(OptionalCookie{
+		Name:     "a",
+		Value:    "b",
+		URL:      String("https://www.example.com/shop/cart"),
+		SameSite: SameSiteAttributeLax,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com", c.Domain)
+	require.Equal(t, "/shop", c.Path)
+	require.True(t, c.Secure)
+	require.Equal(t, http.SameSiteLaxMode, c.SameSite)
+
+	c, err = OptionalCookieToHTTP(OptionalCookie{Name: "a", Value: "b", Domain: String(".example.com"), Path: String("/"), Expires: Float(1700000000)})
+	require.NoError(t, err)
+	require.Equal(t, ".example.com", c.Domain)
+	require.Equal(t, time.Unix(1700000000, 0), c.Expires)
+	require.False(t, c.Secure)
+
+	_, err = OptionalCookieToHTTP(OptionalCookie{Name: "a", Value: "b"})
+	require.Error(t, err)
+}
+
+func TestCookieFromHTTP(t *testing.T) {
+	u, err := url.Parse("https://www.example.com/shop/cart?x=1")
+	require.NoError(t, err)
+
+	cookie, err := CookieFromHTTP(&http.Cookie{Name: "a", Value: "b"}, u, "")
+	require.NoError(t, err)
+	require.Equal(t, "www.example.com", *cookie.Domain)
+	require.Equal(t, "/shop", *cookie.Path)
+	require.Nil(t, cookie.Expires)
+	require.Nil(t, cookie.SameSite)
+	require.Nil(t, cookie.PartitionKey)
+
+	expires := time.Unix(1800000000, 250_000_000)
+	partitioned := &http.Cookie{Name: "a", Value: "b", Domain: "Example.com", Path: "/", Expires: expires, SameSite: http.SameSiteStrictMode, Secure: true}
+	setCookiePartitioned(partitioned)
+	_, err = CookieFromHTTP(partitioned, u, "")
+	require.Error(t, err)
+	cookie, err = CookieFromHTTP(partitioned, u, "https://example.org")
+	require.NoError(t, err)
+	require.Equal(t, ".example.com", *cookie.Domain)
+	require.Equal(t, "/", *cookie.Path)
+	require.Equal(t, 1800000000.25, *cookie.Expires)
+	require.Equal(t, SameSiteAttributeStrict, cookie.SameSite)
+	require.Equal(t, "https://example.org", *cookie.PartitionKey)
+	require.True(t, *cookie.Secure)
+
+	cookie, err = CookieFromHTTP(&http.Cookie{Name: "a", Value: "b", MaxAge: 60, Expires: expires}, u, "")
+	require.NoError(t, err)
+	require.InDelta(t, float64(time.Now().Unix()+60), *cookie.Expires, 2)
+
+	cookie, err = CookieFromHTTP(&http.Cookie{Name: "a", Value: "b", MaxAge: -1}, u, "")
+	require.NoError(t, err)
+	require.Less(t, *cookie.Expires, float64(time.Now().Unix()))
+
+	_, err = CookieFromHTTP(&http.Cookie{Name: "a", Value: "b", Domain: "other.com"}, u, "")
+	require.Error(t, err)
+	_, err = CookieFromHTTP(&http.Cookie{Name: "a", Value: "b"}, nil, "")
+	require.Error(t, err)
+	cookie, err = CookieFromHTTP(&http.Cookie{Name: "a", Value: "b", Domain: "example.com"}, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, "/", *cookie.Path)
+}
+
+func TestDefaultCookiePath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"":         "/",
+		"/":        "/",
+		"/a":       "/",
+		"/a/b":     "/a",
+		"/a/b/":    "/a/b",
+		"relative": "/",
+	} {
+		require.Equal(t, expected, defaultCookiePath(&url.URL{Path: path}), path)
+	}
+}
+
+type fakeCookieContext struct {
+	BrowserContext
+	cookies []Cookie
+	added   []OptionalCookie
+	cleared []BrowserContextClearCookiesOptions
+}
+
+func (c *fakeCookieContext) Cookies(urls ...string) ([]Cookie, error) { return c.cookies, nil }
+func (c *fakeCookieContext) AddCookies(cookies []OptionalCookie) error {
+	c.added = append(c.added, cookies...)
+	return nil
+}
+
+func (c *fakeCookieContext) ClearCookies(options ...BrowserContextClearCookiesOptions) error {
+	c.cleared = append(c.cleared, options...)
+	return nil
+}
+
+func TestCookieJar(t *testing.T) {
+	context := &fakeCookieContext{cookies: []Cookie{
+		{Name: "sid", Value: "1", Domain: "www.example.com", Path: "/"},
+		{Name: "chip", Value: "2", Domain: "www.example.com", Path: "/", PartitionKey: String("https://example.com")},
+		{Name: "embedded", Value: "3", Domain: "www.example.com", Path: "/", PartitionKey: String("https://other.com")},
+	}}
+	jar := NewCookieJar(context)
+	u, err := url.Parse("https://www.example.com/login")
+	require.NoError(t, err)
+
+	cookies := jar.Cookies(u)
+	require.Equal(t, []*http.Cookie{{Name: "sid", Value: "1"}, {Name: "chip", Value: "2"}}, cookies)
+
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "sid", Value: "2", HttpOnly: true},
+		{Name: "old", Value: "", MaxAge: -1, Path: "/"},
+		{Name: "bad", Value: "x", Domain: "other.com"},
+	})
+	require.Len(t, context.added, 1)
+	require.Equal(t, "sid", context.added[0].Name)
+	require.Equal(t, "www.example.com", *context.added[0].Domain)
+	require.True(t, *context.added[0].HttpOnly)
+	require.Equal(t, []BrowserContextClearCookiesOptions{{Name: "old", Domain: "www.example.com", Path: "/"}}, context.cleared)
+
+	// Partitioned cookies need the top-level site.
+	partitioned := &http.Cookie{Name: "chip", Value: "3", Secure: true}
+	setCookiePartitioned(partitioned)
+	jar.SetCookies(u, []*http.Cookie{partitioned})
+	require.Len(t, context.added, 1)
+	jar = NewCookieJar(context, CookieJarOptions{TopLevelSite: String("https://example.com")})
+	jar.SetCookies(u, []*http.Cookie{partitioned})
+	require.Len(t, context.added, 2)
+	require.Equal(t, "https://example.com", *context.added[1].PartitionKey)
+}
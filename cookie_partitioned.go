@@ -0,0 +1,13 @@
+//go:build go1.23
+
+package playwright
+
+import "net/http"
+
+func isCookiePartitioned(cookie *http.Cookie) bool {
+	return cookie.Partitioned
+}
+
+func setCookiePartitioned(cookie *http.Cookie) {
+	cookie.Partitioned = true
+}
@@ -0,0 +1,23 @@
+//go:build !go1.23
+
+package playwright
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Before Go 1.23 http.Cookie has no Partitioned field, the attribute ends up
+// in Unparsed.
+func isCookiePartitioned(cookie *http.Cookie) bool {
+	for _, attribute := range cookie.Unparsed {
+		if strings.EqualFold(strings.TrimSpace(attribute), "Partitioned") {
+			return true
+		}
+	}
+	return false
+}
+
+func setCookiePartitioned(cookie *http.Cookie) {
+	cookie.Unparsed = append(cookie.Unparsed, "Partitioned")
+}
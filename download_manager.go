@@ -0,0 +1,417 @@
+package playwright
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// ErrDownloadTooLarge is the error of downloads exceeding
+// [DownloadManagerOptions.MaxSize].
+var ErrDownloadTooLarge = errors.New("download exceeds the maximal size")
+
+// DownloadManagerOptions are the options for [NewDownloadManager].
+type DownloadManagerOptions struct {
+	// Directory downloads are saved to. It is created if it doesn't exist.
+	Dir string
+	// A text/template for the path of a download relative to Dir, executed with
+	// a [DownloadName], e.g. "{{.Host}}/{{.Index}}-{{.Name}}". Defaults to
+	// "{{.Name}}". Characters that are invalid in file names are replaced and
+	// paths leaving Dir are rejected. If the file exists already, a number is
+	// added to the name, e.g. "report (1).csv".
+	NameTemplate string
+	// Maximal size of a download in bytes. Larger downloads are not saved and
+	// fail with [ErrDownloadTooLarge]. Zero means no limit.
+	MaxSize int64
+	// Maximal number of downloads saved at the same time. Defaults to 4.
+	MaxConcurrency int
+	// How often a failed download is retried. Retries request the URL again
+	// with [BrowserContext.Request], sharing the cookies of the context, so only
+	// http and https downloads are retried. The body of a retry is held in
+	// memory as a whole before it is written.
+	Retries int
+	// Keep the file of a download in the temporary directory of the browser
+	// after it has been saved. By default it is deleted.
+	KeepArtifacts bool
+	// Called with the result of every download once it is saved or failed.
+	OnResult func(result DownloadResult)
+}
+
+// DownloadName is the data [DownloadManagerOptions.NameTemplate] is executed
+// with.
+type DownloadName struct {
+	// Name is the suggested file name, e.g. "report.csv".
+	Name string
+	// Stem is Name without its extension, e.g. "report".
+	Stem string
+	// Ext is the extension of Name including the dot, e.g. ".csv".
+	Ext  string
+	URL  string
+	Host string
+	// Index counts the downloads of the manager, starting at 1.
+	Index int
+	// Time is when the download started.
+	Time time.Time
+}
+
+// DownloadResult describes a download handled by a [DownloadManager].
+type DownloadResult struct {
+	Index             int
+	URL               string
+	SuggestedFilename string
+	// Path of the saved file, empty if the download failed.
+	Path string
+	// Size of the file in bytes.
+	Size int64
+	// SHA256 is the hex encoded SHA-256 checksum of the file.
+	SHA256 string
+	// Attempts is the number of attempts made, 1 if the download wasn't retried.
+	Attempts int
+	Started  time.Time
+	Finished time.Time
+	Err      error
+}
+
+// DownloadManager saves all downloads of a [BrowserContext], see
+// [NewDownloadManager].
+type DownloadManager struct {
+	context  BrowserContext
+	options  DownloadManagerOptions
+	template *template.Template
+	slots    chan struct{}
+	// handler is m.handle, kept to remove the same listener again.
+	handler func(Download)
+
+	mu       sync.Mutex
+	index    int
+	pending  int
+	changed  chan struct{}
+	reserved map[string]bool
+	results  []DownloadResult
+	stopped  bool
+}
+
+// NewDownloadManager saves every download of context into a directory:
+//
+//	manager, _ := playwright.NewDownloadManager(context, playwright.DownloadManagerOptions{
+//		Dir:          "exports",
+//		NameTemplate: "{{.Index}}-{{.Name}}",
+//	})
+//	// click export buttons ...
+//	_ = manager.Wait(time.Minute)
+//	for _, result := range manager.Results() {
+//		fmt.Println(result.Path, result.SHA256, result.Err)
+//	}
+//
+// Files are read from the browser in chunks and hashed while they are written.
+// A download is read once the browser finished it, so MaxSize is checked while
+// saving, not while the browser downloads.
+func NewDownloadManager(context BrowserContext, options DownloadManagerOptions) (*DownloadManager, error) {
+	if options.Dir == "" {
+		return nil, errors.New("download manager needs a directory")
+	}
+	if options.NameTemplate == "" {
+		options.NameTemplate = "{{.Name}}"
+	}
+	if options.MaxConcurrency <= 0 {
+		options.MaxConcurrency = 4
+	}
+	tmpl, err := template.New("download").Option("missingkey=error").Parse(options.NameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download name template: %w", err)
+	}
+	if err := os.MkdirAll(options.Dir, 0o777); err != nil {
+		return nil, err
+	}
+	m := &DownloadManager{
+		context:  context,
+		options:  options,
+		template: tmpl,
+		slots:    make(chan struct{}, options.MaxConcurrency),
+		changed:  make(chan struct{}),
+		reserved: make(map[string]bool),
+	}
+	m.handler = m.handle
+	context.OnDownload(m.handler)
+	return m, nil
+}
+
+func (m *DownloadManager) handle(download Download) {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return
+	}
+	m.index++
+	m.pending++
+	index := m.index
+	m.mu.Unlock()
+	// Saving waits for the download to finish, which must not block the
+	// dispatching of events.
+	go m.save(download, index)
+}
+
+func (m *DownloadManager) save(download Download, index int) {
+	m.slots <- struct{}{}
+	result := DownloadResult{
+		Index:             index,
+		URL:               download.URL(),
+		SuggestedFilename: download.SuggestedFilename(),
+		Started:           time.Now(),
+	}
+	result.Path, result.Err = m.reserve(download, index, result.Started)
+	if result.Err == nil {
+		result.Size, result.SHA256, result.Attempts, result.Err = m.download(download, result.Path)
+		// A saved file keeps its name taken on disk, a failed one frees it.
+		m.release(result.Path)
+		if result.Err != nil {
+			result.Path = ""
+		}
+	}
+	if !m.options.KeepArtifacts {
+		if err := download.Delete(); err != nil {
+			logger.Error("Error deleting download", "error", err)
+		}
+	}
+	result.Finished = time.Now()
+	<-m.slots
+
+	m.mu.Lock()
+	m.results = append(m.results, result)
+	m.pending--
+	close(m.changed)
+	m.changed = make(chan struct{})
+	m.mu.Unlock()
+	if m.options.OnResult != nil {
+		m.options.OnResult(result)
+	}
+}
+
+// download writes download to path, retrying failed attempts.
+func (m *DownloadManager) download(download Download, path string) (size int64, checksum string, attempts int, err error) {
+	for attempts = 1; ; attempts++ {
+		var body io.ReadCloser
+		if attempts == 1 {
+			body, err = openDownload(download, filepath.Dir(path))
+		} else {
+			body, err = m.refetch(download.URL())
+		}
+		if err == nil {
+			size, checksum, err = m.write(path, body)
+		}
+		if err == nil || errors.Is(err, ErrDownloadTooLarge) || attempts > m.options.Retries || !isHTTPURL(download.URL()) {
+			return size, checksum, attempts, err
+		}
+	}
+}
+
+// openDownload returns a reader of the finished download.
+func openDownload(download Download, tempDir string) (io.ReadCloser, error) {
+	if err := download.Failure(); err != nil {
+		return nil, err
+	}
+	if impl, ok := download.(*downloadImpl); ok {
+		return impl.artifact.stream()
+	}
+	file, err := os.CreateTemp(tempDir, ".download-*")
+	if err != nil {
+		return nil, err
+	}
+	file.Close() //nolint:errcheck
+	if err := download.SaveAs(file.Name()); err != nil {
+		return nil, errors.Join(err, os.Remove(file.Name()))
+	}
+	reader, err := os.Open(file.Name())
+	if err != nil {
+		return nil, errors.Join(err, os.Remove(file.Name()))
+	}
+	return &tempFileReader{File: reader}, nil
+}
+
+// tempFileReader removes the file when closed.
+type tempFileReader struct {
+	*os.File
+}
+
+func (f *tempFileReader) Close() error {
+	return errors.Join(f.File.Close(), os.Remove(f.Name()))
+}
+
+func (m *DownloadManager) refetch(u string) (io.ReadCloser, error) {
+	response, err := m.context.Request().Get(u, APIRequestContextGetOptions{
+		FailOnStatusCode: Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The driver sends the body of an APIResponse in a single message.
+	body, err := response.Body()
+	if err = errors.Join(err, response.Dispose()); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+// write copies body to path while hashing it. The file is removed if writing
+// fails.
+func (m *DownloadManager) write(path string, body io.ReadCloser) (int64, string, error) {
+	defer body.Close() //nolint:errcheck
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o666)
+	if err != nil {
+		return 0, "", err
+	}
+	var reader io.Reader = body
+	if m.options.MaxSize > 0 {
+		reader = io.LimitReader(body, m.options.MaxSize+1)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), reader)
+	if err == nil && m.options.MaxSize > 0 && size > m.options.MaxSize {
+		err = fmt.Errorf("%w of %d bytes", ErrDownloadTooLarge, m.options.MaxSize)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, "", errors.Join(err, os.Remove(path))
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// reserve picks a free path for download following the name template. The
+// path stays reserved until the download is written.
+func (m *DownloadManager) reserve(download Download, index int, started time.Time) (string, error) {
+	name := download.SuggestedFilename()
+	ext := path.Ext(name)
+	data := DownloadName{
+		Name:  name,
+		Stem:  strings.TrimSuffix(name, ext),
+		Ext:   ext,
+		URL:   download.URL(),
+		Index: index,
+		Time:  started,
+	}
+	if u, err := url.Parse(download.URL()); err == nil {
+		data.Host = u.Hostname()
+	}
+	var rendered strings.Builder
+	if err := m.template.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("could not name download: %w", err)
+	}
+	relative, err := sanitizeDownloadPath(rendered.String())
+	if err != nil {
+		return "", err
+	}
+	candidate := filepath.Join(m.options.Dir, relative)
+	if err := os.MkdirAll(filepath.Dir(candidate), 0o777); err != nil {
+		return "", err
+	}
+	ext = filepath.Ext(candidate)
+	stem := strings.TrimSuffix(candidate, ext)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := 1; ; i++ {
+		if !m.reserved[candidate] {
+			if _, err := os.Lstat(candidate); errors.Is(err, fs.ErrNotExist) {
+				m.reserved[candidate] = true
+				return candidate, nil
+			}
+		}
+		candidate = fmt.Sprintf("%s (%d)%s", stem, i, ext)
+	}
+}
+
+func (m *DownloadManager) release(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.reserved, path)
+}
+
+// Results returns the results of the finished downloads, in the order they
+// finished.
+func (m *DownloadManager) Results() []DownloadResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DownloadResult(nil), m.results...)
+}
+
+// Pending returns the number of downloads that are not finished yet.
+func (m *DownloadManager) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pending
+}
+
+// Wait waits until all started downloads are finished, or returns
+// [ErrTimeout] after timeout. A timeout of zero waits without limit.
+func (m *DownloadManager) Wait(timeout time.Duration) error {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		m.mu.Lock()
+		pending, changed := m.pending, m.changed
+		m.mu.Unlock()
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return fmt.Errorf("%w: %d downloads pending after %s", ErrTimeout, pending, timeout)
+		}
+	}
+}
+
+// Stop stops handling new downloads. Downloads already started are still
+// saved, use Wait to wait for them.
+func (m *DownloadManager) Stop() {
+	m.mu.Lock()
+	m.stopped = true
+	m.mu.Unlock()
+	m.context.RemoveListener("download", m.handler)
+}
+
+// sanitizeDownloadPath turns name into a relative path of valid file names.
+func sanitizeDownloadPath(name string) (string, error) {
+	var segments []string
+	for _, segment := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		segment = strings.Map(func(r rune) rune {
+			if r < 0x20 || strings.ContainsRune(`<>:"|?*`, r) {
+				return '_'
+			}
+			return r
+		}, strings.TrimSpace(segment))
+		switch segment {
+		case "", ".":
+			continue
+		case "..":
+			return "", fmt.Errorf("download name %q leaves the download directory", name)
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 0 {
+		return "download", nil
+	}
+	return filepath.Join(segments...), nil
+}
+
+func isHTTPURL(u string) bool {
+	return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")
+}
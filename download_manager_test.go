@@ -0,0 +1,227 @@
+package playwright
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDownload struct {
+	Download
+	url      string
+	name     string
+	content  string
+	failure  error
+	deleted  atomic.Bool
+	saveWait chan struct{}
+}
+
+func (d *fakeDownload) URL() string               { return d.url }
+func (d *fakeDownload) SuggestedFilename() string { return d.name }
+func (d *fakeDownload) Failure() error            { return d.failure }
+
+func (d *fakeDownload) SaveAs(path string) error {
+	if d.saveWait != nil {
+		<-d.saveWait
+	}
+	return os.WriteFile(path, []byte(d.content), 0o666)
+}
+
+func (d *fakeDownload) Delete() error {
+	d.deleted.Store(true)
+	return nil
+}
+
+type fakeDownloadResponse struct {
+	APIResponse
+	body string
+}
+
+func (r *fakeDownloadResponse) Body() ([]byte, error) { return []byte(r.body), nil }
+func (r *fakeDownloadResponse) Dispose() error        { return nil }
+
+type fakeDownloadRequest struct {
+	APIRequestContext
+	body string
+	urls []string
+}
+
+func (r *fakeDownloadRequest) Get(url string, options ...APIRequestContextGetOptions) (APIResponse, error) {
+	r.urls = append(r.urls, url)
+	return &fakeDownloadResponse{body: r.body}, nil
+}
+
+type fakeDownloadContext struct {
+	BrowserContext
+	handler func(Download)
+	request *fakeDownloadRequest
+}
+
+func (c *fakeDownloadContext) OnDownload(fn func(Download)) { c.handler = fn }
+func (c *fakeDownloadContext) Request() APIRequestContext   { return c.request }
+
+func (c *fakeDownloadContext) RemoveListener(name string, handler any) {
+	if name == "download" && funcIdentity(handler) == funcIdentity(c.handler) {
+		c.handler = nil
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadManagerSavesAndDeduplicates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "report.csv"), []byte("existing"), 0o666))
+	context := &fakeDownloadContext{}
+	var results []DownloadResult
+	resultsCh := make(chan DownloadResult, 2)
+	manager, err := NewDownloadManager(context, DownloadManagerOptions{
+		Dir:      dir,
+		OnResult: func(result DownloadResult) { resultsCh <- result },
+	})
+	require.NoError(t, err)
+
+	first := &fakeDownload{url: "https://example.com/a", name: "report.csv", content: "a,b"}
+	second := &fakeDownload{url: "https://example.com/b", name: "report.csv", content: "c,d"}
+	context.handler(first)
+	context.handler(second)
+	require.NoError(t, manager.Wait(5*time.Second))
+	results = append(results, <-resultsCh, <-resultsCh)
+	require.Len(t, manager.Results(), 2)
+	require.Zero(t, manager.Pending())
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	paths := []string{results[0].Path, results[1].Path}
+	sort.Strings(paths)
+	require.Equal(t, []string{filepath.Join(dir, "report (1).csv"), filepath.Join(dir, "report (2).csv")}, paths)
+	for i, download := range []*fakeDownload{first, second} {
+		result := results[i]
+		require.NoError(t, result.Err)
+		require.Equal(t, download.url, result.URL)
+		require.Equal(t, int64(len(download.content)), result.Size)
+		require.Equal(t, sha256Hex(download.content), result.SHA256)
+		require.Equal(t, 1, result.Attempts)
+		content, err := os.ReadFile(result.Path)
+		require.NoError(t, err)
+		require.Equal(t, download.content, string(content))
+		require.True(t, download.deleted.Load())
+	}
+	existing, err := os.ReadFile(filepath.Join(dir, "report.csv"))
+	require.NoError(t, err)
+	require.Equal(t, "existing", string(existing))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Empty(t, manager.reserved)
+
+	manager.Stop()
+	require.Nil(t, context.handler)
+}
+
+func TestDownloadManagerNameTemplate(t *testing.T) {
+	dir := t.TempDir()
+	context := &fakeDownloadContext{}
+	manager, err := NewDownloadManager(context, DownloadManagerOptions{
+		Dir:           dir,
+		NameTemplate:  "{{.Host}}/{{.Index}}-{{.Stem}}{{.Ext}}",
+		KeepArtifacts: true,
+	})
+	require.NoError(t, err)
+	download := &fakeDownload{url: "https://files.example.com/x", name: "data.json", content: "{}"}
+	context.handler(download)
+	require.NoError(t, manager.Wait(5*time.Second))
+	result := manager.Results()[0]
+	require.NoError(t, result.Err)
+	require.Equal(t, filepath.Join(dir, "files.example.com", "1-data.json"), result.Path)
+	require.False(t, download.deleted.Load())
+
+	_, err = NewDownloadManager(context, DownloadManagerOptions{Dir: dir, NameTemplate: "{{.Name"})
+	require.ErrorContains(t, err, "invalid download name template")
+	_, err = NewDownloadManager(context, DownloadManagerOptions{})
+	require.Error(t, err)
+}
+
+func TestDownloadManagerMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	context := &fakeDownloadContext{}
+	manager, err := NewDownloadManager(context, DownloadManagerOptions{Dir: dir, MaxSize: 4, Retries: 2})
+	require.NoError(t, err)
+	context.handler(&fakeDownload{url: "https://example.com/big", name: "big.bin", content: "12345"})
+	context.handler(&fakeDownload{url: "https://example.com/small", name: "small.bin", content: "1234"})
+	require.NoError(t, manager.Wait(5*time.Second))
+	for _, result := range manager.Results() {
+		if result.SuggestedFilename == "big.bin" {
+			require.ErrorIs(t, result.Err, ErrDownloadTooLarge)
+			require.Empty(t, result.Path)
+			require.Equal(t, 1, result.Attempts)
+			require.NoFileExists(t, filepath.Join(dir, "big.bin"))
+		} else {
+			require.NoError(t, result.Err)
+			require.Equal(t, int64(4), result.Size)
+		}
+	}
+}
+
+func TestDownloadManagerRetriesFailedDownloads(t *testing.T) {
+	dir := t.TempDir()
+	context := &fakeDownloadContext{request: &fakeDownloadRequest{body: "refetched"}}
+	manager, err := NewDownloadManager(context, DownloadManagerOptions{Dir: dir, Retries: 1})
+	require.NoError(t, err)
+	context.handler(&fakeDownload{url: "https://example.com/flaky", name: "flaky.txt", failure: errors.New("network error")})
+	context.handler(&fakeDownload{url: "blob:https://example.com/1", name: "blob.txt", failure: errors.New("canceled")})
+	require.NoError(t, manager.Wait(5*time.Second))
+	require.Equal(t, []string{"https://example.com/flaky"}, context.request.urls)
+	for _, result := range manager.Results() {
+		if result.SuggestedFilename == "flaky.txt" {
+			require.NoError(t, result.Err)
+			require.Equal(t, 2, result.Attempts)
+			require.Equal(t, sha256Hex("refetched"), result.SHA256)
+		} else {
+			require.ErrorContains(t, result.Err, "canceled")
+			require.Equal(t, 1, result.Attempts)
+		}
+	}
+}
+
+func TestDownloadManagerConcurrencyAndWait(t *testing.T) {
+	context := &fakeDownloadContext{}
+	manager, err := NewDownloadManager(context, DownloadManagerOptions{Dir: t.TempDir(), MaxConcurrency: 1})
+	require.NoError(t, err)
+	release := make(chan struct{})
+	context.handler(&fakeDownload{url: "https://example.com/1", name: "1.txt", saveWait: release})
+	context.handler(&fakeDownload{url: "https://example.com/2", name: "2.txt", saveWait: release})
+	require.ErrorIs(t, manager.Wait(50*time.Millisecond), ErrTimeout)
+	require.Equal(t, 2, manager.Pending())
+	release <- struct{}{}
+	require.Eventually(t, func() bool { return manager.Pending() == 1 }, 5*time.Second, 10*time.Millisecond)
+	close(release)
+	require.NoError(t, manager.Wait(5*time.Second))
+	require.Len(t, manager.Results(), 2)
+}
+
+func TestSanitizeDownloadPath(t *testing.T) {
+	for name, expected := range map[string]string{
+		"report.csv":       "report.csv",
+		"a/b/c.txt":        filepath.Join("a", "b", "c.txt"),
+		`a\b.txt`:          filepath.Join("a", "b.txt"),
+		"/abs/./x.txt":     filepath.Join("abs", "x.txt"),
+		`we<ird>:"n|a?m*e`: "we_ird___n_a_m_e",
+		"":                 "download",
+	} {
+		actual, err := sanitizeDownloadPath(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual, name)
+	}
+	_, err := sanitizeDownloadPath("../escape.txt")
+	require.Error(t, err)
+}
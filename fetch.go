@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 )
 
 type apiRequestImpl struct {
@@ -75,6 +76,7 @@ type apiRequestContextImpl struct {
 	closeReason     *string
 	defaultTimeout  *float64
 	timeoutSettings *timeoutSettings
+	retryPolicy     atomic.Pointer[RetryPolicy]
 }
 
 func (r *apiRequestContextImpl) Dispose(options ...APIRequestContextDisposeOptions) error {
@@ -112,6 +114,13 @@ func (r *apiRequestContextImpl) Delete(url string, options ...APIRequestContextD
 }
 
 func (r *apiRequestContextImpl) Fetch(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
+	if policy := r.retryPolicy.Load(); policy != nil {
+		return fetchWithRetry(r.fetchOnce, *policy, urlOrRequest, options...)
+	}
+	return r.fetchOnce(urlOrRequest, options...)
+}
+
+func (r *apiRequestContextImpl) fetchOnce(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
 	switch v := urlOrRequest.(type) {
 	case string:
 		return r.innerFetch(v, nil, options...)
@@ -225,6 +234,15 @@ func (r *apiRequestContextImpl) innerFetch(url string, request Request, options
 							"buffer":   base64.StdEncoding.EncodeToString(v.Buffer),
 						},
 					})
+				case InputFileStream:
+					file, err := v.multipartFile()
+					if err != nil {
+						return nil, err
+					}
+					multipartData = append(multipartData, map[string]any{
+						"name": name,
+						"file": file,
+					})
 				default:
 					multipartData = append(multipartData, map[string]any{
 						"name":  name,
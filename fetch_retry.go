@@ -0,0 +1,314 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy configures how [APIRequestContext] calls are retried, see
+// [SetRetryPolicy] and [WithRetryPolicy]. Unlike the MaxRetries fetch option,
+// which only retries network errors, it also retries on status codes and
+// waits between attempts with exponential backoff.
+//
+// Requests with an [InputFileStream] multipart field are not retried, since
+// its Reader can only be read once.
+type RetryPolicy struct {
+	// Maximal number of attempts, including the first one. Defaults to 3.
+	MaxAttempts int
+	// Response status codes that are retried. Defaults to 429, 502, 503 and 504.
+	StatusCodes []int
+	// Also retry calls that fail with an error, e.g. a connection reset.
+	// Timeouts are not retried.
+	RetryErrors bool
+	// Wait before the first retry. Defaults to 500ms.
+	InitialInterval time.Duration
+	// Upper bound of the wait between attempts. Defaults to 30s.
+	MaxInterval time.Duration
+	// Factor the wait grows by after each attempt. Defaults to 2.
+	Multiplier float64
+	// Randomizes each wait by up to this fraction in both directions, from 0 to
+	// 1. Defaults to 0.5, pass a negative value to disable jitter.
+	Jitter float64
+	// Stop retrying once this much time has passed since the first attempt,
+	// including the wait that would follow. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// Wait as long as the Retry-After header of a response asks for, if it is
+	// longer than the backoff. Defaults to true.
+	RespectRetryAfter *bool
+	// Only retry idempotent methods: GET, HEAD, OPTIONS, TRACE, PUT and DELETE.
+	// Defaults to true.
+	IdempotentOnly *bool
+	// Called after every attempt.
+	OnAttempt func(attempt RetryAttempt)
+}
+
+// RetryAttempt describes an attempt of a call retried by a [RetryPolicy].
+type RetryAttempt struct {
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+	Method  string
+	URL     string
+	// Response is the response of the attempt, or nil if it failed with Err. It
+	// is disposed before the next attempt.
+	Response APIResponse
+	Err      error
+	// Retry tells whether another attempt follows, after waiting for Delay.
+	Retry bool
+	Delay time.Duration
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+}
+
+// SetRetryPolicy makes every call of request, including those through
+// [Page.Request] and [BrowserContext.Request], follow policy, e.g. right after
+// [APIRequest.NewContext]. Pass nil to stop retrying.
+func SetRetryPolicy(request APIRequestContext, policy *RetryPolicy) error {
+	impl, ok := request.(*apiRequestContextImpl)
+	if !ok {
+		return fmt.Errorf("can't set a retry policy on %T", request)
+	}
+	if policy == nil {
+		impl.retryPolicy.Store(nil)
+		return nil
+	}
+	copied := *policy
+	impl.retryPolicy.Store(&copied)
+	return nil
+}
+
+// WithRetryPolicy returns an [APIRequestContext] whose calls follow policy
+// instead of the one set with [SetRetryPolicy], e.g. for a single call:
+//
+//	response, err := playwright.WithRetryPolicy(request, playwright.RetryPolicy{
+//		MaxAttempts: 5,
+//		StatusCodes: []int{409},
+//	}).Post(url, options)
+func WithRetryPolicy(request APIRequestContext, policy RetryPolicy) APIRequestContext {
+	fetch := request.Fetch
+	if impl, ok := request.(*apiRequestContextImpl); ok {
+		fetch = impl.fetchOnce
+	}
+	return &retryingAPIRequestContext{APIRequestContext: request, fetch: fetch, policy: policy}
+}
+
+type retryingAPIRequestContext struct {
+	APIRequestContext
+	fetch  func(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error)
+	policy RetryPolicy
+}
+
+func (r *retryingAPIRequestContext) Fetch(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
+	return fetchWithRetry(r.fetch, r.policy, urlOrRequest, options...)
+}
+
+func (r *retryingAPIRequestContext) fetchMethod(method, url string, options any) (APIResponse, error) {
+	opts := APIRequestContextFetchOptions{Method: String(method)}
+	if options != nil {
+		if err := assignStructFields(&opts, options, false); err != nil {
+			return nil, err
+		}
+	}
+	return r.Fetch(url, opts)
+}
+
+func (r *retryingAPIRequestContext) Delete(url string, options ...APIRequestContextDeleteOptions) (APIResponse, error) {
+	if len(options) == 1 {
+		return r.fetchMethod("DELETE", url, options[0])
+	}
+	return r.fetchMethod("DELETE", url, nil)
+}
+
+func (r *retryingAPIRequestContext) Get(url string, options ...APIRequestContextGetOptions) (APIResponse, error) {
+	if len(options) == 1 {
+		return r.fetchMethod("GET", url, options[0])
+	}
+	return r.fetchMethod("GET", url, nil)
+}
+
+func (r *retryingAPIRequestContext) Head(url string, options ...APIRequestContextHeadOptions) (APIResponse, error) {
+	if len(options) == 1 {
+		return r.fetchMethod("HEAD", url, options[0])
+	}
+	return r.fetchMethod("HEAD", url, nil)
+}
+
+func (r *retryingAPIRequestContext) Patch(url string, options ...APIRequestContextPatchOptions) (APIResponse, error) {
+	if len(options) == 1 {
+		return r.fetchMethod("PATCH", url, options[0])
+	}
+	return r.fetchMethod("PATCH", url, nil)
+}
+
+func (r *retryingAPIRequestContext) Post(url string, options ...APIRequestContextPostOptions) (APIResponse, error) {
+	if len(options) == 1 {
+		return r.fetchMethod("POST", url, options[0])
+	}
+	return r.fetchMethod("POST", url, nil)
+}
+
+func (r *retryingAPIRequestContext) Put(url string, options ...APIRequestContextPutOptions) (APIResponse, error) {
+	if len(options) == 1 {
+		return r.fetchMethod("PUT", url, options[0])
+	}
+	return r.fetchMethod("PUT", url, nil)
+}
+
+// retrySleep is replaced in tests.
+var retrySleep = time.Sleep
+
+func fetchWithRetry(fetch func(any, ...APIRequestContextFetchOptions) (APIResponse, error), policy RetryPolicy, urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
+	policy = policy.withDefaults()
+	method, url := fetchMethodAndURL(urlOrRequest, options...)
+	retryable := (!*policy.IdempotentOnly || isIdempotentMethod(method)) && !hasStreamBody(options...)
+	start := time.Now()
+	interval := policy.InitialInterval
+	for attempt := 1; ; attempt++ {
+		// Fetch clears fields of its options, so each attempt gets a shallow
+		// copy. Maps and readers are shared, see hasStreamBody.
+		attemptOptions := slices.Clone(options)
+		response, err := fetch(urlOrRequest, attemptOptions...)
+		info := RetryAttempt{
+			Attempt:  attempt,
+			Method:   method,
+			URL:      url,
+			Response: response,
+			Err:      err,
+		}
+		if retryable && attempt < policy.MaxAttempts && policy.shouldRetry(response, err) {
+			delay := policy.backoff(interval)
+			if response != nil && *policy.RespectRetryAfter {
+				if retryAfter, ok := parseRetryAfter(response.Headers()["retry-after"], time.Now()); ok && retryAfter > delay {
+					delay = retryAfter
+				}
+			}
+			elapsed := time.Since(start)
+			if policy.MaxElapsedTime <= 0 || elapsed+delay <= policy.MaxElapsedTime {
+				info.Retry = true
+				info.Delay = delay
+				info.Elapsed = elapsed
+				if policy.OnAttempt != nil {
+					policy.OnAttempt(info)
+				}
+				if response != nil {
+					response.Dispose() //nolint:errcheck
+				}
+				retrySleep(delay)
+				interval = min(time.Duration(float64(interval)*policy.Multiplier), policy.MaxInterval)
+				continue
+			}
+		}
+		info.Elapsed = time.Since(start)
+		if policy.OnAttempt != nil {
+			policy.OnAttempt(info)
+		}
+		return response, err
+	}
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.StatusCodes == nil {
+		p.StatusCodes = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = 500 * time.Millisecond
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = 30 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter == 0 {
+		p.Jitter = 0.5
+	}
+	p.Jitter = min(p.Jitter, 1)
+	if p.RespectRetryAfter == nil {
+		p.RespectRetryAfter = Bool(true)
+	}
+	if p.IdempotentOnly == nil {
+		p.IdempotentOnly = Bool(true)
+	}
+	return p
+}
+
+// hasStreamBody reports whether the body of a fetch is read from an
+// [InputFileStream], which is consumed by the first attempt.
+func hasStreamBody(options ...APIRequestContextFetchOptions) bool {
+	if len(options) == 0 {
+		return false
+	}
+	multipart, ok := options[0].Multipart.(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, value := range multipart {
+		if _, ok := value.(InputFileStream); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) shouldRetry(response APIResponse, err error) bool {
+	if err != nil {
+		return p.RetryErrors && !errors.Is(err, ErrTimeout)
+	}
+	return slices.Contains(p.StatusCodes, response.Status())
+}
+
+// backoff randomizes interval by the jitter of the policy.
+func (p RetryPolicy) backoff(interval time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return interval
+	}
+	delta := p.Jitter * float64(interval)
+	return time.Duration(float64(interval) - delta + rand.Float64()*2*delta)
+}
+
+// parseRetryAfter parses a Retry-After header, given in seconds or as an HTTP
+// date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func fetchMethodAndURL(urlOrRequest any, options ...APIRequestContextFetchOptions) (method, url string) {
+	method = "GET"
+	switch v := urlOrRequest.(type) {
+	case string:
+		url = v
+	case Request:
+		method = v.Method()
+		url = v.URL()
+	}
+	if len(options) == 1 && options[0].Method != nil {
+		method = *options[0].Method
+	}
+	return strings.ToUpper(method), url
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package playwright
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRetryResponse struct {
+	APIResponse
+	status   int
+	headers  map[string]string
+	disposed bool
+}
+
+func (r *fakeRetryResponse) Status() int                { return r.status }
+func (r *fakeRetryResponse) Headers() map[string]string { return r.headers }
+func (r *fakeRetryResponse) Dispose() error {
+	r.disposed = true
+	return nil
+}
+
+// fakeRetryFetch returns results in order and records the options of each call.
+type fakeRetryFetch struct {
+	results []any
+	calls   []APIRequestContextFetchOptions
+}
+
+func (f *fakeRetryFetch) fetch(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
+	opts := APIRequestContextFetchOptions{}
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	f.calls = append(f.calls, opts)
+	result := f.results[len(f.calls)-1]
+	if err, ok := result.(error); ok {
+		return nil, err
+	}
+	return result.(APIResponse), nil
+}
+
+func stubRetrySleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var sleeps []time.Duration
+	original := retrySleep
+	retrySleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	t.Cleanup(func() { retrySleep = original })
+	return &sleeps
+}
+
+func TestFetchWithRetryRetriesStatusCodes(t *testing.T) {
+	sleeps := stubRetrySleep(t)
+	first := &fakeRetryResponse{status: 503}
+	second := &fakeRetryResponse{status: 502}
+	last := &fakeRetryResponse{status: 200}
+	fake := &fakeRetryFetch{results: []any{first, second, last}}
+	var attempts []RetryAttempt
+	policy := RetryPolicy{
+		Jitter:          -1,
+		InitialInterval: 100 * time.Millisecond,
+		OnAttempt:       func(a RetryAttempt) { attempts = append(attempts, a) },
+	}
+
+	response, err := fetchWithRetry(fake.fetch, policy, "https://example.com/items")
+	require.NoError(t, err)
+	require.Same(t, last, response)
+	require.Len(t, fake.calls, 3)
+	require.True(t, first.disposed)
+	require.True(t, second.disposed)
+	require.False(t, last.disposed)
+	require.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *sleeps)
+	require.Len(t, attempts, 3)
+	require.Equal(t, 1, attempts[0].Attempt)
+	require.Equal(t, "GET", attempts[0].Method)
+	require.Equal(t, "https://example.com/items", attempts[0].URL)
+	require.True(t, attempts[0].Retry)
+	require.Equal(t, 100*time.Millisecond, attempts[0].Delay)
+	require.False(t, attempts[2].Retry)
+	require.Same(t, last, attempts[2].Response)
+}
+
+func TestFetchWithRetryStopsAfterMaxAttempts(t *testing.T) {
+	stubRetrySleep(t)
+	last := &fakeRetryResponse{status: 429}
+	fake := &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 429}, last}}
+	response, err := fetchWithRetry(fake.fetch, RetryPolicy{MaxAttempts: 2}, "https://example.com")
+	require.NoError(t, err)
+	require.Same(t, last, response)
+	require.Len(t, fake.calls, 2)
+}
+
+func TestFetchWithRetryIdempotentOnly(t *testing.T) {
+	stubRetrySleep(t)
+	fake := &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 503}}}
+	response, err := fetchWithRetry(fake.fetch, RetryPolicy{}, "https://example.com", APIRequestContextFetchOptions{Method: String("post")})
+	require.NoError(t, err)
+	require.Equal(t, 503, response.Status())
+	require.Len(t, fake.calls, 1)
+
+	fake = &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 503}, &fakeRetryResponse{status: 201}}}
+	response, err = fetchWithRetry(fake.fetch, RetryPolicy{IdempotentOnly: Bool(false)}, "https://example.com", APIRequestContextFetchOptions{Method: String("POST")})
+	require.NoError(t, err)
+	require.Equal(t, 201, response.Status())
+	require.Len(t, fake.calls, 2)
+}
+
+func TestFetchWithRetryErrors(t *testing.T) {
+	stubRetrySleep(t)
+	reset := errors.New("connection reset")
+	fake := &fakeRetryFetch{results: []any{reset}}
+	_, err := fetchWithRetry(fake.fetch, RetryPolicy{}, "https://example.com")
+	require.ErrorIs(t, err, reset)
+	require.Len(t, fake.calls, 1)
+
+	fake = &fakeRetryFetch{results: []any{reset, &fakeRetryResponse{status: 200}}}
+	response, err := fetchWithRetry(fake.fetch, RetryPolicy{RetryErrors: true}, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+
+	fake = &fakeRetryFetch{results: []any{ErrTimeout}}
+	_, err = fetchWithRetry(fake.fetch, RetryPolicy{RetryErrors: true}, "https://example.com")
+	require.ErrorIs(t, err, ErrTimeout)
+	require.Len(t, fake.calls, 1)
+}
+
+func TestFetchWithRetryRespectsRetryAfter(t *testing.T) {
+	sleeps := stubRetrySleep(t)
+	fake := &fakeRetryFetch{results: []any{
+		&fakeRetryResponse{status: 429, headers: map[string]string{"retry-after": "3"}},
+		&fakeRetryResponse{status: 200},
+	}}
+	_, err := fetchWithRetry(fake.fetch, RetryPolicy{Jitter: -1}, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, []time.Duration{3 * time.Second}, *sleeps)
+
+	*sleeps = nil
+	fake = &fakeRetryFetch{results: []any{
+		&fakeRetryResponse{status: 429, headers: map[string]string{"retry-after": "3"}},
+		&fakeRetryResponse{status: 200},
+	}}
+	_, err = fetchWithRetry(fake.fetch, RetryPolicy{Jitter: -1, RespectRetryAfter: Bool(false)}, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, []time.Duration{500 * time.Millisecond}, *sleeps)
+}
+
+func TestFetchWithRetryMaxElapsedTime(t *testing.T) {
+	stubRetrySleep(t)
+	fake := &fakeRetryFetch{results: []any{
+		&fakeRetryResponse{status: 503, headers: map[string]string{"retry-after": "60"}},
+	}}
+	response, err := fetchWithRetry(fake.fetch, RetryPolicy{MaxElapsedTime: 10 * time.Second}, "https://example.com")
+	require.NoError(t, err)
+	require.Equal(t, 503, response.Status())
+	require.Len(t, fake.calls, 1)
+}
+
+func TestFetchWithRetryClonesOptions(t *testing.T) {
+	stubRetrySleep(t)
+	fake := &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 503}, &fakeRetryResponse{status: 200}}}
+	options := APIRequestContextFetchOptions{Method: String("PUT"), Headers: map[string]string{"a": "1"}}
+	_, err := fetchWithRetry(fake.fetch, RetryPolicy{}, "https://example.com", options)
+	require.NoError(t, err)
+	require.Len(t, fake.calls, 2)
+	require.Equal(t, "PUT", *fake.calls[1].Method)
+	require.Equal(t, map[string]string{"a": "1"}, fake.calls[1].Headers)
+}
+
+func TestFetchWithRetrySkipsStreamBodies(t *testing.T) {
+	stubRetrySleep(t)
+	fake := &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 503}}}
+	options := APIRequestContextFetchOptions{
+		Method: String("PUT"),
+		Multipart: map[string]any{
+			"file": InputFileStream{Name: "a.txt", Reader: strings.NewReader("a")},
+		},
+	}
+	response, err := fetchWithRetry(fake.fetch, RetryPolicy{}, "https://example.com", options)
+	require.NoError(t, err)
+	require.Equal(t, 503, response.Status())
+	require.Len(t, fake.calls, 1)
+
+	options.Multipart = map[string]any{"file": InputFile{Name: "a.txt", Buffer: []byte("a")}}
+	fake = &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 503}, &fakeRetryResponse{status: 200}}}
+	_, err = fetchWithRetry(fake.fetch, RetryPolicy{}, "https://example.com", options)
+	require.NoError(t, err)
+	require.Len(t, fake.calls, 2)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{Jitter: 0.5}.withDefaults()
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(time.Second)
+		require.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		require.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+	require.Equal(t, time.Second, RetryPolicy{Jitter: -1}.withDefaults().backoff(time.Second))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	delay, ok := parseRetryAfter("120", now)
+	require.True(t, ok)
+	require.Equal(t, 2*time.Minute, delay)
+
+	delay, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, delay)
+
+	delay, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	require.True(t, ok)
+	require.Zero(t, delay)
+
+	_, ok = parseRetryAfter("", now)
+	require.False(t, ok)
+	_, ok = parseRetryAfter("soon", now)
+	require.False(t, ok)
+}
+
+type fakeRetryContext struct {
+	APIRequestContext
+	fake *fakeRetryFetch
+}
+
+func (c *fakeRetryContext) Fetch(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
+	return c.fake.fetch(urlOrRequest, options...)
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	stubRetrySleep(t)
+	fake := &fakeRetryFetch{results: []any{&fakeRetryResponse{status: 409}, &fakeRetryResponse{status: 200}}}
+	request := WithRetryPolicy(&fakeRetryContext{fake: fake}, RetryPolicy{StatusCodes: []int{409}})
+	response, err := request.Delete("https://example.com/items/1", APIRequestContextDeleteOptions{
+		Headers: map[string]string{"a": "1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+	require.Len(t, fake.calls, 2)
+	require.Equal(t, "DELETE", *fake.calls[1].Method)
+	require.Equal(t, "1", fake.calls[1].Headers["a"])
+
+	require.Error(t, SetRetryPolicy(request, &RetryPolicy{}))
+}
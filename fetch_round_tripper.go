@@ -0,0 +1,202 @@
+package playwright
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RoundTripper returns an [http.RoundTripper] sending requests through
+// request, so an [http.Client] shares its cookies, proxy, client certificates
+// and extra headers:
+//
+//	client := &http.Client{Transport: playwright.RoundTripper(page.Request())}
+//
+// Redirects are not followed by the transport but returned to the client,
+// which applies its CheckRedirect policy. The response body is fetched when it
+// is first read and the APIResponse is disposed when it is closed. Bodies are
+// decoded already, so Content-Encoding and Content-Length are removed and
+// Uncompressed is set, as [http.Transport] does.
+//
+// Neither direction streams: the driver protocol sends bodies in a single
+// message, so the request body is read completely before the request is sent,
+// including chunked uploads, and the response body is held in memory as a
+// whole on first read.
+//
+// The deadline of the request context is used as timeout; without one the
+// request doesn't time out, like with [http.Transport], instead of getting the
+// default timeout of request. When the context is canceled RoundTrip returns,
+// but the browser may still complete the request.
+// Don't set a Jar on the client, the cookies of request are used.
+func RoundTripper(request APIRequestContext) http.RoundTripper {
+	return &apiRoundTripper{request: request}
+}
+
+type apiRoundTripper struct {
+	request APIRequestContext
+}
+
+func (t *apiRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	options, err := fetchOptionsFromHTTP(req)
+	if req.Body != nil {
+		req.Body.Close() //nolint:errcheck
+	}
+	if err != nil {
+		return nil, err
+	}
+	ctx := req.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		response APIResponse
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := t.request.Fetch(req.URL.String(), options)
+		done <- result{response, err}
+	}()
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return httpResponseFromAPI(r.response, req), nil
+	case <-ctx.Done():
+		go func() {
+			if r := <-done; r.err == nil {
+				r.response.Dispose() //nolint:errcheck
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// fetchOptionsFromHTTP converts req into options for [APIRequestContext.Fetch].
+func fetchOptionsFromHTTP(req *http.Request) (APIRequestContextFetchOptions, error) {
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	options := APIRequestContextFetchOptions{
+		Method:           String(method),
+		Headers:          make(map[string]string, len(req.Header)+1),
+		MaxRedirects:     Int(0),
+		FailOnStatusCode: Bool(false),
+	}
+	for name, values := range req.Header {
+		separator := ", "
+		if strings.EqualFold(name, "Cookie") {
+			separator = "; "
+		}
+		options.Headers[name] = strings.Join(values, separator)
+	}
+	if req.Host != "" && req.Host != req.URL.Host {
+		options.Headers["Host"] = req.Host
+	}
+	// A timeout of 0 disables the default timeout of the request context.
+	options.Timeout = Float(0)
+	if deadline, ok := req.Context().Deadline(); ok {
+		timeout := time.Until(deadline)
+		if timeout <= 0 {
+			return options, context.DeadlineExceeded
+		}
+		options.Timeout = Float(float64(timeout.Milliseconds()) + 1)
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		// The driver takes the body in one message, so it can't be streamed.
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return options, fmt.Errorf("could not read request body: %w", err)
+		}
+		if len(body) > 0 {
+			options.Data = body
+		}
+	}
+	return options, nil
+}
+
+// httpResponseFromAPI converts response into an *http.Response whose body is
+// fetched on first read.
+func httpResponseFromAPI(response APIResponse, req *http.Request) *http.Response {
+	header := make(http.Header)
+	for _, h := range response.HeadersArray() {
+		header.Add(h.Name, h.Value)
+	}
+	statusText := response.StatusText()
+	if statusText == "" {
+		statusText = http.StatusText(response.Status())
+	}
+	resp := &http.Response{
+		Status:        strconv.Itoa(response.Status()) + " " + statusText,
+		StatusCode:    response.Status(),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		ContentLength: -1,
+		Request:       req,
+	}
+	if header.Get("Content-Encoding") != "" {
+		header.Del("Content-Encoding")
+		header.Del("Content-Length")
+		resp.Uncompressed = true
+	} else if length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		resp.ContentLength = length
+	}
+	if req.Method == http.MethodHead {
+		resp.Body = http.NoBody
+		response.Dispose() //nolint:errcheck
+		return resp
+	}
+	resp.Body = &apiResponseBody{response: response}
+	return resp
+}
+
+// apiResponseBody reads the body of an APIResponse on first use, as a whole.
+type apiResponseBody struct {
+	response APIResponse
+
+	mu     sync.Mutex
+	reader *bytes.Reader
+	err    error
+	closed bool
+}
+
+func (b *apiResponseBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, errors.New("read on closed response body")
+	}
+	if b.reader == nil && b.err == nil {
+		body, err := b.response.Body()
+		if err != nil {
+			b.err = err
+		} else {
+			b.reader = bytes.NewReader(body)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.reader.Read(p)
+}
+
+func (b *apiResponseBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	return b.response.Dispose()
+}
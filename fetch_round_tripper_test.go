@@ -0,0 +1,126 @@
+package playwright
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRoundTripResponse struct {
+	APIResponse
+	status   int
+	headers  []NameValue
+	body     string
+	reads    int
+	disposed atomic.Bool
+}
+
+func (r *fakeRoundTripResponse) Status() int               { return r.status }
+func (r *fakeRoundTripResponse) StatusText() string        { return "" }
+func (r *fakeRoundTripResponse) HeadersArray() []NameValue { return r.headers }
+func (r *fakeRoundTripResponse) Body() ([]byte, error) {
+	r.reads++
+	return []byte(r.body), nil
+}
+
+func (r *fakeRoundTripResponse) Dispose() error {
+	r.disposed.Store(true)
+	return nil
+}
+
+type fakeRoundTripContext struct {
+	APIRequestContext
+	url      string
+	options  APIRequestContextFetchOptions
+	response *fakeRoundTripResponse
+	block    chan struct{}
+}
+
+func (c *fakeRoundTripContext) Fetch(urlOrRequest any, options ...APIRequestContextFetchOptions) (APIResponse, error) {
+	if c.block != nil {
+		<-c.block
+	}
+	c.url = urlOrRequest.(string)
+	c.options = options[0]
+	return c.response, nil
+}
+
+func TestRoundTripper(t *testing.T) {
+	response := &fakeRoundTripResponse{
+		status: 302,
+		headers: []NameValue{
+			{Name: "Location", Value: "/next"},
+			{Name: "Set-Cookie", Value: "a=1"},
+			{Name: "Set-Cookie", Value: "b=2"},
+			{Name: "Content-Length", Value: "4"},
+		},
+		body: "moved",
+	}
+	fake := &fakeRoundTripContext{response: response}
+	req, err := http.NewRequest("POST", "https://api.example.com/items?x=1", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	req.Header.Add("Accept", "text/plain")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Cookie", "a=1")
+	req.Header.Add("Cookie", "b=2")
+
+	resp, err := RoundTripper(fake).RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, "https://api.example.com/items?x=1", fake.url)
+	require.Equal(t, "POST", *fake.options.Method)
+	require.Equal(t, []byte(`{"a":1}`), fake.options.Data)
+	require.Equal(t, 0, *fake.options.MaxRedirects)
+	require.False(t, *fake.options.FailOnStatusCode)
+	require.Equal(t, 0.0, *fake.options.Timeout)
+	require.Equal(t, "text/plain, application/json", fake.options.Headers["Accept"])
+	require.Equal(t, "a=1; b=2", fake.options.Headers["Cookie"])
+
+	require.Equal(t, 302, resp.StatusCode)
+	require.Equal(t, "302 Found", resp.Status)
+	require.Equal(t, []string{"a=1", "b=2"}, resp.Header.Values("Set-Cookie"))
+	require.Equal(t, int64(4), resp.ContentLength)
+	require.Same(t, req, resp.Request)
+	require.Equal(t, 0, response.reads, "body is read lazily")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "moved", string(body))
+	require.Equal(t, 1, response.reads)
+	require.NoError(t, resp.Body.Close())
+	require.True(t, response.disposed.Load())
+}
+
+func TestRoundTripperDecodedBody(t *testing.T) {
+	fake := &fakeRoundTripContext{response: &fakeRoundTripResponse{
+		status:  200,
+		headers: []NameValue{{Name: "Content-Encoding", Value: "gzip"}, {Name: "Content-Length", Value: "10"}},
+	}}
+	req, err := http.NewRequest("HEAD", "https://example.com/", nil)
+	require.NoError(t, err)
+	resp, err := RoundTripper(fake).RoundTrip(req)
+	require.NoError(t, err)
+	require.True(t, resp.Uncompressed)
+	require.Empty(t, resp.Header.Get("Content-Encoding"))
+	require.Equal(t, int64(-1), resp.ContentLength)
+	require.Equal(t, http.NoBody, resp.Body)
+	require.Nil(t, fake.options.Data)
+}
+
+func TestRoundTripperContext(t *testing.T) {
+	fake := &fakeRoundTripContext{response: &fakeRoundTripResponse{status: 200}, block: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://example.com/", nil)
+	require.NoError(t, err)
+	_, err = RoundTripper(fake).RoundTrip(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	close(fake.block)
+	require.Eventually(t, func() bool { return fake.response.disposed.Load() }, time.Second, time.Millisecond)
+	require.NotNil(t, fake.options.Timeout)
+	require.LessOrEqual(t, *fake.options.Timeout, 21.0)
+}
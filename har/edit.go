@@ -0,0 +1,214 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Predicate reports whether an entry should be kept by [HAR.Filter].
+type Predicate func(entry *Entry) bool
+
+// Filter removes every entry for which keep returns false. Pages that are no
+// longer referenced by any entry are removed as well.
+func (h *HAR) Filter(keep Predicate) {
+	h.Log.Entries = slices.DeleteFunc(h.Log.Entries, func(entry *Entry) bool {
+		return !keep(entry)
+	})
+	referenced := make(map[string]bool)
+	for _, entry := range h.Log.Entries {
+		referenced[entry.Pageref] = true
+	}
+	h.Log.Pages = slices.DeleteFunc(h.Log.Pages, func(page *Page) bool {
+		return !referenced[page.ID]
+	})
+}
+
+// Sort sorts the entries stably using cmp, which follows the [slices.SortFunc]
+// convention.
+func (h *HAR) Sort(cmp func(a, b *Entry) int) {
+	slices.SortStableFunc(h.Log.Entries, cmp)
+}
+
+// SortByStartTime sorts the entries by the time their request was started.
+func (h *HAR) SortByStartTime() {
+	h.Sort(func(a, b *Entry) int {
+		return a.StartedDateTime.Compare(b.StartedDateTime)
+	})
+}
+
+// Clone returns a copy of h sharing no memory with it. Attached resources are
+// copied too and still resolved against the directory h was read from.
+func (h *HAR) Clone() *HAR {
+	clone := deepCopy(h)
+	clone.dir = h.dir
+	clone.resources = nil
+	for name, data := range h.resources {
+		if clone.resources == nil {
+			clone.resources = make(map[string][]byte, len(h.resources))
+		}
+		clone.resources[name] = bytes.Clone(data)
+	}
+	return clone
+}
+
+// Merge combines several archives into a new one. Entries are concatenated and
+// sorted by start time, pages with the same ID are kept once. The creator and
+// browser are taken from the first archive. Entries and pages are copied, so
+// editing the merged archive leaves the inputs unchanged. Attached resources
+// are read into the merged archive, a missing one is returned as an error
+// wrapping [ErrResourceNotFound].
+func Merge(hars ...*HAR) (*HAR, error) {
+	merged := New(Creator{})
+	seenPages := make(map[string]bool)
+	for i, h := range hars {
+		if i == 0 {
+			merged.Log.Version = h.Log.Version
+			merged.Log.Creator = h.Log.Creator
+			if h.Log.Browser != nil {
+				merged.Log.Browser = deepCopy(h.Log.Browser)
+			}
+		}
+		for _, page := range h.Log.Pages {
+			if !seenPages[page.ID] {
+				seenPages[page.ID] = true
+				merged.Log.Pages = append(merged.Log.Pages, deepCopy(page))
+			}
+		}
+		for _, entry := range h.Log.Entries {
+			merged.Log.Entries = append(merged.Log.Entries, deepCopy(entry))
+		}
+		for _, name := range h.attachments() {
+			data, err := h.resource(name)
+			if err != nil {
+				return nil, err
+			}
+			if merged.resources == nil {
+				merged.resources = make(map[string][]byte)
+			}
+			merged.resources[name] = data
+		}
+	}
+	merged.SortByStartTime()
+	return merged, nil
+}
+
+// ByHost keeps entries whose request host, without port, equals one of hosts.
+// A host starting with "*." also matches all of its subdomains.
+func ByHost(hosts ...string) Predicate {
+	return func(entry *Entry) bool {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return false
+		}
+		hostname := strings.ToLower(u.Hostname())
+		for _, host := range hosts {
+			host = strings.ToLower(host)
+			if suffix, ok := strings.CutPrefix(host, "*."); ok {
+				if hostname == suffix || strings.HasSuffix(hostname, "."+suffix) {
+					return true
+				}
+			} else if hostname == host {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByURL keeps entries whose request URL matches one of the [path.Match] patterns,
+// where "*" also matches "/".
+func ByURL(patterns ...string) Predicate {
+	return func(entry *Entry) bool {
+		for _, pattern := range patterns {
+			if globMatch(pattern, entry.Request.URL) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByMethod keeps entries whose request method equals one of methods.
+func ByMethod(methods ...string) Predicate {
+	return func(entry *Entry) bool {
+		for _, method := range methods {
+			if strings.EqualFold(entry.Request.Method, method) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ByResourceType keeps entries recorded with one of the given Playwright resource
+// types, e.g. "document", "xhr" or "fetch".
+func ByResourceType(types ...string) Predicate {
+	return func(entry *Entry) bool {
+		return slices.Contains(types, entry.ResourceType)
+	}
+}
+
+// ByStatus keeps entries whose response status is within [min, max].
+func ByStatus(min, max int) Predicate {
+	return func(entry *Entry) bool {
+		return entry.Response.Status >= min && entry.Response.Status <= max
+	}
+}
+
+// Not inverts a predicate.
+func Not(p Predicate) Predicate {
+	return func(entry *Entry) bool {
+		return !p(entry)
+	}
+}
+
+// And keeps entries matched by all predicates.
+func And(predicates ...Predicate) Predicate {
+	return func(entry *Entry) bool {
+		for _, p := range predicates {
+			if !p(entry) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or keeps entries matched by any of the predicates.
+func Or(predicates ...Predicate) Predicate {
+	return func(entry *Entry) bool {
+		for _, p := range predicates {
+			if p(entry) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// globMatch matches s against a [path.Match] pattern, treating "/" as an ordinary
+// character so "*" spans path segments.
+func globMatch(pattern, s string) bool {
+	const sep = "\x00"
+	matched, err := path.Match(strings.ReplaceAll(pattern, "/", sep), strings.ReplaceAll(s, "/", sep))
+	return err == nil && matched
+}
+
+// deepCopy returns a copy of v sharing no memory with it, by encoding it as
+// JSON, which all types of the archive are made for.
+func deepCopy[T any](v *T) *T {
+	data, err := json.Marshal(v)
+	if err == nil {
+		out := new(T)
+		if err = json.Unmarshal(data, out); err == nil {
+			return out
+		}
+	}
+	// Only values that can't be encoded, like NaN timings, end up here.
+	out := *v
+	return &out
+}
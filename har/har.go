@@ -0,0 +1,233 @@
+// Package har implements the HTTP Archive (HAR) 1.2 format as recorded by
+// Playwright, including its underscore-prefixed extensions. It reads and writes
+// plain .har files as well as .zip archives with attached resources, and offers
+// helpers to filter, sort and merge recordings without starting a browser.
+package har
+
+import (
+	"strings"
+	"time"
+)
+
+// HAR is the root object of an HTTP Archive.
+type HAR struct {
+	Log Log `json:"log"`
+
+	// resources holds the bodies of entries recorded with the "attach" content
+	// policy, keyed by the name referenced from Content.File and PostData.File.
+	resources map[string][]byte
+	// dir is the directory attached resources are resolved against when they are
+	// not in resources, i.e. for .har files recorded next to their resources.
+	dir string
+}
+
+// Log holds all exported data of the archive.
+type Log struct {
+	Version string   `json:"version"`
+	Creator Creator  `json:"creator"`
+	Browser *Browser `json:"browser,omitempty"`
+	Pages   []*Page  `json:"pages,omitempty"`
+	Entries []*Entry `json:"entries"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// Creator describes the application that created the archive.
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Browser describes the browser the archive was recorded with.
+type Browser struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Page describes a page the entries belong to.
+type Page struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	PageTimings     PageTimings `json:"pageTimings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+// PageTimings holds the page load timings in milliseconds, -1 if not available.
+type PageTimings struct {
+	OnContentLoad *float64 `json:"onContentLoad,omitempty"`
+	OnLoad        *float64 `json:"onLoad,omitempty"`
+	Comment       string   `json:"comment,omitempty"`
+}
+
+// Entry is a single recorded request/response pair.
+type Entry struct {
+	Pageref         string    `json:"pageref,omitempty"`
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total elapsed time of the request in milliseconds.
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           Cache    `json:"cache"`
+	Timings         Timings  `json:"timings"`
+	ServerIPAddress string   `json:"serverIPAddress,omitempty"`
+	Connection      string   `json:"connection,omitempty"`
+	Comment         string   `json:"comment,omitempty"`
+
+	// Playwright extensions.
+	ResourceType    string           `json:"_resourceType,omitempty"`
+	FrameRef        string           `json:"_frameref,omitempty"`
+	MonotonicTime   *float64         `json:"_monotonicTime,omitempty"`
+	ServerPort      *int             `json:"_serverPort,omitempty"`
+	SecurityDetails *SecurityDetails `json:"_securityDetails,omitempty"`
+	WasAborted      *bool            `json:"_wasAborted,omitempty"`
+	WasFulfilled    *bool            `json:"_wasFulfilled,omitempty"`
+	WasContinued    *bool            `json:"_wasContinued,omitempty"`
+	APIRequest      *bool            `json:"_apiRequest,omitempty"`
+}
+
+// Request describes the performed request.
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+	Comment     string      `json:"comment,omitempty"`
+}
+
+// Header returns the value of the first request header matching name
+// case-insensitively.
+func (r *Request) Header(name string) string {
+	return headerValue(r.Headers, name)
+}
+
+// Response describes the received response.
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+	Comment     string      `json:"comment,omitempty"`
+
+	// Playwright extensions.
+	TransferSize *int64 `json:"_transferSize,omitempty"`
+	FailureText  string `json:"_failureText,omitempty"`
+}
+
+// Header returns the value of the first response header matching name
+// case-insensitively.
+func (r *Response) Header(name string) string {
+	return headerValue(r.Headers, name)
+}
+
+// Cookie is a request or response cookie.
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly *bool  `json:"httpOnly,omitempty"`
+	Secure   *bool  `json:"secure,omitempty"`
+	SameSite string `json:"sameSite,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// NameValue is a header or query string parameter.
+type NameValue struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// PostData describes the posted request body.
+type PostData struct {
+	MimeType string  `json:"mimeType"`
+	Params   []Param `json:"params"`
+	Text     string  `json:"text"`
+	Comment  string  `json:"comment,omitempty"`
+
+	// Playwright extensions, set when the body is attached instead of embedded.
+	SHA1 string `json:"_sha1,omitempty"`
+	File string `json:"_file,omitempty"`
+}
+
+// Param is a posted form parameter.
+type Param struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// Content describes the response body.
+type Content struct {
+	Size        int64  `json:"size"`
+	Compression *int64 `json:"compression,omitempty"`
+	MimeType    string `json:"mimeType"`
+	Text        string `json:"text,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+
+	// Playwright extensions, set when the body is attached instead of embedded.
+	SHA1 string `json:"_sha1,omitempty"`
+	File string `json:"_file,omitempty"`
+}
+
+// Cache describes the cache state before and after the request.
+type Cache struct {
+	BeforeRequest *CacheState `json:"beforeRequest,omitempty"`
+	AfterRequest  *CacheState `json:"afterRequest,omitempty"`
+	Comment       string      `json:"comment,omitempty"`
+}
+
+// CacheState describes a cache entry.
+type CacheState struct {
+	Expires    string `json:"expires,omitempty"`
+	LastAccess string `json:"lastAccess"`
+	ETag       string `json:"eTag"`
+	HitCount   int    `json:"hitCount"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// Timings holds the request phase timings in milliseconds, -1 if not available.
+type Timings struct {
+	Blocked *float64 `json:"blocked,omitempty"`
+	DNS     *float64 `json:"dns,omitempty"`
+	Connect *float64 `json:"connect,omitempty"`
+	Send    float64  `json:"send"`
+	Wait    float64  `json:"wait"`
+	Receive float64  `json:"receive"`
+	SSL     *float64 `json:"ssl,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// SecurityDetails holds the TLS details of the connection.
+type SecurityDetails struct {
+	Protocol    string   `json:"protocol,omitempty"`
+	SubjectName string   `json:"subjectName,omitempty"`
+	Issuer      string   `json:"issuer,omitempty"`
+	ValidFrom   *float64 `json:"validFrom,omitempty"`
+	ValidTo     *float64 `json:"validTo,omitempty"`
+}
+
+func headerValue(headers []NameValue, name string) string {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,225 @@
+package har
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	h, err := ReadFile(filepath.Join("..", "tests", "assets", "har-fulfill.har"))
+	require.NoError(t, err)
+	require.Equal(t, "1.2", h.Log.Version)
+	require.Equal(t, "Playwright", h.Log.Creator.Name)
+	require.NotEmpty(t, h.Log.Entries)
+	entry := h.Log.Entries[0]
+	require.Equal(t, "GET", entry.Request.Method)
+	require.Equal(t, "http://no.playwright/", entry.Request.URL)
+	body, err := h.Body(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "<title>Hey</title>")
+}
+
+func TestBodyFromAttachedFile(t *testing.T) {
+	h, err := ReadFile(filepath.Join("..", "tests", "assets", "har-sha1.har"))
+	require.NoError(t, err)
+	body, err := h.Body(h.Log.Entries[0])
+	require.NoError(t, err)
+	require.Contains(t, string(body), "Hello, world")
+
+	h.Log.Entries[0].Response.Content.File = "missing.txt"
+	_, err = h.Body(h.Log.Entries[0])
+	require.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestWriteFileZipRoundTrip(t *testing.T) {
+	h := New(Creator{Name: "test", Version: "1"})
+	h.Log.Pages = []*Page{{ID: "page@1"}}
+	attached := newEntry("https://example.com/app.js", "application/javascript", time.Unix(2, 0))
+	attached.Pageref = "page@1"
+	attached.Response.Content.File = "placeholder.js"
+	h.SetBody(attached, []byte("console.log(1)"))
+	embedded := newEntry("https://example.com/logo.png", "image/png", time.Unix(1, 0))
+	h.SetBody(embedded, []byte{0x89, 'P', 'N', 'G'})
+	h.Log.Entries = append(h.Log.Entries, attached, embedded)
+
+	require.Equal(t, "base64", embedded.Response.Content.Encoding)
+	require.Equal(t, int64(4), embedded.Response.Content.Size)
+	require.NotEqual(t, "placeholder.js", attached.Response.Content.File)
+	require.Equal(t, ".js", filepath.Ext(attached.Response.Content.File))
+
+	path := filepath.Join(t.TempDir(), "out.zip")
+	require.NoError(t, h.WriteFile(path))
+	read, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, read.Log.Entries, 2)
+	body, err := read.Body(read.Log.Entries[0])
+	require.NoError(t, err)
+	require.Equal(t, "console.log(1)", string(body))
+	body, err = read.Body(read.Log.Entries[1])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x89, 'P', 'N', 'G'}, body)
+
+	require.NoError(t, read.Embed())
+	require.Empty(t, read.Log.Entries[0].Response.Content.File)
+	require.Equal(t, "console.log(1)", read.Log.Entries[0].Response.Content.Text)
+	var buf bytes.Buffer
+	require.NoError(t, read.Write(&buf))
+	again, err := Read(&buf)
+	require.NoError(t, err)
+	body, err = again.Body(again.Log.Entries[0])
+	require.NoError(t, err)
+	require.Equal(t, "console.log(1)", string(body))
+}
+
+func TestWriteFileHarWithAttachments(t *testing.T) {
+	h := New(Creator{Name: "test"})
+	entry := newEntry("https://example.com/", "text/html", time.Unix(0, 0))
+	entry.Response.Content.File = "x.html"
+	h.SetBody(entry, []byte("<p>hi</p>"))
+	h.Log.Entries = append(h.Log.Entries, entry)
+
+	path := filepath.Join(t.TempDir(), "nested", "out.har")
+	require.NoError(t, h.WriteFile(path))
+	read, err := ReadFile(path)
+	require.NoError(t, err)
+	body, err := read.Body(read.Log.Entries[0])
+	require.NoError(t, err)
+	require.Equal(t, "<p>hi</p>", string(body))
+}
+
+func TestFilterSortMerge(t *testing.T) {
+	a := New(Creator{Name: "a"})
+	a.Log.Pages = []*Page{{ID: "page@1"}, {ID: "page@2"}}
+	tracker := newEntry("https://tracker.ads.com/pixel", "image/gif", time.Unix(3, 0))
+	tracker.Pageref = "page@2"
+	doc := newEntry("https://example.com/", "text/html", time.Unix(1, 0))
+	doc.Pageref = "page@1"
+	a.Log.Entries = []*Entry{tracker, doc}
+
+	b := New(Creator{Name: "b"})
+	b.Log.Pages = []*Page{{ID: "page@1"}}
+	api := newEntry("https://api.example.com/items", "application/json", time.Unix(2, 0))
+	api.Request.Method = "POST"
+	api.Response.Status = 500
+	api.Pageref = "page@1"
+	b.Log.Entries = []*Entry{api}
+
+	merged, err := Merge(a, b)
+	require.NoError(t, err)
+	require.Equal(t, "a", merged.Log.Creator.Name)
+	require.Len(t, merged.Log.Pages, 2)
+	require.Equal(t, []string{doc.Request.URL, api.Request.URL, tracker.Request.URL}, urls(merged))
+
+	merged.Filter(Not(ByHost("*.ads.com")))
+	require.Equal(t, []string{doc.Request.URL, api.Request.URL}, urls(merged))
+	require.Len(t, merged.Log.Pages, 1)
+
+	merged.Sort(func(x, y *Entry) int { return y.StartedDateTime.Compare(x.StartedDateTime) })
+	require.Equal(t, []string{api.Request.URL, doc.Request.URL}, urls(merged))
+
+	require.True(t, And(ByMethod("post"), ByStatus(500, 599))(api))
+	require.False(t, And(ByMethod("post"), ByStatus(500, 599))(doc))
+	require.True(t, Or(ByMethod("post"), ByHost("example.com"))(doc))
+	require.True(t, ByURL("https://*.example.com/*")(api))
+	require.False(t, ByURL("https://*.example.com/*")(doc))
+	require.True(t, ByURL("https://example.com/*")(doc))
+}
+
+func TestMergeCopiesEntries(t *testing.T) {
+	a := New(Creator{Name: "a"})
+	a.Log.Pages = []*Page{{ID: "page@1", Title: "Home"}}
+	entry := newEntry("https://example.com/", "text/html", time.Unix(1, 0))
+	entry.Request.Headers = []NameValue{{Name: "Cookie", Value: "secret"}}
+	a.Log.Entries = []*Entry{entry}
+
+	merged, err := Merge(a)
+	require.NoError(t, err)
+	merged.Log.Entries[0].Request.URL = "https://example.com/changed"
+	merged.Log.Entries[0].Request.Headers[0].Value = "redacted"
+	merged.Log.Pages[0].Title = "Changed"
+	require.Equal(t, "https://example.com/", entry.Request.URL)
+	require.Equal(t, "secret", entry.Request.Headers[0].Value)
+	require.Equal(t, "Home", a.Log.Pages[0].Title)
+	require.True(t, entry.StartedDateTime.Equal(merged.Log.Entries[0].StartedDateTime))
+}
+
+func TestMergeMissingAttachment(t *testing.T) {
+	a := New(Creator{Name: "a"})
+	a.dir = t.TempDir()
+	entry := newEntry("https://example.com/", "text/html", time.Unix(1, 0))
+	entry.Response.Content.File = "missing.html"
+	a.Log.Entries = []*Entry{entry}
+	_, err := Merge(a)
+	require.ErrorIs(t, err, ErrResourceNotFound)
+}
+
+func TestClone(t *testing.T) {
+	h := New(Creator{Name: "a"})
+	entry := newEntry("https://example.com/", "text/html", time.Unix(1, 0))
+	h.Log.Entries = []*Entry{entry}
+	entry.Response.Content.File = "body.bin"
+	h.SetBody(entry, []byte(strings.Repeat("\x00", 10)))
+	require.NotEmpty(t, entry.Response.Content.File)
+
+	clone := h.Clone()
+	require.NoError(t, clone.Embed())
+	require.Empty(t, clone.Log.Entries[0].Response.Content.File)
+	require.NotEmpty(t, entry.Response.Content.File)
+	body, err := h.Body(entry)
+	require.NoError(t, err)
+	require.Equal(t, []byte(strings.Repeat("\x00", 10)), body)
+	body, err = clone.Body(clone.Log.Entries[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte(strings.Repeat("\x00", 10)), body)
+}
+
+func TestWriteFileRejectsAttachmentPaths(t *testing.T) {
+	dir := t.TempDir()
+	h := New(Creator{Name: "test"})
+	entry := newEntry("https://example.com/", "text/html", time.Unix(0, 0))
+	entry.Response.Content.File = "../escaped.html"
+	h.resources = map[string][]byte{"../escaped.html": []byte("<p>hi</p>")}
+	h.Log.Entries = append(h.Log.Entries, entry)
+
+	for _, name := range []string{"out.har", "out.zip"} {
+		err := h.WriteFile(filepath.Join(dir, "nested", name))
+		require.ErrorContains(t, err, "invalid attachment name")
+	}
+	require.NoFileExists(t, filepath.Join(dir, "escaped.html"))
+	require.NoDirExists(t, filepath.Join(dir, "nested"))
+}
+
+func TestHeader(t *testing.T) {
+	entry := newEntry("https://example.com/", "text/plain", time.Unix(0, 0))
+	entry.Response.Headers = []NameValue{{Name: "Content-Type", Value: "text/plain"}}
+	require.Equal(t, "text/plain", entry.Response.Header("content-type"))
+	require.Empty(t, entry.Request.Header("cookie"))
+}
+
+func newEntry(url, mimeType string, started time.Time) *Entry {
+	return &Entry{
+		StartedDateTime: started,
+		Request:         Request{Method: "GET", URL: url, HTTPVersion: "HTTP/1.1", HeadersSize: -1, BodySize: -1},
+		Response: Response{
+			Status:      200,
+			StatusText:  "OK",
+			HTTPVersion: "HTTP/1.1",
+			Content:     Content{MimeType: mimeType},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	}
+}
+
+func urls(h *HAR) []string {
+	out := make([]string, len(h.Log.Entries))
+	for i, entry := range h.Log.Entries {
+		out[i] = entry.Request.URL
+	}
+	return out
+}
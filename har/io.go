@@ -0,0 +1,343 @@
+package har
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrResourceNotFound is returned by [HAR.Body] when an attached resource is
+// neither part of the archive nor present on disk.
+var ErrResourceNotFound = errors.New("har: attached resource not found")
+
+// harEntryName is the name of the HAR document inside a .zip archive.
+const harEntryName = "har.har"
+
+// New returns an empty archive created by the given application.
+func New(creator Creator) *HAR {
+	return &HAR{
+		Log: Log{
+			Version: "1.2",
+			Creator: creator,
+			Entries: []*Entry{},
+		},
+	}
+}
+
+// Read parses a HAR document from r. Attached resources are resolved against the
+// current working directory.
+func Read(r io.Reader) (*HAR, error) {
+	h := &HAR{}
+	if err := json.NewDecoder(r).Decode(h); err != nil {
+		return nil, fmt.Errorf("har: could not parse: %w", err)
+	}
+	return h, nil
+}
+
+// ReadFile reads a .har file, or a .zip archive as written by Playwright when
+// recording to a path ending in .zip. Resources attached to a .har file are
+// resolved relative to its directory.
+func ReadFile(path string) (*HAR, error) {
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		return readZip(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+	h, err := Read(f)
+	if err != nil {
+		return nil, err
+	}
+	h.dir = filepath.Dir(path)
+	return h, nil
+}
+
+func readZip(path string) (*HAR, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close() //nolint:errcheck
+	var h *HAR
+	resources := make(map[string][]byte)
+	for _, file := range archive.File {
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if h == nil && strings.HasSuffix(file.Name, ".har") {
+			if h, err = Read(bytes.NewReader(data)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		resources[file.Name] = data
+	}
+	if h == nil {
+		return nil, fmt.Errorf("har: no .har file in %s", path)
+	}
+	h.resources = resources
+	return h, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() //nolint:errcheck
+	return io.ReadAll(rc)
+}
+
+// Write encodes the HAR document to w. Attached resources are not written; use
+// [HAR.WriteFile] with a .zip path to keep them, or [HAR.Embed] them first.
+func (h *HAR) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(h)
+}
+
+// WriteFile writes the archive to path. A path ending in .zip produces a zip
+// archive holding the document and every attached resource, which
+// BrowserContext.RouteFromHAR can replay. Otherwise the document is written as
+// JSON and attached resources are written next to it. Attachments must be
+// referenced by plain file names, names with a directory are rejected.
+func (h *HAR) WriteFile(path string) error {
+	for _, name := range h.attachments() {
+		if err := checkAttachmentName(name); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+	if !strings.HasSuffix(strings.ToLower(path), ".zip") {
+		if err := h.Write(f); err != nil {
+			return err
+		}
+		dir := filepath.Dir(path)
+		for _, name := range h.attachments() {
+			if _, ok := h.resources[name]; !ok && filepath.Clean(h.dir) == filepath.Clean(dir) {
+				// Already on disk next to the document.
+				continue
+			}
+			data, err := h.resource(name)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+				return err
+			}
+		}
+		return f.Close()
+	}
+	archive := zip.NewWriter(f)
+	w, err := archive.Create(harEntryName)
+	if err != nil {
+		return err
+	}
+	if err := h.Write(w); err != nil {
+		return err
+	}
+	for _, name := range h.attachments() {
+		data, err := h.resource(name)
+		if err != nil {
+			return err
+		}
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Body returns the decoded response body of entry, reading attached resources
+// from the archive or from disk.
+func (h *HAR) Body(entry *Entry) ([]byte, error) {
+	content := &entry.Response.Content
+	if content.File != "" {
+		return h.resource(content.File)
+	}
+	if content.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(content.Text)
+	}
+	return []byte(content.Text), nil
+}
+
+// SetBody replaces the response body of entry. Textual MIME types are embedded as
+// text, everything else as base64. If the body was attached it stays attached
+// under a new name derived from its SHA-1. The size fields are updated.
+func (h *HAR) SetBody(entry *Entry, body []byte) {
+	content := &entry.Response.Content
+	content.Size = int64(len(body))
+	content.Compression = nil
+	if content.File != "" {
+		name := sha1Hex(body) + filepath.Ext(content.File)
+		if h.resources == nil {
+			h.resources = make(map[string][]byte)
+		}
+		h.resources[name] = body
+		content.File = name
+		content.Text = ""
+		content.Encoding = ""
+	} else if isTextMimeType(content.MimeType) {
+		content.Text = string(body)
+		content.Encoding = ""
+	} else {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+		content.Encoding = "base64"
+	}
+	if entry.Response.BodySize >= 0 {
+		entry.Response.BodySize = int64(len(body))
+	}
+}
+
+// PostDataBody returns the request body of entry, or nil if it has none.
+func (h *HAR) PostDataBody(entry *Entry) ([]byte, error) {
+	postData := entry.Request.PostData
+	if postData == nil {
+		return nil, nil
+	}
+	if postData.File != "" {
+		return h.resource(postData.File)
+	}
+	return []byte(postData.Text), nil
+}
+
+// SetPostDataBody replaces the request body of entry, keeping it attached if it
+// was. It does nothing if the entry has no request body.
+func (h *HAR) SetPostDataBody(entry *Entry, body []byte) {
+	postData := entry.Request.PostData
+	if postData == nil {
+		return
+	}
+	if postData.File != "" {
+		name := sha1Hex(body) + filepath.Ext(postData.File)
+		if h.resources == nil {
+			h.resources = make(map[string][]byte)
+		}
+		h.resources[name] = body
+		postData.File = name
+	} else {
+		postData.Text = string(body)
+	}
+	if entry.Request.BodySize >= 0 {
+		entry.Request.BodySize = int64(len(body))
+	}
+}
+
+// Embed inlines every attached resource into its entry so the document is
+// self-contained.
+func (h *HAR) Embed() error {
+	for _, entry := range h.Log.Entries {
+		if entry.Response.Content.File != "" {
+			body, err := h.resource(entry.Response.Content.File)
+			if err != nil {
+				return err
+			}
+			entry.Response.Content.File = ""
+			h.SetBody(entry, body)
+		}
+		if postData := entry.Request.PostData; postData != nil && postData.File != "" {
+			body, err := h.resource(postData.File)
+			if err != nil {
+				return err
+			}
+			postData.File = ""
+			postData.Text = string(body)
+		}
+	}
+	return nil
+}
+
+func (h *HAR) resource(name string) ([]byte, error) {
+	if data, ok := h.resources[name]; ok {
+		return data, nil
+	}
+	dir := h.dir
+	if dir == "" {
+		dir = "."
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.Base(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, name)
+	}
+	return data, err
+}
+
+// checkAttachmentName rejects names of attached resources that are not plain
+// file names, which could be written outside the directory of the archive.
+func checkAttachmentName(name string) error {
+	if name == "." || name == ".." || name != filepath.Base(name) || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("har: invalid attachment name %q", name)
+	}
+	return nil
+}
+
+// attachments returns the names of all resources referenced by entries.
+func (h *HAR) attachments() []string {
+	seen := make(map[string]bool)
+	names := []string{}
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, entry := range h.Log.Entries {
+		add(entry.Response.Content.File)
+		if entry.Request.PostData != nil {
+			add(entry.Request.PostData.File)
+		}
+	}
+	return names
+}
+
+func sha1Hex(data []byte) string {
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func isTextMimeType(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		mediaType = strings.ToLower(mimeType)
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml",
+		"application/x-www-form-urlencoded", "image/svg+xml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package playwright
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// InputFileStream is a file whose content is read from Reader. It can be passed
+// to [Locator.SetInputFiles], which streams it to the browser in chunks, so
+// large files don't have to be held in memory. It can also be a field of
+// [APIRequestContextFetchOptions.Multipart], but multipart fields are fully
+// buffered: the driver protocol sends them base64 encoded in a single message.
+//
+// The browser derives the type of files set as input files from the extension
+// of Name, MimeType is only sent for multipart fields. Reader is read once and
+// closed afterwards if it implements [io.Closer].
+type InputFileStream struct {
+	Name     string
+	MimeType string
+	Reader   io.Reader
+	// Reported as lastModified of the file in the page, if not zero.
+	LastModified time.Time
+}
+
+// InputFilesFS selects files of FS for [Locator.SetInputFiles]. Paths are
+// either files or a single directory, which is uploaded with all files below it
+// to an input with the webkitdirectory attribute. Files are opened one by one
+// while they are uploaded.
+type InputFilesFS struct {
+	FS    fs.FS
+	Paths []string
+}
+
+// InputFileFromFS returns the file name of fsys as an [InputFileStream], with
+// the MIME type guessed from its extension. The file is only opened when it is
+// read, e.g. to upload a fixture embedded with embed.FS:
+//
+//	file, err := playwright.InputFileFromFS(fixtures, "fixtures/video.mp4")
+//	err = page.Locator("input[type=file]").SetInputFiles(file)
+func InputFileFromFS(fsys fs.FS, name string) (InputFileStream, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return InputFileStream{}, err
+	}
+	if info.IsDir() {
+		return InputFileStream{}, fmt.Errorf("%s is a directory, use InputFilesFS to upload directories", name)
+	}
+	return InputFileStream{
+		Name:         path.Base(name),
+		MimeType:     mime.TypeByExtension(path.Ext(name)),
+		Reader:       &fsFileReader{fsys: fsys, name: name},
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (f InputFileStream) upload() inputFileUpload {
+	return inputFileUpload{
+		item: fileItem{Name: f.Name, LastModifiedMs: lastModifiedMs(f.LastModified)},
+		open: f.open,
+	}
+}
+
+func (f InputFileStream) open() (io.ReadCloser, error) {
+	if f.Reader == nil {
+		return nil, fmt.Errorf("input file %q has no reader", f.Name)
+	}
+	if closer, ok := f.Reader.(io.ReadCloser); ok {
+		return closer, nil
+	}
+	return io.NopCloser(f.Reader), nil
+}
+
+// multipartFile reads the file into a multipart field, encoding it while
+// reading. The driver protocol sends multipart bodies in a single message.
+func (f InputFileStream) multipartFile() (map[string]string, error) {
+	reader, err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() //nolint:errcheck
+	var encoded strings.Builder
+	encoder := base64.NewEncoder(base64.StdEncoding, &encoded)
+	if _, err := io.Copy(encoder, reader); err != nil {
+		return nil, fmt.Errorf("could not read multipart file %q: %w", f.Name, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"name":     f.Name,
+		"mimeType": f.MimeType,
+		"buffer":   encoded.String(),
+	}, nil
+}
+
+// uploads resolves the paths into files to upload and, for a directory, the
+// name of the directory.
+func (f InputFilesFS) uploads() (*string, []inputFileUpload, error) {
+	if f.FS == nil {
+		return nil, nil, errors.New("InputFilesFS needs a file system")
+	}
+	var (
+		uploads []inputFileUpload
+		dir     *string
+	)
+	for _, name := range f.Paths {
+		info, err := fs.Stat(f.FS, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !info.IsDir() {
+			uploads = append(uploads, f.upload(name, path.Base(name), info))
+			continue
+		}
+		if dir != nil {
+			return nil, nil, errors.New("Multiple directories are not supported")
+		}
+		if name == "." {
+			return nil, nil, errors.New("the root of a file system can't be uploaded as a directory, use fs.Sub to select its parent")
+		}
+		dir = String(name)
+	}
+	if dir == nil {
+		return nil, uploads, nil
+	}
+	if len(uploads) > 0 {
+		return nil, nil, errors.New("File paths must be all files or a single directory")
+	}
+	err := fs.WalkDir(f.FS, *dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		uploads = append(uploads, f.upload(name, strings.TrimPrefix(name, *dir+"/"), info))
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return String(path.Base(*dir)), uploads, nil
+}
+
+func (f InputFilesFS) upload(name, uploadName string, info fs.FileInfo) inputFileUpload {
+	return inputFileUpload{
+		item: fileItem{Name: uploadName, LastModifiedMs: lastModifiedMs(info.ModTime())},
+		open: func() (io.ReadCloser, error) {
+			return f.FS.Open(name)
+		},
+	}
+}
+
+func lastModifiedMs(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	ms := t.UnixMilli()
+	return &ms
+}
+
+// fsFileReader opens a file of a file system on first read.
+type fsFileReader struct {
+	fsys fs.FS
+	name string
+	file fs.File
+}
+
+func (r *fsFileReader) Read(p []byte) (int, error) {
+	if r.file == nil {
+		file, err := r.fsys.Open(r.name)
+		if err != nil {
+			return 0, err
+		}
+		r.file = file
+	}
+	return r.file.Read(p)
+}
+
+func (r *fsFileReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,101 @@
+package playwright
+
+import (
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestInputFileStreamMultipartFile(t *testing.T) {
+	reader := &closeRecorder{Reader: strings.NewReader("hello world")}
+	file, err := InputFileStream{Name: "a.txt", MimeType: "text/plain", Reader: reader}.multipartFile()
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"name":     "a.txt",
+		"mimeType": "text/plain",
+		"buffer":   base64.StdEncoding.EncodeToString([]byte("hello world")),
+	}, file)
+	require.True(t, reader.closed)
+
+	_, err = InputFileStream{Name: "b.txt"}.multipartFile()
+	require.ErrorContains(t, err, "has no reader")
+}
+
+func TestInputFileFromFS(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"fixtures/video.mp4": {Data: []byte("video"), ModTime: modified},
+		"fixtures/dir/a.txt": {Data: []byte("a")},
+	}
+	file, err := InputFileFromFS(fsys, "fixtures/video.mp4")
+	require.NoError(t, err)
+	require.Equal(t, "video.mp4", file.Name)
+	require.Equal(t, "video/mp4", file.MimeType)
+	require.Equal(t, modified, file.LastModified)
+	require.Equal(t, modified.UnixMilli(), *file.upload().item.LastModifiedMs)
+	content, err := io.ReadAll(file.Reader)
+	require.NoError(t, err)
+	require.Equal(t, "video", string(content))
+	require.NoError(t, file.Reader.(io.Closer).Close())
+
+	_, err = InputFileFromFS(fsys, "fixtures/dir")
+	require.ErrorContains(t, err, "is a directory")
+	_, err = InputFileFromFS(fsys, "missing.txt")
+	require.Error(t, err)
+}
+
+func TestInputFilesFSUploads(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fixtures/a.txt":          {Data: []byte("a")},
+		"fixtures/b.txt":          {Data: []byte("b")},
+		"fixtures/nested/c.txt":   {Data: []byte("c")},
+		"fixtures/nested/d/e.txt": {Data: []byte("e")},
+	}
+
+	rootDirName, uploads, err := InputFilesFS{FS: fsys, Paths: []string{"fixtures/a.txt", "fixtures/nested/c.txt"}}.uploads()
+	require.NoError(t, err)
+	require.Nil(t, rootDirName)
+	require.Len(t, uploads, 2)
+	require.Equal(t, "a.txt", uploads[0].item.Name)
+	require.Equal(t, "c.txt", uploads[1].item.Name)
+	require.Nil(t, uploads[0].item.LastModifiedMs)
+	reader, err := uploads[1].open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "c", string(content))
+	require.NoError(t, reader.Close())
+
+	rootDirName, uploads, err = InputFilesFS{FS: fsys, Paths: []string{"fixtures/nested"}}.uploads()
+	require.NoError(t, err)
+	require.Equal(t, "nested", *rootDirName)
+	names := []string{}
+	for _, upload := range uploads {
+		names = append(names, upload.item.Name)
+	}
+	require.Equal(t, []string{"c.txt", "d/e.txt"}, names)
+
+	_, _, err = InputFilesFS{FS: fsys, Paths: []string{"fixtures/nested", "fixtures/a.txt"}}.uploads()
+	require.ErrorContains(t, err, "all files or a single directory")
+	_, _, err = InputFilesFS{FS: fsys, Paths: []string{"fixtures/nested", "fixtures/nested/d"}}.uploads()
+	require.ErrorContains(t, err, "Multiple directories")
+	_, _, err = InputFilesFS{FS: fsys, Paths: []string{"."}}.uploads()
+	require.ErrorContains(t, err, "fs.Sub")
+	_, _, err = InputFilesFS{Paths: []string{"a.txt"}}.uploads()
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -29,6 +30,7 @@ type fileItem struct {
 // convertInputFiles converts files to proper format for Playwright
 //
 //   - files should be one of: string, []string, InputFile, []InputFile,
+//     InputFileStream, []InputFileStream, InputFilesFS
 //     string: local file path
 func convertInputFiles(files any, context *browserContextImpl) (*inputFiles, error) {
 	var (
@@ -50,12 +52,26 @@ func convertInputFiles(files any, context *browserContextImpl) (*inputFiles, err
 		}
 		converted.Payloads = normalizeFilePayloads(items)
 		return converted, nil
+	case InputFileStream:
+		return uploadInputFiles(context, nil, []inputFileUpload{items.upload()})
+	case []InputFileStream:
+		uploads := make([]inputFileUpload, 0, len(items))
+		for _, item := range items {
+			uploads = append(uploads, item.upload())
+		}
+		return uploadInputFiles(context, nil, uploads)
+	case InputFilesFS:
+		rootDirName, uploads, err := items.uploads()
+		if err != nil {
+			return nil, err
+		}
+		return uploadInputFiles(context, rootDirName, uploads)
 	case string: // local file path
 		paths = []string{items}
 	case []string:
 		paths = items
 	default:
-		return nil, errors.New("files should be one of: string, []string, InputFile, []InputFile")
+		return nil, errors.New("files should be one of: string, []string, InputFile, []InputFile, InputFileStream, []InputFileStream, InputFilesFS")
 	}
 
 	localPaths, localDir, err := resolvePathsAndDirectoryForInputFiles(paths)
@@ -70,17 +86,16 @@ func convertInputFiles(files any, context *browserContextImpl) (*inputFiles, err
 	}
 
 	// remote
-	params := map[string]any{
-		"items": []fileItem{},
-	}
+	var rootDirName *string
 	allFiles := localPaths
 	if localDir != nil {
-		params["rootDirName"] = filepath.Base(*localDir)
+		rootDirName = String(filepath.Base(*localDir))
 		allFiles, err = listFiles(*localDir)
 		if err != nil {
 			return nil, err
 		}
 	}
+	uploads := make([]inputFileUpload, 0, len(allFiles))
 	for _, file := range allFiles {
 		lastModifiedMs, err := getFileLastModifiedMs(file)
 		if err != nil {
@@ -94,11 +109,38 @@ func convertInputFiles(files any, context *browserContextImpl) (*inputFiles, err
 				return nil, err
 			}
 		}
-		params["items"] = append(params["items"].([]fileItem), fileItem{
-			LastModifiedMs: &lastModifiedMs,
-			Name:           filename,
+		uploads = append(uploads, inputFileUpload{
+			item: fileItem{
+				LastModifiedMs: &lastModifiedMs,
+				Name:           filename,
+			},
+			open: func() (io.ReadCloser, error) {
+				return os.Open(file)
+			},
 		})
 	}
+	return uploadInputFiles(context, rootDirName, uploads)
+}
+
+// inputFileUpload is a file streamed to the driver by uploadInputFiles.
+type inputFileUpload struct {
+	item fileItem
+	open func() (io.ReadCloser, error)
+}
+
+// uploadInputFiles streams files into temporary files created by the driver,
+// in a directory named rootDirName if it is not nil.
+func uploadInputFiles(context *browserContextImpl, rootDirName *string, files []inputFileUpload) (*inputFiles, error) {
+	items := make([]fileItem, 0, len(files))
+	for _, file := range files {
+		items = append(items, file.item)
+	}
+	params := map[string]any{
+		"items": items,
+	}
+	if rootDirName != nil {
+		params["rootDirName"] = *rootDirName
+	}
 
 	ret, err := context.connection.WrapAPICall(func() (any, error) {
 		return context.channel.SendReturnAsDict("createTempFiles", params)
@@ -108,12 +150,19 @@ func convertInputFiles(files any, context *browserContextImpl) (*inputFiles, err
 	}
 	result := ret.(map[string]any)
 
+	converted := &inputFiles{}
 	streams := make([]*channel, 0)
-	items := result["writableStreams"].([]any)
-	for i := 0; i < len(allFiles); i++ {
-		stream := fromChannel(items[i]).(*writableStream)
-		if err := stream.Copy(allFiles[i]); err != nil {
-			return nil, err
+	writableStreams := result["writableStreams"].([]any)
+	for i, file := range files {
+		stream := fromChannel(writableStreams[i]).(*writableStream)
+		if err := copyInputFile(stream, file); err != nil {
+			// The streams of this and the following files are still open in the
+			// driver, the previous ones were closed by CopyFrom.
+			errs := []error{err}
+			for _, rest := range writableStreams[i:] {
+				errs = append(errs, fromChannel(rest).(*writableStream).Close())
+			}
+			return nil, errors.Join(errs...)
 		}
 		streams = append(streams, stream.channel)
 	}
@@ -131,6 +180,18 @@ func convertInputFiles(files any, context *browserContextImpl) (*inputFiles, err
 	return converted, nil
 }
 
+func copyInputFile(stream *writableStream, file inputFileUpload) error {
+	reader, err := file.open()
+	if err != nil {
+		return err
+	}
+	err = stream.CopyFrom(reader)
+	if closeErr := reader.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 func getFileLastModifiedMs(path string) (int64, error) {
 	info, err := os.Stat(path)
 	if err != nil {
@@ -0,0 +1,379 @@
+package playwright
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrExtractRequired is returned by [Extract] when a required field did not
+// match any element.
+var ErrExtractRequired = errors.New("required field not found")
+
+// Extract fills v with data scraped from the elements matching locator. v must be a
+// pointer to a struct, to a slice of structs, or to a slice of scalar values. A struct
+// target expects exactly one matching element; a slice target receives one item per
+// match, the same elements returned by [Locator.All].
+//
+// Struct fields are mapped with the following tags:
+//
+//	pw:"css=.price"         selector relative to the parent element, `css=` (default) or `xpath=`
+//	pw:".price,optional"    a missing element leaves the field at its zero value
+//	pw:".price,required"    a missing element fails the extraction
+//	pw:".bio,html"          read innerHTML instead of textContent
+//	pw:".bio,innertext"     read innerText instead of textContent
+//	attr:"data-value"       read the attribute instead of the text
+//	layout:"2006-01-02"     time layout for [time.Time] fields, defaults to [time.RFC3339]
+//
+// Fields without a selector read from the parent element itself. Text is trimmed of
+// surrounding whitespace; attributes and HTML are kept verbatim. Pointer and slice fields
+// are optional by default, all other fields are required. Slices hold every match, nested
+// structs and slices of structs are scoped to their matched element, and values are
+// parsed into strings, bools, ints, uints, floats, [time.Time], [time.Duration] (using
+// [time.ParseDuration], e.g. "1m30s") or any [encoding.TextUnmarshaler]. Recursive
+// types are not supported. Fields without a pw tag are ignored.
+//
+// The whole struct is extracted within a single [Locator.EvaluateAll] round trip, so
+// unlike [Locator.TextContent] it does not wait for elements to appear.
+func Extract(locator Locator, v any) error {
+	if err := locator.Err(); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("extract: expected a non-nil pointer, got %T", v)
+	}
+	target := rv.Elem()
+	many := target.Kind() == reflect.Slice && target.Type().Elem().Kind() != reflect.Uint8
+	elemType := target.Type()
+	if many {
+		elemType = elemType.Elem()
+	}
+	spec, err := extractSpecForType(elemType)
+	if err != nil {
+		return err
+	}
+	result, err := locator.EvaluateAll(extractScript, spec.serialize())
+	if err != nil {
+		return err
+	}
+	items, ok := result.([]any)
+	if !ok {
+		return fmt.Errorf("extract: unexpected result %T", result)
+	}
+	if !many {
+		if len(items) != 1 {
+			return fmt.Errorf("extract: expected exactly one element, got %d", len(items))
+		}
+		return spec.decode(items[0], target, "")
+	}
+	out := reflect.MakeSlice(target.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := spec.decode(item, out.Index(i), fmt.Sprintf("[%d]", i)); err != nil {
+			return err
+		}
+	}
+	target.Set(out)
+	return nil
+}
+
+const extractScript = `(elements, spec) => {
+  const query = (root, node) => {
+    if (!node.selector)
+      return [root];
+    if (node.engine === 'xpath') {
+      const result = document.evaluate(node.selector, root, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+      const found = [];
+      for (let i = 0; i < result.snapshotLength; i++)
+        found.push(result.snapshotItem(i));
+      return found;
+    }
+    return Array.from(root.querySelectorAll(node.selector));
+  };
+  const read = (element, node) => {
+    if (node.attr)
+      return element.getAttribute(node.attr);
+    if (node.source === 'html')
+      return element.innerHTML;
+    if (node.source === 'innertext')
+      return (element.innerText || '').trim();
+    return (element.textContent || '').trim();
+  };
+  const extract = (element, node) => {
+    if (!node.fields)
+      return read(element, node);
+    const out = {};
+    for (const field of node.fields) {
+      const found = query(element, field);
+      out[field.name] = field.many ? found.map(e => extract(e, field)) : (found.length ? extract(found[0], field) : null);
+    }
+    return out;
+  };
+  return elements.map(e => extract(e, spec));
+}`
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	extractSpecCache    sync.Map // reflect.Type -> *extractSpec
+	extractEnginePrefix = regexp.MustCompile(`^[\w:-]+=`)
+)
+
+// extractSpec describes how to extract a Go value from an element. A spec either
+// reads a single string (leaf) or has child fields (struct).
+type extractSpec struct {
+	name     string
+	selector string
+	engine   string
+	attr     string
+	source   string
+	layout   string
+	many     bool
+	required bool
+	fields   []*extractSpec
+	index    []int
+}
+
+func extractSpecForType(typ reflect.Type) (*extractSpec, error) {
+	if cached, ok := extractSpecCache.Load(typ); ok {
+		return cached.(*extractSpec), nil
+	}
+	spec := &extractSpec{}
+	if err := spec.build(typ, make(map[reflect.Type]bool)); err != nil {
+		return nil, err
+	}
+	extractSpecCache.Store(typ, spec)
+	return spec, nil
+}
+
+// build adds the fields of typ to s. building holds the struct types whose
+// specs are under construction, a recursive type would never end.
+func (s *extractSpec) build(typ reflect.Type, building map[reflect.Type]bool) error {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType || reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return nil
+	}
+	if building[typ] {
+		return fmt.Errorf("extract: recursive type %s is not supported", typ)
+	}
+	building[typ] = true
+	defer delete(building, typ)
+	for i := 0; i < typ.NumField(); i++ {
+		fi := typ.Field(i)
+		tag, ok := fi.Tag.Lookup("pw")
+		if !ok || !fi.IsExported() {
+			continue
+		}
+		field, err := newExtractField(fi, tag, building)
+		if err != nil {
+			return fmt.Errorf("extract: field %s.%s: %w", typ.Name(), fi.Name, err)
+		}
+		s.fields = append(s.fields, field)
+	}
+	if len(s.fields) == 0 {
+		return fmt.Errorf("extract: struct %s has no fields with a pw tag", typ)
+	}
+	return nil
+}
+
+func newExtractField(fi reflect.StructField, tag string, building map[reflect.Type]bool) (*extractSpec, error) {
+	field := &extractSpec{
+		name:     fi.Name,
+		engine:   "css",
+		attr:     fi.Tag.Get("attr"),
+		layout:   fi.Tag.Get("layout"),
+		required: fi.Type.Kind() != reflect.Pointer && fi.Type.Kind() != reflect.Slice,
+		index:    fi.Index,
+	}
+	parts := strings.Split(tag, ",")
+	// Modifiers are only taken from the end so that selector lists such as
+	// "h1, h2" keep working.
+modifiers:
+	for len(parts) > 1 {
+		switch strings.TrimSpace(parts[len(parts)-1]) {
+		case "optional":
+			field.required = false
+		case "required":
+			field.required = true
+		case "html":
+			field.source = "html"
+		case "innertext":
+			field.source = "innertext"
+		default:
+			break modifiers
+		}
+		parts = parts[:len(parts)-1]
+	}
+	selector := strings.TrimSpace(strings.Join(parts, ","))
+	switch {
+	case strings.HasPrefix(selector, "css="):
+		selector = strings.TrimPrefix(selector, "css=")
+	case strings.HasPrefix(selector, "xpath="):
+		field.engine = "xpath"
+		selector = strings.TrimPrefix(selector, "xpath=")
+	case strings.HasPrefix(selector, "//") || strings.HasPrefix(selector, ".."):
+		field.engine = "xpath"
+	case extractEnginePrefix.MatchString(selector):
+		return nil, fmt.Errorf("unsupported selector engine in %q, only css= and xpath= can be extracted in a single round trip", selector)
+	}
+	field.selector = selector
+
+	typ := fi.Type
+	if typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8 {
+		field.many = true
+		typ = typ.Elem()
+	}
+	if err := field.build(typ, building); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+func (s *extractSpec) serialize() map[string]any {
+	out := map[string]any{
+		"name":     s.name,
+		"selector": s.selector,
+		"engine":   s.engine,
+		"attr":     s.attr,
+		"source":   s.source,
+		"many":     s.many,
+	}
+	if s.fields != nil {
+		fields := make([]any, len(s.fields))
+		for i, field := range s.fields {
+			fields[i] = field.serialize()
+		}
+		out["fields"] = fields
+	}
+	return out
+}
+
+// decode assigns the raw evaluation result to v, converting strings to the
+// destination type and reporting missing required fields.
+func (s *extractSpec) decode(raw any, v reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return s.decode(raw, v.Elem(), path)
+	}
+	if s.fields == nil {
+		str, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("extract %s: unexpected value %T", path, raw)
+		}
+		if err := setExtractedValue(v, str, s.layout); err != nil {
+			return fmt.Errorf("extract %s: %w", path, err)
+		}
+		return nil
+	}
+	values, ok := raw.(map[string]any)
+	if !ok {
+		return fmt.Errorf("extract %s: unexpected value %T", path, raw)
+	}
+	for _, field := range s.fields {
+		fieldPath := strings.TrimPrefix(path+"."+field.name, ".")
+		value := values[field.name]
+		if value == nil {
+			if field.required {
+				return fmt.Errorf("extract %s (%s): %w", fieldPath, field.selector, ErrExtractRequired)
+			}
+			continue
+		}
+		dest := v.FieldByIndex(field.index)
+		if !field.many {
+			if err := field.decode(value, dest, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("extract %s: unexpected value %T", fieldPath, value)
+		}
+		if field.required && len(items) == 0 {
+			return fmt.Errorf("extract %s (%s): %w", fieldPath, field.selector, ErrExtractRequired)
+		}
+		out := reflect.MakeSlice(dest.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := field.decode(item, out.Index(i), fmt.Sprintf("%s[%d]", fieldPath, i)); err != nil {
+				return err
+			}
+		}
+		dest.Set(out)
+	}
+	return nil
+}
+
+func setExtractedValue(v reflect.Value, s string, layout string) error {
+	if v.Type() == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		v.SetBytes([]byte(s))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package playwright
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testExtractProduct struct {
+	Name     string    `pw:"h2"`
+	Price    float64   `pw:"css=.price" attr:"data-value"`
+	Stock    *int      `pw:".stock"`
+	Released time.Time `pw:"time" attr:"datetime" layout:"2006-01-02"`
+	Tags     []string  `pw:"ul > li, ol > li,optional"`
+	Seller   struct {
+		Name string `pw:"xpath=.//span[@class='seller']"`
+	} `pw:""`
+	Ignored string
+}
+
+func TestExtractSpecParsesTags(t *testing.T) {
+	spec, err := extractSpecForType(reflect.TypeOf(testExtractProduct{}))
+	require.NoError(t, err)
+	require.Len(t, spec.fields, 6)
+
+	price := spec.fields[1]
+	require.Equal(t, ".price", price.selector)
+	require.Equal(t, "css", price.engine)
+	require.Equal(t, "data-value", price.attr)
+	require.True(t, price.required)
+
+	require.False(t, spec.fields[2].required)
+
+	tags := spec.fields[4]
+	require.Equal(t, "ul > li, ol > li", tags.selector)
+	require.True(t, tags.many)
+	require.False(t, tags.required)
+
+	seller := spec.fields[5]
+	require.Equal(t, "", seller.selector)
+	require.Len(t, seller.fields, 1)
+	require.Equal(t, "xpath", seller.fields[0].engine)
+	require.Equal(t, ".//span[@class='seller']", seller.fields[0].selector)
+}
+
+func TestExtractSpecRejectsUnsupportedEngines(t *testing.T) {
+	_, err := extractSpecForType(reflect.TypeOf(struct {
+		Title string `pw:"text=Hello"`
+	}{}))
+	require.ErrorContains(t, err, "unsupported selector engine")
+}
+
+func TestExtractSpecDecode(t *testing.T) {
+	spec, err := extractSpecForType(reflect.TypeOf(testExtractProduct{}))
+	require.NoError(t, err)
+
+	var product testExtractProduct
+	require.NoError(t, spec.decode(map[string]any{
+		"Name":     "Widget",
+		"Price":    "9.5",
+		"Stock":    "3",
+		"Released": "2024-02-01",
+		"Tags":     []any{"a", "b"},
+		"Seller":   map[string]any{"Name": "ACME"},
+	}, reflect.ValueOf(&product).Elem(), ""))
+	require.Equal(t, "Widget", product.Name)
+	require.Equal(t, 9.5, product.Price)
+	require.Equal(t, 3, *product.Stock)
+	require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), product.Released)
+	require.Equal(t, []string{"a", "b"}, product.Tags)
+	require.Equal(t, "ACME", product.Seller.Name)
+
+	product = testExtractProduct{}
+	err = spec.decode(map[string]any{
+		"Price": "1",
+	}, reflect.ValueOf(&product).Elem(), "")
+	require.ErrorIs(t, err, ErrExtractRequired)
+	require.ErrorContains(t, err, "Name")
+
+	err = spec.decode(map[string]any{
+		"Name":  "Widget",
+		"Price": "cheap",
+	}, reflect.ValueOf(&product).Elem(), "")
+	require.ErrorContains(t, err, "Price")
+}
+
+type testExtractNode struct {
+	Label    string            `pw:"span"`
+	Children []testExtractNode `pw:"li"`
+}
+
+func TestExtractSpecRejectsRecursiveTypes(t *testing.T) {
+	_, err := extractSpecForType(reflect.TypeOf(testExtractNode{}))
+	require.ErrorContains(t, err, "recursive type")
+
+	// A type used twice side by side is not recursive.
+	_, err = extractSpecForType(reflect.TypeOf(struct {
+		First struct {
+			Name string `pw:"b"`
+		} `pw:".first"`
+		Second struct {
+			Name string `pw:"b"`
+		} `pw:".second"`
+	}{}))
+	require.NoError(t, err)
+}
+
+func TestExtractSpecDecodeDuration(t *testing.T) {
+	spec, err := extractSpecForType(reflect.TypeOf(struct {
+		Runtime time.Duration `pw:".runtime"`
+	}{}))
+	require.NoError(t, err)
+	var movie struct {
+		Runtime time.Duration `pw:".runtime"`
+	}
+	require.NoError(t, spec.decode(map[string]any{"Runtime": " 1h30m "}, reflect.ValueOf(&movie).Elem(), ""))
+	require.Equal(t, 90*time.Minute, movie.Runtime)
+}
@@ -0,0 +1,299 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrFormFieldNotFound is reported by [FillForm] for keys that matched no form control.
+	ErrFormFieldNotFound = errors.New("form field not found")
+	// ErrFormFieldAmbiguous is reported by [FillForm] for keys that matched several form controls.
+	ErrFormFieldAmbiguous = errors.New("form field is ambiguous")
+)
+
+// FormFillResult reports which keys [FillForm] could apply.
+type FormFillResult struct {
+	// Filled lists the keys that were applied, in the order they were filled.
+	Filled []string
+	// Unmatched lists the keys that matched no form control.
+	Unmatched []string
+	// Ambiguous lists the keys that matched more than one form control.
+	Ambiguous []string
+}
+
+// FillForm fills the form controls inside locator from values, which is either a
+// map[string]any or a struct. Struct fields are keyed by their `form` tag or else
+// their name; `form:"-"` skips a field, `form:",omitempty"` skips zero values and
+// nil pointers are always skipped. Map keys are filled in sorted order, struct
+// fields in declaration order.
+//
+// A key is matched against, in order, an exact label (as [Locator.GetByLabel]), the
+// name attribute, an exact placeholder (as [Locator.GetByPlaceholder]), the test id
+// (as [Locator.GetByTestId]) and finally a substring label match. The first strategy
+// that matches decides; a key can be pinned to one strategy with a "label=",
+// "name=", "placeholder=" or "testid=" prefix.
+//
+// The action depends on the matched control and the value:
+//   - checkboxes and radios use [Locator.SetChecked] with a bool (or a string parsed as one)
+//   - a group of radios sharing a name checks the radio with the given value
+//   - <select> elements use [Locator.SelectOption] with a string or []string
+//   - file inputs use [Locator.SetInputFiles] with anything it accepts
+//   - everything else uses [Locator.Fill], formatting [time.Time] for date and time inputs
+//
+// Every key is attempted. The returned error joins the action errors together with
+// [ErrFormFieldNotFound] and [ErrFormFieldAmbiguous] for the keys listed in the result.
+func FillForm(locator Locator, values any) (*FormFillResult, error) {
+	if err := locator.Err(); err != nil {
+		return nil, err
+	}
+	fields, err := formFieldValues(values)
+	if err != nil {
+		return nil, err
+	}
+	result := &FormFillResult{}
+	var errs []error
+	for _, field := range fields {
+		control, matches, err := resolveFormControl(locator, field.key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field.key, err))
+			continue
+		}
+		switch {
+		case matches == 0:
+			result.Unmatched = append(result.Unmatched, field.key)
+			errs = append(errs, fmt.Errorf("%s: %w", field.key, ErrFormFieldNotFound))
+			continue
+		case matches > 1:
+			handled, err := fillRadioGroup(control, field.value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", field.key, err))
+				continue
+			}
+			if !handled {
+				result.Ambiguous = append(result.Ambiguous, field.key)
+				errs = append(errs, fmt.Errorf("%s: %w (%d matches)", field.key, ErrFormFieldAmbiguous, matches))
+				continue
+			}
+		default:
+			if err := fillFormControl(control, field.value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", field.key, err))
+				continue
+			}
+		}
+		result.Filled = append(result.Filled, field.key)
+	}
+	return result, errors.Join(errs...)
+}
+
+type formFieldValue struct {
+	key   string
+	value any
+}
+
+func formFieldValues(values any) ([]formFieldValue, error) {
+	v := reflect.ValueOf(values)
+	for v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	fields := []formFieldValue{}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("form values must be keyed by string, got %s", v.Type())
+		}
+		for _, key := range v.MapKeys() {
+			fields = append(fields, formFieldValue{key: key.String(), value: v.MapIndex(key).Interface()})
+		}
+		slices.SortFunc(fields, func(a, b formFieldValue) int {
+			return strings.Compare(a.key, b.key)
+		})
+	case reflect.Struct:
+		typ := v.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			fi := typ.Field(i)
+			name, opts, _ := strings.Cut(fi.Tag.Get("form"), ",")
+			if !fi.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = fi.Name
+			}
+			value := v.Field(i)
+			if (value.Kind() == reflect.Pointer && value.IsNil()) || (opts == "omitempty" && value.IsZero()) {
+				continue
+			}
+			if value.Kind() == reflect.Pointer {
+				value = value.Elem()
+			}
+			fields = append(fields, formFieldValue{key: name, value: value.Interface()})
+		}
+	default:
+		return nil, fmt.Errorf("form values must be a map or a struct, got %T", values)
+	}
+	return fields, nil
+}
+
+// resolveFormControl returns the locator of the first strategy with at least one
+// match together with its match count.
+func resolveFormControl(root Locator, key string) (Locator, int, error) {
+	for _, candidate := range formControlCandidates(root, key) {
+		count, err := candidate.Count()
+		if err != nil {
+			return nil, 0, err
+		}
+		if count > 0 {
+			return candidate, count, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+func formControlCandidates(root Locator, key string) []Locator {
+	byName := func(name string) Locator {
+		selector, _ := getByAttributeTextSelector("name", name, true)
+		return root.Locator(selector)
+	}
+	exact := Bool(true)
+	if strategy, text, ok := strings.Cut(key, "="); ok {
+		switch strategy {
+		case "label":
+			return []Locator{root.GetByLabel(text, LocatorGetByLabelOptions{Exact: exact})}
+		case "name":
+			return []Locator{byName(text)}
+		case "placeholder":
+			return []Locator{root.GetByPlaceholder(text, LocatorGetByPlaceholderOptions{Exact: exact})}
+		case "testid":
+			return []Locator{root.GetByTestId(text)}
+		}
+	}
+	return []Locator{
+		root.GetByLabel(key, LocatorGetByLabelOptions{Exact: exact}),
+		byName(key),
+		root.GetByPlaceholder(key, LocatorGetByPlaceholderOptions{Exact: exact}),
+		root.GetByTestId(key),
+		root.GetByLabel(key),
+	}
+}
+
+type formControlInfo struct {
+	tag string
+	typ string
+}
+
+const formControlInfoScript = `elements => elements.map(e => ({
+  tag: e.tagName.toLowerCase(),
+  type: (e.getAttribute('type') || '').toLowerCase(),
+}))`
+
+func formControlInfos(control Locator) ([]formControlInfo, error) {
+	result, err := control.EvaluateAll(formControlInfoScript)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := result.([]any)
+	infos := make([]formControlInfo, 0, len(items))
+	for _, item := range items {
+		values, _ := item.(map[string]any)
+		info := formControlInfo{}
+		info.tag, _ = values["tag"].(string)
+		info.typ, _ = values["type"].(string)
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// fillRadioGroup checks the radio whose value attribute equals value when every
+// element matched by control is a radio button. It reports false if control is
+// not a radio group.
+func fillRadioGroup(control Locator, value any) (bool, error) {
+	infos, err := formControlInfos(control)
+	if err != nil {
+		return false, err
+	}
+	for _, info := range infos {
+		if info.tag != "input" || info.typ != "radio" {
+			return false, nil
+		}
+	}
+	impl, ok := control.(*locatorImpl)
+	if !ok {
+		return false, nil
+	}
+	selector, _ := getByAttributeTextSelector("value", fmt.Sprint(value), true)
+	radio := control.And(impl.frame.Locator(selector))
+	count, err := radio.Count()
+	if err != nil {
+		return true, err
+	}
+	if count != 1 {
+		return true, fmt.Errorf("%w: no radio with value %q", ErrFormFieldNotFound, fmt.Sprint(value))
+	}
+	return true, radio.Check()
+}
+
+func fillFormControl(control Locator, value any) error {
+	infos, err := formControlInfos(control)
+	if err != nil {
+		return err
+	}
+	if len(infos) != 1 {
+		return fmt.Errorf("%w (%d matches)", ErrFormFieldAmbiguous, len(infos))
+	}
+	info := infos[0]
+	switch {
+	case info.tag == "input" && (info.typ == "checkbox" || info.typ == "radio"):
+		checked, err := formBool(value)
+		if err != nil {
+			return err
+		}
+		return control.SetChecked(checked)
+	case info.tag == "input" && info.typ == "file":
+		return control.SetInputFiles(value)
+	case info.tag == "select":
+		switch v := value.(type) {
+		case []string:
+			_, err = control.SelectOption(SelectOptionValues{ValuesOrLabels: &v})
+		default:
+			_, err = control.SelectOption(SelectOptionValues{ValuesOrLabels: StringSlice(fmt.Sprint(v))})
+		}
+		return err
+	default:
+		return control.Fill(formText(value, info.typ))
+	}
+}
+
+func formBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	default:
+		return false, fmt.Errorf("expected a bool for a checkbox, got %T", value)
+	}
+}
+
+func formText(value any, inputType string) string {
+	t, ok := value.(time.Time)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	switch inputType {
+	case "date":
+		return t.Format(time.DateOnly)
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04")
+	case "time":
+		return t.Format("15:04")
+	case "month":
+		return t.Format("2006-01")
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
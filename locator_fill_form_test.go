@@ -0,0 +1,37 @@
+package playwright
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormFieldValues(t *testing.T) {
+	fields, err := formFieldValues(map[string]any{"b": 2, "a": "1"})
+	require.NoError(t, err)
+	require.Equal(t, []formFieldValue{{key: "a", value: "1"}, {key: "b", value: 2}}, fields)
+
+	type signup struct {
+		Email    string `form:"Email address"`
+		Nickname string `form:",omitempty"`
+		Age      *int
+		Terms    bool
+		internal string
+		Skipped  string `form:"-"`
+	}
+	fields, err = formFieldValues(&signup{Email: "a@b.c", Terms: true, internal: "x", Skipped: "y"})
+	require.NoError(t, err)
+	require.Equal(t, []formFieldValue{{key: "Email address", value: "a@b.c"}, {key: "Terms", value: true}}, fields)
+
+	_, err = formFieldValues([]string{"a"})
+	require.ErrorContains(t, err, "must be a map or a struct")
+}
+
+func TestFormText(t *testing.T) {
+	ts := time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC)
+	require.Equal(t, "2024-03-09", formText(ts, "date"))
+	require.Equal(t, "2024-03-09T14:05", formText(ts, "datetime-local"))
+	require.Equal(t, "14:05", formText(ts, "time"))
+	require.Equal(t, "42", formText(42, "number"))
+}
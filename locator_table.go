@@ -0,0 +1,254 @@
+package playwright
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Table holds the cells of an HTML table as returned by [ExtractTable]. Cells
+// spanning several rows or columns via rowspan/colspan are repeated in every
+// position they cover, so every row has exactly len(Header) cells.
+type Table struct {
+	// Header holds the column names taken from the last header row.
+	Header []string `json:"header"`
+	// Rows holds the body rows.
+	Rows [][]string `json:"rows"`
+	// Footer holds the rows of the <tfoot> section.
+	Footer [][]string `json:"footer,omitempty"`
+}
+
+// ExtractTable reads the <table> element matching locator in a single
+// [Locator.Evaluate] round trip. Header rows are the rows of <thead> or, when
+// there is none, a leading row made only of <th> cells. Cell text is the
+// trimmed innerText of each cell.
+func ExtractTable(locator Locator) (*Table, error) {
+	result, err := locator.Evaluate(extractTableScript, nil)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := result.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("extract table: unexpected result %T", result)
+	}
+	table := &Table{
+		Header: toStringRow(values["header"]),
+		Rows:   toStringRows(values["rows"]),
+		Footer: toStringRows(values["footer"]),
+	}
+	return table, nil
+}
+
+// TableAs extracts the table matching locator via [ExtractTable] and maps each
+// body row onto a T. Columns are matched to struct fields by header name,
+// case-insensitively, using the `table` struct tag or else the field name. A
+// `table:"-"` tag skips the field. Non-pointer fields require their column to be
+// present. Values are converted the same way as by [Extract], including the
+// `layout` tag for [time.Time] fields. An empty cell leaves the field at its
+// zero value, nil for pointer fields.
+func TableAs[T any](locator Locator) ([]T, error) {
+	table, err := ExtractTable(locator)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(table.Rows))
+	if err := table.decode(reflect.ValueOf(out)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WriteCSV writes the header (if any) followed by the body rows to w.
+func (t *Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if len(t.Header) > 0 {
+		if err := writer.Write(t.Header); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// WriteJSON writes the body rows to w as a JSON array of objects keyed by
+// header name. Repeated names, e.g. of a header cell spanning several columns,
+// get a numeric suffix not used by another column, e.g. "price", "price_2",
+// and blank names are replaced by their column number, e.g. "column_3".
+// Without a header every row is written as an array of cells.
+func (t *Table) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	if len(t.Header) == 0 {
+		return encoder.Encode(t.Rows)
+	}
+	names := t.uniqueHeader()
+	records := make([]map[string]string, len(t.Rows))
+	for i, row := range t.Rows {
+		record := make(map[string]string, len(names))
+		for j, name := range names {
+			if j < len(row) {
+				record[name] = row[j]
+			}
+		}
+		records[i] = record
+	}
+	return encoder.Encode(records)
+}
+
+// uniqueHeader returns the header with blank and repeated names replaced, see
+// [Table.WriteJSON].
+func (t *Table) uniqueHeader() []string {
+	names := make([]string, len(t.Header))
+	used := make(map[string]bool, len(t.Header))
+	for _, name := range t.Header {
+		used[name] = true
+	}
+	seen := make(map[string]int, len(t.Header))
+	for i, name := range t.Header {
+		if strings.TrimSpace(name) == "" {
+			names[i] = fmt.Sprintf("column_%d", i+1)
+			used[names[i]] = true
+			continue
+		}
+		seen[name]++
+		if seen[name] == 1 {
+			names[i] = name
+			continue
+		}
+		unique := fmt.Sprintf("%s_%d", name, seen[name])
+		for used[unique] {
+			seen[name]++
+			unique = fmt.Sprintf("%s_%d", name, seen[name])
+		}
+		used[unique] = true
+		names[i] = unique
+	}
+	return names
+}
+
+func (t *Table) decode(out reflect.Value) error {
+	typ := out.Type().Elem()
+	isPointer := typ.Kind() == reflect.Pointer
+	if isPointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return fmt.Errorf("table: expected a struct type, got %s", typ)
+	}
+	columns := make(map[string]int, len(t.Header))
+	for i, name := range t.Header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[key]; !ok {
+			columns[key] = i
+		}
+	}
+	type fieldColumn struct {
+		index  []int
+		column int
+		layout string
+	}
+	fields := []fieldColumn{}
+	for i := 0; i < typ.NumField(); i++ {
+		fi := typ.Field(i)
+		name := fi.Tag.Get("table")
+		if !fi.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = fi.Name
+		}
+		column, ok := columns[strings.ToLower(name)]
+		if !ok {
+			if fi.Type.Kind() == reflect.Pointer {
+				continue
+			}
+			return fmt.Errorf("table: no column %q for field %s", name, fi.Name)
+		}
+		fields = append(fields, fieldColumn{index: fi.Index, column: column, layout: fi.Tag.Get("layout")})
+	}
+	for i, row := range t.Rows {
+		item := out.Index(i)
+		if isPointer {
+			item.Set(reflect.New(typ))
+			item = item.Elem()
+		}
+		for _, field := range fields {
+			if field.column >= len(row) || strings.TrimSpace(row[field.column]) == "" {
+				continue
+			}
+			dest := item.FieldByIndex(field.index)
+			if dest.Kind() == reflect.Pointer {
+				dest.Set(reflect.New(dest.Type().Elem()))
+				dest = dest.Elem()
+			}
+			if err := setExtractedValue(dest, row[field.column], field.layout); err != nil {
+				return fmt.Errorf("table: row %d, column %q: %w", i, t.Header[field.column], err)
+			}
+		}
+	}
+	return nil
+}
+
+func toStringRow(v any) []string {
+	cells, _ := v.([]any)
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i], _ = cell.(string)
+	}
+	return row
+}
+
+func toStringRows(v any) [][]string {
+	items, _ := v.([]any)
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		rows[i] = toStringRow(item)
+	}
+	return rows
+}
+
+const extractTableScript = `table => {
+  if (table.tagName !== 'TABLE')
+    throw new Error('Element is not a <table> element');
+  const rows = Array.from(table.rows);
+  // Cells only span rows of their own thead, tbody or tfoot, groupEnd[r] is the
+  // index after the last row of the group of row r.
+  const groupEnd = [];
+  for (let r = rows.length - 1; r >= 0; r--)
+    groupEnd[r] = r + 1 < rows.length && rows[r + 1].parentElement === rows[r].parentElement ? groupEnd[r + 1] : r + 1;
+  const grid = rows.map(() => []);
+  rows.forEach((row, r) => {
+    let c = 0;
+    for (const cell of row.cells) {
+      while (grid[r][c] !== undefined)
+        c++;
+      const text = cell.innerText.trim();
+      const rowSpan = cell.rowSpan === 0 ? groupEnd[r] - r : Math.max(1, cell.rowSpan);
+      const colSpan = Math.max(1, cell.colSpan);
+      for (let i = 0; i < rowSpan && r + i < groupEnd[r]; i++) {
+        for (let j = 0; j < colSpan; j++)
+          grid[r + i][c + j] = text;
+      }
+      c += colSpan;
+    }
+  });
+  const width = Math.max(0, ...grid.map(row => row.length));
+  const cells = grid.map(row => Array.from({ length: width }, (_, i) => row[i] === undefined ? '' : row[i]));
+  const section = row => row.parentElement.tagName;
+  let headerRows = rows.map((row, i) => i).filter(i => section(rows[i]) === 'THEAD');
+  if (!headerRows.length && rows.length && Array.from(rows[0].cells).every(cell => cell.tagName === 'TH'))
+    headerRows = [0];
+  const header = headerRows.length ? cells[headerRows[headerRows.length - 1]] : [];
+  const body = [];
+  const footer = [];
+  rows.forEach((row, i) => {
+    if (headerRows.includes(i))
+      return;
+    (section(row) === 'TFOOT' ? footer : body).push(cells[i]);
+  });
+  return { header, rows: body, footer };
+}`
@@ -0,0 +1,87 @@
+package playwright
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDecode(t *testing.T) {
+	table := &Table{
+		Header: []string{"Name", "Unit Price", "Qty"},
+		Rows: [][]string{
+			{"Widget", "9.5", "3"},
+			{"Gadget", "12", "1"},
+		},
+	}
+	type item struct {
+		Name  string
+		Price float64 `table:"unit price"`
+		Qty   int
+		Note  *string
+		Skip  string `table:"-"`
+	}
+	items := make([]item, len(table.Rows))
+	require.NoError(t, table.decode(reflect.ValueOf(items)))
+	require.Equal(t, []item{
+		{Name: "Widget", Price: 9.5, Qty: 3},
+		{Name: "Gadget", Price: 12, Qty: 1},
+	}, items)
+
+	type missing struct {
+		Color string
+	}
+	err := table.decode(reflect.ValueOf(make([]missing, 2)))
+	require.ErrorContains(t, err, `no column "Color"`)
+
+	type invalid struct {
+		Qty bool
+	}
+	err = table.decode(reflect.ValueOf(make([]*invalid, 2)))
+	require.ErrorContains(t, err, `row 0, column "Qty"`)
+}
+
+func TestTableWrite(t *testing.T) {
+	table := &Table{
+		Header: []string{"Name", "Note"},
+		Rows:   [][]string{{"Widget", `says "hi", twice`}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteCSV(&buf))
+	require.Equal(t, "Name,Note\nWidget,\"says \"\"hi\"\", twice\"\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, table.WriteJSON(&buf))
+	require.JSONEq(t, `[{"Name":"Widget","Note":"says \"hi\", twice"}]`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, (&Table{Rows: [][]string{{"a", "b"}}}).WriteJSON(&buf))
+	require.JSONEq(t, `[["a","b"]]`, buf.String())
+}
+
+func TestTableWriteJSONDisambiguatesHeader(t *testing.T) {
+	table := &Table{
+		Header: []string{"Price", "Price", "", "Price_2"},
+		Rows:   [][]string{{"1", "2", "3", "4"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteJSON(&buf))
+	require.JSONEq(t, `[{"Price":"1","Price_3":"2","column_3":"3","Price_2":"4"}]`, buf.String())
+}
+
+func TestTableDecodeEmptyCells(t *testing.T) {
+	table := &Table{
+		Header: []string{"Name", "Price", "Qty"},
+		Rows:   [][]string{{"Widget", "", " "}},
+	}
+	type item struct {
+		Name  string
+		Price float64
+		Qty   *int
+	}
+	items := make([]item, len(table.Rows))
+	require.NoError(t, table.decode(reflect.ValueOf(items)))
+	require.Equal(t, []item{{Name: "Widget"}}, items)
+}
@@ -0,0 +1,241 @@
+package playwright
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// NetworkProfile describes the network conditions emulated by [EmulateNetwork].
+// Zero values don't limit the respective property.
+type NetworkProfile struct {
+	// Latency added to every request.
+	Latency time.Duration
+	// Jitter adds a random delay between zero and Jitter to every request.
+	Jitter time.Duration
+	// DownloadThroughput is the maximal download speed in bytes per second.
+	DownloadThroughput int
+	// UploadThroughput is the maximal upload speed in bytes per second.
+	UploadThroughput int
+	// PacketLoss is the percentage of lost packets, from 0 to 100.
+	PacketLoss float64
+}
+
+// Network profiles matching the presets of the Chrome DevTools.
+var (
+	NetworkNoThrottling = NetworkProfile{}
+	NetworkSlow3G       = NetworkProfile{
+		Latency:            2000 * time.Millisecond,
+		DownloadThroughput: 50_000,
+		UploadThroughput:   50_000,
+	}
+	NetworkFast3G = NetworkProfile{
+		Latency:            562500 * time.Microsecond,
+		DownloadThroughput: 180_000,
+		UploadThroughput:   84_375,
+	}
+	NetworkFast4G = NetworkProfile{
+		Latency:            165 * time.Millisecond,
+		DownloadThroughput: 1_012_500,
+		UploadThroughput:   168_750,
+	}
+)
+
+func (p NetworkProfile) isZero() bool {
+	return p == NetworkProfile{}
+}
+
+// EmulateNetwork throttles the network of all current and future pages of
+// context to profile. Calling it again replaces the profile;
+// [NetworkNoThrottling] turns emulation off. Use [BrowserContext.SetOffline] to
+// emulate a network outage.
+//
+// On Chromium latency, throughput and packet loss are applied with the
+// Network.emulateNetworkConditions CDP command, and jitter with a route adding
+// a random delay. Other browsers fall back to a route that delays requests by
+// the latency, jitter and upload time, fetches the response without following
+// redirects to delay it by the download time, and fails the given percentage
+// of requests with a connection error. The route is installed on the context,
+// so page routes still take precedence, and removed once it is not needed
+// anymore, e.g. by [NetworkNoThrottling].
+//
+// On Chromium new pages are throttled once their CDP session is attached, so
+// requests they make right after being opened may not be.
+func EmulateNetwork(context BrowserContext, profile NetworkProfile) error {
+	value, _ := networkEmulations.LoadOrStore(context, &networkEmulation{
+		context:  context,
+		sessions: make(map[Page]CDPSession),
+	})
+	return value.(*networkEmulation).apply(profile)
+}
+
+var networkEmulations sync.Map
+
+type networkEmulation struct {
+	context BrowserContext
+
+	mu       sync.Mutex
+	profile  NetworkProfile
+	started  bool
+	chromium bool
+	// route is the handler installed on the context while routed.
+	route    func(Route)
+	routed   bool
+	sessions map[Page]CDPSession
+}
+
+func (e *networkEmulation) apply(profile NetworkProfile) error {
+	e.mu.Lock()
+	e.profile = profile
+	if !e.started {
+		e.started = true
+		if browser := e.context.Browser(); browser != nil {
+			e.chromium = browser.BrowserType().Name() == "chromium"
+		}
+		e.context.OnClose(func(BrowserContext) {
+			networkEmulations.Delete(e.context)
+		})
+		e.context.OnPage(func(page Page) {
+			if err := e.emulatePage(page); err != nil {
+				logger.Error("Error emulating network conditions", "error", err)
+			}
+		})
+	}
+	if e.route == nil {
+		e.route = e.handle
+	}
+	needsRoute := !profile.isZero() && (!e.chromium || profile.Jitter > 0)
+	install, uninstall := needsRoute && !e.routed, !needsRoute && e.routed
+	e.routed = needsRoute
+	e.mu.Unlock()
+
+	switch {
+	case install:
+		if err := e.context.Route("**/*", e.route); err != nil {
+			return err
+		}
+	case uninstall:
+		if err := e.context.Unroute("**/*", e.route); err != nil {
+			return err
+		}
+	}
+	for _, page := range e.context.Pages() {
+		if err := e.emulatePage(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emulatePage applies the profile to page over CDP on Chromium.
+func (e *networkEmulation) emulatePage(page Page) error {
+	e.mu.Lock()
+	if !e.chromium {
+		e.mu.Unlock()
+		return nil
+	}
+	session, ok := e.sessions[page]
+	profile := e.profile
+	e.mu.Unlock()
+	if !ok {
+		var err error
+		if session, err = e.context.NewCDPSession(page); err != nil {
+			return err
+		}
+		if _, err := session.Send("Network.enable", nil); err != nil {
+			return err
+		}
+		e.mu.Lock()
+		e.sessions[page] = session
+		e.mu.Unlock()
+		page.OnClose(func(Page) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			delete(e.sessions, page)
+		})
+	}
+	params := map[string]any{
+		"offline":            false,
+		"latency":            float64(profile.Latency) / float64(time.Millisecond),
+		"downloadThroughput": -1,
+		"uploadThroughput":   -1,
+	}
+	if profile.DownloadThroughput > 0 {
+		params["downloadThroughput"] = profile.DownloadThroughput
+	}
+	if profile.UploadThroughput > 0 {
+		params["uploadThroughput"] = profile.UploadThroughput
+	}
+	if profile.PacketLoss > 0 {
+		params["packetLoss"] = profile.PacketLoss
+	}
+	_, err := session.Send("Network.emulateNetworkConditions", params)
+	return err
+}
+
+func (e *networkEmulation) handle(route Route) {
+	e.mu.Lock()
+	profile := e.profile
+	chromium := e.chromium
+	e.mu.Unlock()
+	if profile.isZero() {
+		e.fallback(route)
+		return
+	}
+	delay := time.Duration(0)
+	if profile.Jitter > 0 {
+		delay += rand.N(profile.Jitter)
+	}
+	if chromium {
+		time.Sleep(delay)
+		e.fallback(route)
+		return
+	}
+	delay += profile.Latency
+	if profile.PacketLoss > 0 && rand.Float64()*100 < profile.PacketLoss {
+		time.Sleep(delay)
+		if err := route.Abort("connectionfailed"); err != nil {
+			logger.Error("Error emulating network conditions", "error", err)
+		}
+		return
+	}
+	if profile.UploadThroughput > 0 {
+		if body, err := route.Request().PostDataBuffer(); err == nil {
+			delay += transferTime(len(body), profile.UploadThroughput)
+		}
+	}
+	time.Sleep(delay)
+	if profile.DownloadThroughput <= 0 {
+		e.fallback(route)
+		return
+	}
+	// Redirects are passed to the browser as they are, so it sees their status
+	// and Location and follows them itself.
+	response, err := route.Fetch(RouteFetchOptions{MaxRedirects: Int(0)})
+	if err != nil {
+		// Let the request fail or be handled as it would without emulation.
+		e.fallback(route)
+		return
+	}
+	body, err := response.Body()
+	if err != nil {
+		e.fallback(route)
+		return
+	}
+	time.Sleep(transferTime(len(body), profile.DownloadThroughput))
+	if err := route.Fulfill(RouteFulfillOptions{Response: response}); err != nil {
+		logger.Error("Error emulating network conditions", "error", err)
+	}
+}
+
+func (e *networkEmulation) fallback(route Route) {
+	if err := route.Fallback(); err != nil {
+		logger.Error("Error emulating network conditions", "error", err)
+	}
+}
+
+// transferTime returns how long sending size bytes takes at throughput bytes
+// per second.
+func transferTime(size, throughput int) time.Duration {
+	return time.Duration(float64(size) / float64(throughput) * float64(time.Second))
+}
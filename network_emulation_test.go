@@ -0,0 +1,122 @@
+package playwright
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEmulationRoute struct {
+	Route
+	request  Request
+	response APIResponse
+	actions  []string
+}
+
+func (r *fakeEmulationRoute) Request() Request { return r.request }
+func (r *fakeEmulationRoute) Fallback(options ...RouteFallbackOptions) error {
+	r.actions = append(r.actions, "fallback")
+	return nil
+}
+
+func (r *fakeEmulationRoute) Abort(errorCode ...string) error {
+	r.actions = append(r.actions, "abort:"+errorCode[0])
+	return nil
+}
+
+func (r *fakeEmulationRoute) Fetch(options ...RouteFetchOptions) (APIResponse, error) {
+	if len(options) == 1 && options[0].MaxRedirects != nil && *options[0].MaxRedirects == 0 {
+		r.actions = append(r.actions, "fetch")
+	}
+	return r.response, nil
+}
+
+func (r *fakeEmulationRoute) Fulfill(options ...RouteFulfillOptions) error {
+	if options[0].Response == r.response {
+		r.actions = append(r.actions, "fulfill")
+	}
+	return nil
+}
+
+type fakeEmulationResponse struct {
+	APIResponse
+	body []byte
+}
+
+func (r *fakeEmulationResponse) Body() ([]byte, error) { return r.body, nil }
+
+func TestNetworkEmulationRoute(t *testing.T) {
+	emulate := func(profile NetworkProfile, chromium bool) (*fakeEmulationRoute, time.Duration) {
+		e := &networkEmulation{profile: profile, chromium: chromium}
+		route := &fakeEmulationRoute{
+			request:  &fakeHandlerRequest{method: "POST", body: make([]byte, 100)},
+			response: &fakeEmulationResponse{body: make([]byte, 200)},
+		}
+		start := time.Now()
+		e.handle(route)
+		return route, time.Since(start)
+	}
+
+	route, _ := emulate(NetworkNoThrottling, false)
+	require.Equal(t, []string{"fallback"}, route.actions)
+
+	route, elapsed := emulate(NetworkProfile{Latency: 20 * time.Millisecond, UploadThroughput: 1000}, false)
+	require.Equal(t, []string{"fallback"}, route.actions)
+	require.GreaterOrEqual(t, elapsed, 120*time.Millisecond)
+
+	route, elapsed = emulate(NetworkProfile{DownloadThroughput: 2000}, false)
+	require.Equal(t, []string{"fetch", "fulfill"}, route.actions)
+	require.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+
+	route, _ = emulate(NetworkProfile{PacketLoss: 100}, false)
+	require.Equal(t, []string{"abort:connectionfailed"}, route.actions)
+
+	// Chromium only delays by the jitter, the rest is emulated over CDP.
+	route, elapsed = emulate(NetworkProfile{Latency: time.Hour, PacketLoss: 100, Jitter: time.Millisecond}, true)
+	require.Equal(t, []string{"fallback"}, route.actions)
+	require.Less(t, elapsed, time.Second)
+}
+
+type fakeEmulationContext struct {
+	BrowserContext
+	routes []func(Route)
+}
+
+func (c *fakeEmulationContext) Browser() Browser                { return nil }
+func (c *fakeEmulationContext) OnClose(fn func(BrowserContext)) {}
+func (c *fakeEmulationContext) OnPage(fn func(Page))            {}
+func (c *fakeEmulationContext) Pages() []Page                   { return nil }
+func (c *fakeEmulationContext) Route(url any, handler func(Route), times ...int) error {
+	c.routes = append(c.routes, handler)
+	return nil
+}
+
+func (c *fakeEmulationContext) Unroute(url any, handlers ...func(Route)) error {
+	for _, handler := range handlers {
+		c.routes = slices.DeleteFunc(c.routes, func(route func(Route)) bool {
+			return funcIdentity(route) == funcIdentity(handler)
+		})
+	}
+	return nil
+}
+
+func TestNetworkEmulationUnroutes(t *testing.T) {
+	context := &fakeEmulationContext{}
+	e := &networkEmulation{context: context, sessions: make(map[Page]CDPSession)}
+	require.NoError(t, e.apply(NetworkSlow3G))
+	require.Len(t, context.routes, 1)
+	require.NoError(t, e.apply(NetworkFast3G))
+	require.Len(t, context.routes, 1)
+	require.NoError(t, e.apply(NetworkNoThrottling))
+	require.Empty(t, context.routes)
+	require.NoError(t, e.apply(NetworkFast4G))
+	require.Len(t, context.routes, 1)
+}
+
+func TestTransferTime(t *testing.T) {
+	require.Equal(t, time.Second, transferTime(50_000, NetworkSlow3G.DownloadThroughput))
+	require.Equal(t, 500*time.Millisecond, transferTime(500, 1000))
+	require.Equal(t, time.Duration(0), transferTime(0, 1000))
+}
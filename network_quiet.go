@@ -0,0 +1,230 @@
+package playwright
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NetworkQuietOptions are the options for [WaitForNetworkQuiet].
+type NetworkQuietOptions struct {
+	// Maximum number of requests that may still be in flight while the network is
+	// considered quiet. Defaults to `0`.
+	MaxInflight *int
+	// Time in milliseconds the number of in-flight requests has to stay at or below
+	// MaxInflight. Defaults to `500`.
+	QuietDuration *float64
+	// Requests whose URL matches any of these glob patterns, regular expressions or
+	// predicates are not tracked, e.g. long-polling or analytics endpoints.
+	IgnoreURLs []any
+	// Maximum time in milliseconds. Defaults to the page's default timeout, pass `0`
+	// to disable the timeout.
+	Timeout *float64
+}
+
+// WaitForNetworkQuiet waits until the page has had at most MaxInflight requests in
+// flight for QuietDuration. Unlike [LoadStateNetworkidle] the threshold and duration
+// are configurable and requests can be ignored, so it also settles on pages that
+// poll or stream analytics.
+//
+// Only requests issued after the call are tracked, use [ExpectNetworkQuiet] to
+// track the requests of an action. On timeout the returned error wraps
+// [ErrTimeout] and lists the requests that were still pending.
+func WaitForNetworkQuiet(page Page, options ...NetworkQuietOptions) error {
+	return ExpectNetworkQuiet(page, nil, options...)
+}
+
+// ExpectNetworkQuiet starts tracking the requests of page, calls cb and then
+// waits like [WaitForNetworkQuiet], so the requests started by cb are never
+// missed:
+//
+//	err := playwright.ExpectNetworkQuiet(page, func() error {
+//		return page.GetByRole("button", playwright.PageGetByRoleOptions{Name: "Load"}).Click()
+//	})
+//
+// cb may be nil.
+func ExpectNetworkQuiet(page Page, cb func() error, options ...NetworkQuietOptions) error {
+	option := NetworkQuietOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	maxInflight := 0
+	if option.MaxInflight != nil {
+		maxInflight = *option.MaxInflight
+	}
+	quietDuration := 500 * time.Millisecond
+	if option.QuietDuration != nil {
+		quietDuration = time.Duration(*option.QuietDuration * float64(time.Millisecond))
+	}
+	timeout := float64(defaultTimeout)
+	if option.Timeout != nil {
+		timeout = *option.Timeout
+	} else if p, ok := page.(*pageImpl); ok {
+		timeout = p.timeoutSettings.Timeout()
+	}
+	ignored := make([]*urlMatcher, 0, len(option.IgnoreURLs))
+	for _, pattern := range option.IgnoreURLs {
+		var baseURL *string
+		if p, ok := page.(*pageImpl); ok && p.browserContext.options != nil {
+			baseURL = p.browserContext.options.BaseURL
+		}
+		ignored = append(ignored, newURLMatcher(pattern, baseURL))
+	}
+
+	tracker := newNetworkQuietTracker(maxInflight, quietDuration)
+	closed := make(chan struct{})
+	onRequest := func(request Request) {
+		for _, matcher := range ignored {
+			if matcher.Matches(request.URL()) {
+				return
+			}
+		}
+		tracker.requestStarted(request)
+	}
+	onRequestDone := func(request Request) {
+		tracker.requestDone(request)
+	}
+	onClose := func(Page) {
+		close(closed)
+	}
+	page.OnRequest(onRequest)
+	page.OnRequestFinished(onRequestDone)
+	page.OnRequestFailed(onRequestDone)
+	page.Once("close", onClose)
+	defer func() {
+		page.RemoveListener("request", onRequest)
+		page.RemoveListener("requestfinished", onRequestDone)
+		page.RemoveListener("requestfailed", onRequestDone)
+		page.RemoveListener("close", onClose)
+		tracker.stop()
+	}()
+
+	if cb != nil {
+		if err := cb(); err != nil {
+			return err
+		}
+	}
+	tracker.start()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		deadline = time.After(time.Duration(timeout) * time.Millisecond)
+	}
+	select {
+	case <-tracker.quiet:
+		return nil
+	case <-closed:
+		return fmt.Errorf("%w: page closed while waiting for network quiet", ErrTargetClosed)
+	case <-deadline:
+		pending := tracker.pending()
+		return fmt.Errorf("%w: Timeout %.2fms exceeded while waiting for network quiet, %d requests pending:\n  %s",
+			ErrTimeout, timeout, len(pending), strings.Join(pending, "\n  "))
+	}
+}
+
+// networkQuietTracker closes quiet once at most maxInflight requests have been
+// in flight for quietDuration.
+type networkQuietTracker struct {
+	maxInflight   int
+	quietDuration time.Duration
+	quiet         chan struct{}
+
+	mu       sync.Mutex
+	inflight map[Request]struct{}
+	started  bool
+	stopped  bool
+	isQuiet  bool
+	timer    *time.Timer
+	// generation is increased whenever the timer is stopped, so a timer that
+	// fired concurrently doesn't close quiet after all.
+	generation int
+}
+
+func newNetworkQuietTracker(maxInflight int, quietDuration time.Duration) *networkQuietTracker {
+	return &networkQuietTracker{
+		maxInflight:   maxInflight,
+		quietDuration: quietDuration,
+		quiet:         make(chan struct{}),
+		inflight:      make(map[Request]struct{}),
+	}
+}
+
+// start starts measuring the quiet window, requests are tracked before.
+func (q *networkQuietTracker) start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.started = true
+	q.update()
+}
+
+func (q *networkQuietTracker) requestStarted(request Request) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.inflight[request] = struct{}{}
+	q.update()
+}
+
+func (q *networkQuietTracker) requestDone(request Request) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.inflight[request]; !ok {
+		return
+	}
+	delete(q.inflight, request)
+	q.update()
+}
+
+func (q *networkQuietTracker) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.stopped = true
+	q.stopTimer()
+}
+
+// pending returns the requests in flight, sorted.
+func (q *networkQuietTracker) pending() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := make([]string, 0, len(q.inflight))
+	for request := range q.inflight {
+		pending = append(pending, request.Method()+" "+request.URL())
+	}
+	slices.Sort(pending)
+	return pending
+}
+
+// update starts or stops the timer, q.mu must be held.
+func (q *networkQuietTracker) update() {
+	if !q.started || q.stopped || q.isQuiet {
+		return
+	}
+	if len(q.inflight) > q.maxInflight {
+		q.stopTimer()
+		return
+	}
+	if q.timer != nil {
+		return
+	}
+	generation := q.generation
+	q.timer = time.AfterFunc(q.quietDuration, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if q.stopped || q.isQuiet || generation != q.generation {
+			return
+		}
+		q.isQuiet = true
+		close(q.quiet)
+	})
+}
+
+// stopTimer stops the timer, q.mu must be held.
+func (q *networkQuietTracker) stopTimer() {
+	if q.timer == nil {
+		return
+	}
+	q.timer.Stop()
+	q.timer = nil
+	q.generation++
+}
@@ -0,0 +1,68 @@
+package playwright
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuietRequest struct {
+	Request
+	url string
+}
+
+func (r *fakeQuietRequest) Method() string { return "GET" }
+func (r *fakeQuietRequest) URL() string    { return r.url }
+
+func isQuiet(tracker *networkQuietTracker, within time.Duration) bool {
+	select {
+	case <-tracker.quiet:
+		return true
+	case <-time.After(within):
+		return false
+	}
+}
+
+func TestNetworkQuietTrackerWaitsForInflightRequests(t *testing.T) {
+	tracker := newNetworkQuietTracker(0, 50*time.Millisecond)
+	defer tracker.stop()
+	request := &fakeQuietRequest{url: "https://example.com/slow"}
+	tracker.requestStarted(request)
+	tracker.start()
+	require.False(t, isQuiet(tracker, 150*time.Millisecond))
+	require.Equal(t, []string{"GET https://example.com/slow"}, tracker.pending())
+
+	tracker.requestDone(request)
+	require.True(t, isQuiet(tracker, time.Second))
+}
+
+func TestNetworkQuietTrackerRestartsWindow(t *testing.T) {
+	tracker := newNetworkQuietTracker(1, 100*time.Millisecond)
+	defer tracker.stop()
+	tracker.start()
+	first := &fakeQuietRequest{url: "https://example.com/1"}
+	second := &fakeQuietRequest{url: "https://example.com/2"}
+	tracker.requestStarted(first)
+	tracker.requestStarted(second)
+	// Over the threshold the window is reset.
+	require.False(t, isQuiet(tracker, 150*time.Millisecond))
+	tracker.requestDone(second)
+	require.True(t, isQuiet(tracker, time.Second))
+	require.Equal(t, []string{"GET https://example.com/1"}, tracker.pending())
+}
+
+func TestNetworkQuietTrackerStop(t *testing.T) {
+	tracker := newNetworkQuietTracker(0, 10*time.Millisecond)
+	tracker.start()
+	tracker.stop()
+	require.False(t, isQuiet(tracker, 50*time.Millisecond))
+
+	// The quiet window only starts with start.
+	tracker = newNetworkQuietTracker(0, 10*time.Millisecond)
+	defer tracker.stop()
+	tracker.requestDone(&fakeQuietRequest{url: "https://example.com/unknown"})
+	require.False(t, isQuiet(tracker, 50*time.Millisecond))
+	tracker.start()
+	require.True(t, isQuiet(tracker, time.Second))
+}
@@ -0,0 +1,987 @@
+package playwright
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mxschmitt/playwright-go/har"
+)
+
+const defaultRedactionReplacement = "[REDACTED]"
+
+// RedactionOptions are the rules for [SetRedaction].
+type RedactionOptions struct {
+	// Header names whose values are replaced, matched case-insensitively.
+	Headers []string
+	// Cookie names whose values are replaced, in cookie lists as well as in
+	// Cookie and Set-Cookie headers.
+	Cookies []string
+	// JSON paths into request and response bodies, e.g. `password`,
+	// `$.user.token`, `items[*].secret` or `$..apiKey`.
+	JSONPaths []string
+	// Form field names whose values are replaced in URL-encoded and multipart
+	// bodies as well as in query strings.
+	FormFields []string
+	// Patterns whose matches are replaced in URLs, header and cookie values,
+	// textual bodies and trace call logs.
+	Patterns []*regexp.Regexp
+	// Text that replaces redacted values. Defaults to `[REDACTED]`.
+	Replacement *string
+	// Whether values filled into password inputs are masked in trace call logs.
+	// Defaults to `true`.
+	MaskPasswords *bool
+	// Called with a report after every redacted HAR or trace has been written.
+	OnRedacted func(report *RedactionReport)
+}
+
+// RedactionReport lists what was redacted from a HAR or trace file.
+type RedactionReport struct {
+	// Path of the HAR or trace file.
+	Path string
+	// Redactions in the order they were applied.
+	Redactions []Redaction
+}
+
+// Redaction describes a single redacted value.
+type Redaction struct {
+	// Kind of the rule that matched: "header", "cookie", "json", "form",
+	// "pattern" or "password".
+	Kind string
+	// Name of the header, cookie or form field, the JSON path or the pattern.
+	Name string
+	// URL of the request, or the call id of the trace action.
+	Location string
+}
+
+// SetRedaction applies options to every HAR and trace recorded through tracing
+// before it is written to disk: HARs from [Tracing.StartHar], RecordHarPath and
+// [BrowserContext.RouteFromHAR] updates, and traces from [Tracing.Stop] and
+// [Tracing.StopChunk]. tracing is the Tracing of a [BrowserContext] or an
+// [APIRequestContext]. Pass nil to stop redacting.
+//
+// Traces are written to a temporary file and only the redacted trace is moved
+// to the requested path. HARs are written by the driver and then replaced
+// through a temporary file, and attachments whose body was redacted are
+// deleted. If redaction fails the unredacted file is removed.
+//
+// Password inputs are detected from the call log of the fill action, which
+// describes the element the locator resolved to. Their values are also masked
+// in the DOM snapshots of the trace.
+func SetRedaction(tracing Tracing, options *RedactionOptions) error {
+	impl, ok := tracing.(*tracingImpl)
+	if !ok {
+		return fmt.Errorf("redaction: unsupported tracing %T", tracing)
+	}
+	var r *redactor
+	if options != nil {
+		var err error
+		if r, err = newRedactor(options); err != nil {
+			return err
+		}
+	}
+	impl.Lock()
+	defer impl.Unlock()
+	impl.redactor = r
+	return nil
+}
+
+type redactor struct {
+	headers       map[string]bool
+	cookies       map[string]bool
+	jsonPaths     []jsonPath
+	formFields    map[string]*regexp.Regexp
+	patterns      []*regexp.Regexp
+	replacement   string
+	maskPasswords bool
+	onRedacted    func(report *RedactionReport)
+}
+
+func newRedactor(options *RedactionOptions) (*redactor, error) {
+	r := &redactor{
+		headers:       make(map[string]bool),
+		cookies:       make(map[string]bool),
+		formFields:    make(map[string]*regexp.Regexp),
+		patterns:      options.Patterns,
+		replacement:   defaultRedactionReplacement,
+		maskPasswords: true,
+		onRedacted:    options.OnRedacted,
+	}
+	if options.Replacement != nil {
+		r.replacement = *options.Replacement
+	}
+	if options.MaskPasswords != nil {
+		r.maskPasswords = *options.MaskPasswords
+	}
+	for _, name := range options.Headers {
+		r.headers[strings.ToLower(name)] = true
+	}
+	for _, name := range options.Cookies {
+		r.cookies[name] = true
+	}
+	for _, name := range options.FormFields {
+		// Matches the value of a multipart part with the given field name.
+		r.formFields[name] = regexp.MustCompile(`(?s)(Content-Disposition: form-data; name="` +
+			regexp.QuoteMeta(name) + `"[^\r\n]*\r\n(?:[^\r\n]+\r\n)*\r\n)(.*?)(\r\n--)`)
+	}
+	for _, path := range options.JSONPaths {
+		parsed, err := parseJSONPath(path)
+		if err != nil {
+			return nil, err
+		}
+		r.jsonPaths = append(r.jsonPaths, parsed)
+	}
+	return r, nil
+}
+
+func (r *redactor) report(report *RedactionReport) {
+	if r.onRedacted != nil {
+		r.onRedacted(report)
+	}
+}
+
+// redactionPass collects the redactions applied to one file.
+type redactionPass struct {
+	*redactor
+	result *RedactionReport
+}
+
+func (p *redactionPass) add(kind, name, location string) {
+	p.result.Redactions = append(p.result.Redactions, Redaction{Kind: kind, Name: name, Location: location})
+}
+
+// redactString replaces every pattern match in s.
+func (p *redactionPass) redactString(s, location string) string {
+	for _, pattern := range p.patterns {
+		matches := len(pattern.FindAllStringIndex(s, -1))
+		if matches == 0 {
+			continue
+		}
+		s = pattern.ReplaceAllLiteralString(s, p.replacement)
+		for i := 0; i < matches; i++ {
+			p.add("pattern", pattern.String(), location)
+		}
+	}
+	return s
+}
+
+// redactHarFile redacts the HAR or HAR zip at path. The redacted archive is
+// written to a temporary file that is renamed over path, and attachments of a
+// .har whose body was redacted are removed, so no unredacted copy remains. If
+// redaction fails the unredacted HAR is removed.
+func (r *redactor) redactHarFile(path string) (err error) {
+	var attached []string
+	isZip := strings.HasSuffix(strings.ToLower(path), ".zip")
+	defer func() {
+		if err != nil {
+			os.Remove(path) //nolint:errcheck
+			if !isZip {
+				removeAttachments(filepath.Dir(path), attached)
+			}
+		}
+	}()
+	archive, err := har.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	attached = harAttachments(archive)
+	pass := &redactionPass{redactor: r, result: &RedactionReport{Path: path}}
+	bodies := harFileBodies{archive}
+	for _, entry := range archive.Log.Entries {
+		if err := pass.redactEntry(entry, bodies); err != nil {
+			return err
+		}
+	}
+	tmpPath := redactionTempPath(path)
+	defer os.Remove(tmpPath) //nolint:errcheck
+	if err := archive.WriteFile(tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	if !isZip {
+		referenced := make(map[string]bool)
+		for _, name := range harAttachments(archive) {
+			referenced[name] = true
+		}
+		var replaced []string
+		for _, name := range attached {
+			if !referenced[name] {
+				replaced = append(replaced, name)
+			}
+		}
+		removeAttachments(filepath.Dir(path), replaced)
+	}
+	r.report(pass.result)
+	return nil
+}
+
+// redactionTempPath returns a hidden path next to path with the same extension.
+func redactionTempPath(path string) string {
+	return filepath.Join(filepath.Dir(path), ".redacting-"+filepath.Base(path))
+}
+
+// removeAttachments removes the attached bodies names from dir.
+func removeAttachments(dir string, names []string) {
+	for _, name := range names {
+		os.Remove(filepath.Join(dir, filepath.Base(name))) //nolint:errcheck
+	}
+}
+
+// harAttachments returns the names of the bodies attached to the entries of
+// archive.
+func harAttachments(archive *har.HAR) []string {
+	var names []string
+	for _, entry := range archive.Log.Entries {
+		if entry.Response.Content.File != "" {
+			names = append(names, entry.Response.Content.File)
+		}
+		if entry.Request.PostData != nil && entry.Request.PostData.File != "" {
+			names = append(names, entry.Request.PostData.File)
+		}
+	}
+	return names
+}
+
+// entryBodies reads and replaces the bodies of a recorded entry, which live in
+// different places for HAR files and traces.
+type entryBodies interface {
+	responseBody(entry *har.Entry) ([]byte, error)
+	setResponseBody(entry *har.Entry, body []byte)
+	requestBody(entry *har.Entry) ([]byte, error)
+	setRequestBody(entry *har.Entry, body []byte)
+}
+
+type harFileBodies struct {
+	archive *har.HAR
+}
+
+func (b harFileBodies) responseBody(entry *har.Entry) ([]byte, error) {
+	return b.archive.Body(entry)
+}
+
+func (b harFileBodies) setResponseBody(entry *har.Entry, body []byte) {
+	b.archive.SetBody(entry, body)
+}
+
+func (b harFileBodies) requestBody(entry *har.Entry) ([]byte, error) {
+	return b.archive.PostDataBody(entry)
+}
+
+func (b harFileBodies) setRequestBody(entry *har.Entry, body []byte) {
+	b.archive.SetPostDataBody(entry, body)
+}
+
+func (p *redactionPass) redactEntry(entry *har.Entry, bodies entryBodies) error {
+	// Report the redacted URL so the report itself doesn't leak secrets.
+	start := len(p.result.Redactions)
+	defer func() {
+		for i := start; i < len(p.result.Redactions); i++ {
+			p.result.Redactions[i].Location = entry.Request.URL
+		}
+	}()
+	location := entry.Request.URL
+	entry.Request.URL = p.redactURL(entry.Request.URL, location)
+	for i := range entry.Request.QueryString {
+		param := &entry.Request.QueryString[i]
+		if _, ok := p.formFields[param.Name]; ok {
+			param.Value = p.replacement
+			p.add("form", param.Name, location)
+			continue
+		}
+		param.Value = p.redactString(param.Value, location)
+	}
+	p.redactHeaders(entry.Request.Headers, location)
+	p.redactHeaders(entry.Response.Headers, location)
+	p.redactCookies(entry.Request.Cookies, location)
+	p.redactCookies(entry.Response.Cookies, location)
+	if entry.Response.RedirectURL != "" {
+		entry.Response.RedirectURL = p.redactString(entry.Response.RedirectURL, location)
+	}
+
+	if postData := entry.Request.PostData; postData != nil {
+		for i := range postData.Params {
+			param := &postData.Params[i]
+			if _, ok := p.formFields[param.Name]; ok {
+				param.Value = p.replacement
+				p.add("form", param.Name, location)
+				continue
+			}
+			param.Value = p.redactString(param.Value, location)
+		}
+		body, err := bodies.requestBody(entry)
+		if err != nil && !errors.Is(err, har.ErrResourceNotFound) {
+			return err
+		}
+		if body != nil {
+			if redacted, changed := p.redactBody(body, postData.MimeType, location); changed {
+				bodies.setRequestBody(entry, redacted)
+			}
+		}
+	}
+
+	body, err := bodies.responseBody(entry)
+	if err != nil && !errors.Is(err, har.ErrResourceNotFound) {
+		return err
+	}
+	if len(body) > 0 {
+		if redacted, changed := p.redactBody(body, entry.Response.Content.MimeType, location); changed {
+			bodies.setResponseBody(entry, redacted)
+		}
+	}
+	return nil
+}
+
+func (p *redactionPass) redactURL(rawURL, location string) string {
+	if len(p.formFields) > 0 {
+		if base, query, ok := strings.Cut(rawURL, "?"); ok {
+			rawURL = base + "?" + p.redactURLEncoded(query, location)
+		}
+	}
+	return p.redactString(rawURL, location)
+}
+
+func (p *redactionPass) redactHeaders(headers []har.NameValue, location string) {
+	for i := range headers {
+		header := &headers[i]
+		name := strings.ToLower(header.Name)
+		switch {
+		case p.headers[name]:
+			header.Value = p.replacement
+			p.add("header", header.Name, location)
+			continue
+		case name == "cookie" && len(p.cookies) > 0:
+			header.Value = p.redactCookieHeader(header.Value, location)
+		case name == "set-cookie" && len(p.cookies) > 0:
+			header.Value = p.redactSetCookieHeader(header.Value, location)
+		}
+		header.Value = p.redactString(header.Value, location)
+	}
+}
+
+func (p *redactionPass) redactCookies(cookies []har.Cookie, location string) {
+	for i := range cookies {
+		cookie := &cookies[i]
+		if p.cookies[cookie.Name] {
+			cookie.Value = p.replacement
+			p.add("cookie", cookie.Name, location)
+			continue
+		}
+		cookie.Value = p.redactString(cookie.Value, location)
+	}
+}
+
+// redactCookieHeader redacts a "name=value; name2=value2" request header.
+func (p *redactionPass) redactCookieHeader(value, location string) string {
+	pairs := strings.Split(value, ";")
+	for i, pair := range pairs {
+		name, _, ok := strings.Cut(pair, "=")
+		if trimmed := strings.TrimSpace(name); ok && p.cookies[trimmed] {
+			pairs[i] = name + "=" + p.replacement
+			p.add("cookie", trimmed, location)
+		}
+	}
+	return strings.Join(pairs, ";")
+}
+
+// redactSetCookieHeader redacts a Set-Cookie header, which HARs record with one
+// cookie per line.
+func (p *redactionPass) redactSetCookieHeader(value, location string) string {
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		pair, attributes, _ := strings.Cut(line, ";")
+		name, _, ok := strings.Cut(pair, "=")
+		if trimmed := strings.TrimSpace(name); ok && p.cookies[trimmed] {
+			lines[i] = name + "=" + p.replacement
+			if attributes != "" {
+				lines[i] += ";" + attributes
+			}
+			p.add("cookie", trimmed, location)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redactBody applies the JSON path, form field and pattern rules to a body
+// depending on its MIME type. Binary bodies are left untouched.
+func (p *redactionPass) redactBody(body []byte, mimeType, location string) ([]byte, bool) {
+	mimeType = strings.ToLower(mimeType)
+	if !isTextualMimeType(mimeType) {
+		return body, false
+	}
+	text := string(body)
+	switch {
+	case strings.Contains(mimeType, "json") && len(p.jsonPaths) > 0:
+		text = p.redactJSON(text, location)
+	case strings.Contains(mimeType, "application/x-www-form-urlencoded") && len(p.formFields) > 0:
+		text = p.redactURLEncoded(text, location)
+	case strings.Contains(mimeType, "multipart/form-data"):
+		for name, pattern := range p.formFields {
+			matches := len(pattern.FindAllStringIndex(text, -1))
+			if matches == 0 {
+				continue
+			}
+			text = pattern.ReplaceAllString(text, "${1}"+strings.ReplaceAll(p.replacement, "$", "$$")+"${3}")
+			for i := 0; i < matches; i++ {
+				p.add("form", name, location)
+			}
+		}
+	}
+	text = p.redactString(text, location)
+	if text == string(body) {
+		return body, false
+	}
+	return []byte(text), true
+}
+
+func (p *redactionPass) redactJSON(text, location string) string {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return text
+	}
+	changed := false
+	for _, path := range p.jsonPaths {
+		for i := path.apply(value, path.segments, p.replacement); i > 0; i-- {
+			p.add("json", path.source, location)
+			changed = true
+		}
+	}
+	if !changed {
+		return text
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return text
+	}
+	return string(redacted)
+}
+
+func (p *redactionPass) redactURLEncoded(text, location string) string {
+	pairs := strings.Split(text, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			continue
+		}
+		if _, ok := p.formFields[name]; ok {
+			pairs[i] = key + "=" + url.QueryEscape(p.replacement)
+			p.add("form", name, location)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func isTextualMimeType(mimeType string) bool {
+	for _, textual := range []string{"text/", "json", "xml", "javascript", "x-www-form-urlencoded", "multipart/form-data"} {
+		if strings.Contains(mimeType, textual) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactTraceFile writes a redacted copy of the trace zip at src to dst, which
+// may be the same path. Network entries are redacted like HAR entries, action
+// parameters and call logs by the header and pattern rules, and values filled
+// into password inputs are masked.
+func (r *redactor) redactTraceFile(src, dst string) error {
+	reader, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	type zipEntry struct {
+		header *zip.FileHeader
+		data   []byte
+	}
+	entries := make([]zipEntry, 0, len(reader.File))
+	resources := make(map[string][]byte)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			reader.Close() //nolint:errcheck
+			return err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close() //nolint:errcheck
+		if err != nil {
+			reader.Close() //nolint:errcheck
+			return err
+		}
+		header := file.FileHeader
+		entries = append(entries, zipEntry{header: &header, data: data})
+		if strings.HasPrefix(file.Name, "resources/") {
+			resources[file.Name] = data
+		}
+	}
+	if err := reader.Close(); err != nil {
+		return err
+	}
+
+	pass := &redactionPass{redactor: r, result: &RedactionReport{Path: dst}}
+	bodies := traceBodies{resources}
+	for i, entry := range entries {
+		var err error
+		switch {
+		case strings.HasSuffix(entry.header.Name, ".network"):
+			entries[i].data, err = pass.redactTraceNetwork(entry.data, bodies)
+		case strings.HasSuffix(entry.header.Name, ".trace"):
+			entries[i].data, err = pass.redactTraceEvents(entry.data)
+		}
+		if err != nil {
+			return fmt.Errorf("redaction: %s: %w", entry.header.Name, err)
+		}
+	}
+
+	tmpPath := redactionTempPath(dst)
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath) //nolint:errcheck
+	writer := zip.NewWriter(f)
+	for _, entry := range entries {
+		if data, ok := resources[entry.header.Name]; ok {
+			entry.data = data
+		}
+		w, err := writer.CreateHeader(&zip.FileHeader{
+			Name:     entry.header.Name,
+			Method:   entry.header.Method,
+			Modified: entry.header.Modified,
+		})
+		if err != nil {
+			f.Close() //nolint:errcheck
+			return err
+		}
+		if _, err := w.Write(entry.data); err != nil {
+			f.Close() //nolint:errcheck
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		return err
+	}
+	r.report(pass.result)
+	return nil
+}
+
+// traceBodies resolves bodies stored as resources/<sha1> in a trace zip. Bodies
+// are replaced in place under their original name.
+type traceBodies struct {
+	resources map[string][]byte
+}
+
+func (b traceBodies) responseBody(entry *har.Entry) ([]byte, error) {
+	content := &entry.Response.Content
+	if content.SHA1 != "" {
+		return b.resources["resources/"+content.SHA1], nil
+	}
+	if content.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(content.Text)
+	}
+	return []byte(content.Text), nil
+}
+
+func (b traceBodies) setResponseBody(entry *har.Entry, body []byte) {
+	content := &entry.Response.Content
+	content.Size = int64(len(body))
+	if content.SHA1 != "" {
+		b.resources["resources/"+content.SHA1] = body
+	} else if content.Encoding == "base64" {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+	} else {
+		content.Text = string(body)
+	}
+}
+
+func (b traceBodies) requestBody(entry *har.Entry) ([]byte, error) {
+	postData := entry.Request.PostData
+	if postData.SHA1 != "" {
+		return b.resources["resources/"+postData.SHA1], nil
+	}
+	return []byte(postData.Text), nil
+}
+
+func (b traceBodies) setRequestBody(entry *har.Entry, body []byte) {
+	postData := entry.Request.PostData
+	if postData.SHA1 != "" {
+		b.resources["resources/"+postData.SHA1] = body
+	} else {
+		postData.Text = string(body)
+	}
+}
+
+// redactTraceNetwork redacts the resource snapshots of a .network file, one JSON
+// event per line. Snapshots are redacted as HAR entries and the changes merged
+// back, so fields [har.Entry] doesn't know are kept.
+func (p *redactionPass) redactTraceNetwork(data []byte, bodies entryBodies) ([]byte, error) {
+	return rewriteJSONLines(data, func(event map[string]any) (bool, error) {
+		snapshot, ok := event["snapshot"].(map[string]any)
+		if event["type"] != "resource-snapshot" || !ok {
+			return false, nil
+		}
+		raw, err := json.Marshal(snapshot)
+		if err != nil {
+			return false, err
+		}
+		entry := &har.Entry{}
+		if err := json.Unmarshal(raw, entry); err != nil {
+			return false, err
+		}
+		start := len(p.result.Redactions)
+		if err := p.redactEntry(entry, bodies); err != nil {
+			return false, err
+		}
+		if len(p.result.Redactions) == start {
+			return false, nil
+		}
+		raw, err = json.Marshal(entry)
+		if err != nil {
+			return false, err
+		}
+		var redacted any
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&redacted); err != nil {
+			return false, err
+		}
+		event["snapshot"] = mergeJSON(snapshot, redacted)
+		return true, nil
+	})
+}
+
+// mergeJSON overwrites the values of dst with those of src, keeping object keys
+// only dst has.
+func mergeJSON(dst, src any) any {
+	switch s := src.(type) {
+	case map[string]any:
+		d, ok := dst.(map[string]any)
+		if !ok {
+			return src
+		}
+		for key, value := range s {
+			d[key] = mergeJSON(d[key], value)
+		}
+		return d
+	case []any:
+		d, ok := dst.([]any)
+		if !ok || len(d) != len(s) {
+			return src
+		}
+		for i, value := range s {
+			d[i] = mergeJSON(d[i], value)
+		}
+		return d
+	default:
+		return src
+	}
+}
+
+var passwordInputRegexp = regexp.MustCompile(`(?i)type\s*=\s*\\?["']?password\b`)
+
+// redactTraceEvents redacts the action events of a .trace file, one JSON event
+// per line.
+func (p *redactionPass) redactTraceEvents(data []byte) ([]byte, error) {
+	// Find fill actions targeting password inputs first: the element is only
+	// known from the call log, which follows the action's "before" event.
+	secrets := make(map[string]string)
+	if p.maskPasswords {
+		fills := make(map[string]string)
+		passwordCalls := make(map[string]bool)
+		err := forEachJSONLine(data, func(event map[string]any) error {
+			callID, _ := event["callId"].(string)
+			switch event["type"] {
+			case "before":
+				method, _ := event["method"].(string)
+				params, _ := event["params"].(map[string]any)
+				if method != "fill" && method != "type" {
+					return nil
+				}
+				if value, ok := params["value"].(string); ok {
+					fills[callID] = value
+				} else if text, ok := params["text"].(string); ok {
+					fills[callID] = text
+				}
+				if selector, ok := params["selector"].(string); ok && passwordInputRegexp.MatchString(selector) {
+					passwordCalls[callID] = true
+				}
+			case "log":
+				if message, ok := event["message"].(string); ok && passwordInputRegexp.MatchString(message) {
+					passwordCalls[callID] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for callID := range passwordCalls {
+			if value := fills[callID]; value != "" {
+				secrets[callID] = value
+			}
+		}
+	}
+	return rewriteJSONLines(data, func(event map[string]any) (bool, error) {
+		start := len(p.result.Redactions)
+		callID, _ := event["callId"].(string)
+		location := callID
+		if location == "" {
+			location, _ = event["type"].(string)
+		}
+		secret := secrets[callID]
+		for key, value := range event {
+			event[key] = p.redactTraceValue(key, value, secret, location)
+		}
+		if secret != "" && event["type"] == "before" {
+			p.add("password", "fill", location)
+		}
+		if snapshot, ok := event["snapshot"].(map[string]any); ok && p.maskPasswords && event["type"] == "frame-snapshot" {
+			if snapshotCallID, _ := snapshot["callId"].(string); snapshotCallID != "" {
+				location = snapshotCallID
+			}
+			p.maskPasswordInputs(snapshot["html"], location)
+		}
+		return secret != "" || len(p.result.Redactions) > start, nil
+	})
+}
+
+// maskPasswordInputs replaces the values of password inputs in the DOM of a
+// frame snapshot. Elements are recorded as [tag, attributes, ...children], the
+// value typed into an input as its __playwright_value_ attribute.
+func (p *redactionPass) maskPasswordInputs(node any, location string) {
+	element, ok := node.([]any)
+	if !ok || len(element) < 2 {
+		return
+	}
+	tag, _ := element[0].(string)
+	attrs, _ := element[1].(map[string]any)
+	if inputType, _ := attrs["type"].(string); strings.EqualFold(tag, "INPUT") && strings.EqualFold(inputType, "password") {
+		for _, name := range []string{"__playwright_value_", "value"} {
+			if value, _ := attrs[name].(string); value != "" {
+				attrs[name] = p.replacement
+				p.add("password", "snapshot", location)
+			}
+		}
+	}
+	for _, child := range element[2:] {
+		p.maskPasswordInputs(child, location)
+	}
+}
+
+// redactTraceValue walks a decoded JSON value, redacting header lists and
+// replacing pattern matches and the masked secret in every string.
+func (p *redactionPass) redactTraceValue(key string, value any, secret, location string) any {
+	switch v := value.(type) {
+	case string:
+		if secret != "" {
+			v = strings.ReplaceAll(v, secret, p.replacement)
+		}
+		return p.redactString(v, location)
+	case map[string]any:
+		for k, child := range v {
+			v[k] = p.redactTraceValue(k, child, secret, location)
+		}
+		return v
+	case []any:
+		isHeaders := key == "headers" || key == "extraHTTPHeaders"
+		for i, child := range v {
+			if header, ok := child.(map[string]any); ok && isHeaders {
+				if name, ok := header["name"].(string); ok && p.headers[strings.ToLower(name)] {
+					header["value"] = p.replacement
+					p.add("header", name, location)
+					continue
+				}
+			}
+			v[i] = p.redactTraceValue("", child, secret, location)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func forEachJSONLine(data []byte, fn func(event map[string]any) error) error {
+	return scanJSONLines(data, func(line []byte, event map[string]any) error {
+		return fn(event)
+	})
+}
+
+func scanJSONLines(data []byte, fn func(line []byte, event map[string]any) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var event map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&event); err != nil {
+			return err
+		}
+		if err := fn(line, event); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// rewriteJSONLines calls fn for every event of data, one JSON event per line,
+// and re-encodes the events fn reports as changed. Other lines are kept as is.
+func rewriteJSONLines(data []byte, fn func(event map[string]any) (bool, error)) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	err := scanJSONLines(data, func(line []byte, event map[string]any) error {
+		changed, err := fn(event)
+		if err != nil {
+			return err
+		}
+		if changed {
+			return encoder.Encode(event)
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+		return nil
+	})
+	return out.Bytes(), err
+}
+
+// jsonPath is a parsed subset of JSONPath: `$`, `.name`, `['name']`, `[n]`,
+// `[*]`, `.*` and recursive descent via `..name`.
+type jsonPath struct {
+	source   string
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	key       string // object key, "*" for any key or index
+	index     int    // array index, -1 if key is used
+	recursive bool
+}
+
+func (s jsonPathSegment) matchesKey(key string) bool {
+	return s.index < 0 && (s.key == "*" || s.key == key)
+}
+
+func (s jsonPathSegment) matchesIndex(index int) bool {
+	return s.key == "*" || s.index == index
+}
+
+func parseJSONPath(source string) (jsonPath, error) {
+	path := jsonPath{source: source}
+	rest := strings.TrimPrefix(strings.TrimSpace(source), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+	invalid := func() (jsonPath, error) {
+		return jsonPath{}, fmt.Errorf("redaction: invalid JSON path %q", source)
+	}
+	for rest != "" {
+		segment := jsonPathSegment{index: -1}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			segment.recursive = true
+			rest = rest[2:]
+		case rest[0] == '.':
+			rest = rest[1:]
+		}
+		if rest == "" {
+			return invalid()
+		}
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return invalid()
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				segment.key = "*"
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segment.key = inner[1 : len(inner)-1]
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return invalid()
+				}
+				segment.index = index
+			}
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segment.key = rest[:end]
+			rest = rest[end:]
+			if segment.key == "" {
+				return invalid()
+			}
+		}
+		path.segments = append(path.segments, segment)
+	}
+	if len(path.segments) == 0 {
+		return invalid()
+	}
+	return path, nil
+}
+
+// apply replaces the values addressed by segments below value and returns how
+// many were replaced.
+func (path jsonPath) apply(value any, segments []jsonPathSegment, replacement string) int {
+	segment, rest := segments[0], segments[1:]
+	count := 0
+	visit := func(child any, set func(any)) bool {
+		if len(rest) == 0 {
+			set(replacement)
+			count++
+			return true
+		}
+		count += path.apply(child, rest, replacement)
+		return false
+	}
+	switch node := value.(type) {
+	case map[string]any:
+		for key, child := range node {
+			if segment.matchesKey(key) && visit(child, func(v any) { node[key] = v }) {
+				continue
+			}
+			if segment.recursive {
+				count += path.apply(child, segments, replacement)
+			}
+		}
+	case []any:
+		for i, child := range node {
+			if segment.matchesIndex(i) && visit(child, func(v any) { node[i] = v }) {
+				continue
+			}
+			if segment.recursive {
+				count += path.apply(child, segments, replacement)
+			}
+		}
+	}
+	return count
+}
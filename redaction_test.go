@@ -0,0 +1,272 @@
+package playwright
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go/har"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPathRedaction(t *testing.T) {
+	for _, tc := range []struct {
+		path     string
+		input    string
+		expected string
+		count    int
+	}{
+		{"password", `{"user":"a","password":"x"}`, `{"password":"R","user":"a"}`, 1},
+		{"$.user.token", `{"user":{"token":"t","id":1}}`, `{"user":{"id":1,"token":"R"}}`, 1},
+		{"items[*].secret", `{"items":[{"secret":1},{"secret":2},{}]}`, `{"items":[{"secret":"R"},{"secret":"R"},{}]}`, 2},
+		{"items[1]", `{"items":["a","b"]}`, `{"items":["a","R"]}`, 1},
+		{"$..apiKey", `{"apiKey":"a","nested":[{"apiKey":"b"}]}`, `{"apiKey":"R","nested":[{"apiKey":"R"}]}`, 2},
+		{"$['odd key']", `{"odd key":true}`, `{"odd key":"R"}`, 1},
+		{"missing.path", `{"a":1}`, `{"a":1}`, 0},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			path, err := parseJSONPath(tc.path)
+			require.NoError(t, err)
+			var value any
+			require.NoError(t, json.Unmarshal([]byte(tc.input), &value))
+			require.Equal(t, tc.count, path.apply(value, path.segments, "R"))
+			out, err := json.Marshal(value)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(out))
+		})
+	}
+	for _, invalid := range []string{"", "$", "a..", "a[", "a[x]"} {
+		_, err := parseJSONPath(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestRedactHarFile(t *testing.T) {
+	archive := har.New(har.Creator{Name: "test"})
+	archive.Log.Entries = append(archive.Log.Entries, &har.Entry{
+		StartedDateTime: time.Unix(0, 0),
+		Request: har.Request{
+			Method: "POST",
+			URL:    "https://example.com/login?session=abc&page=1",
+			Headers: []har.NameValue{
+				{Name: "Authorization", Value: "Bearer secret-token"},
+				{Name: "Cookie", Value: "sid=123; theme=dark"},
+				{Name: "X-Trace", Value: "key-AKIA1234"},
+			},
+			QueryString: []har.NameValue{{Name: "session", Value: "abc"}, {Name: "page", Value: "1"}},
+			PostData: &har.PostData{
+				MimeType: "application/x-www-form-urlencoded",
+				Text:     "user=bob&password=hunter2",
+				Params:   []har.Param{{Name: "user", Value: "bob"}, {Name: "password", Value: "hunter2"}},
+			},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Response: har.Response{
+			Status:  200,
+			Headers: []har.NameValue{{Name: "Set-Cookie", Value: "sid=456; Path=/\ntheme=light"}},
+			Cookies: []har.Cookie{{Name: "sid", Value: "456"}, {Name: "theme", Value: "light"}},
+			Content: har.Content{
+				MimeType: "application/json",
+				Text:     `{"token":"jwt","user":{"name":"bob"}}`,
+			},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	})
+	path := filepath.Join(t.TempDir(), "out.har")
+	require.NoError(t, archive.WriteFile(path))
+
+	var report *RedactionReport
+	r, err := newRedactor(&RedactionOptions{
+		Headers:    []string{"authorization"},
+		Cookies:    []string{"sid"},
+		JSONPaths:  []string{"token"},
+		FormFields: []string{"password", "session"},
+		Patterns:   []*regexp.Regexp{regexp.MustCompile(`AKIA\d+`)},
+		OnRedacted: func(r *RedactionReport) { report = r },
+	})
+	require.NoError(t, err)
+	require.NoError(t, r.redactHarFile(path))
+
+	redacted, err := har.ReadFile(path)
+	require.NoError(t, err)
+	entry := redacted.Log.Entries[0]
+	require.Equal(t, "https://example.com/login?session=%5BREDACTED%5D&page=1", entry.Request.URL)
+	require.Equal(t, "[REDACTED]", entry.Request.Header("authorization"))
+	require.Equal(t, "sid=[REDACTED]; theme=dark", entry.Request.Header("cookie"))
+	require.Equal(t, "key-[REDACTED]", entry.Request.Header("x-trace"))
+	require.Equal(t, "[REDACTED]", entry.Request.QueryString[0].Value)
+	require.Equal(t, "1", entry.Request.QueryString[1].Value)
+	require.Equal(t, "user=bob&password=%5BREDACTED%5D", entry.Request.PostData.Text)
+	require.Equal(t, "[REDACTED]", entry.Request.PostData.Params[1].Value)
+	require.Equal(t, "sid=[REDACTED]; Path=/\ntheme=light", entry.Response.Header("set-cookie"))
+	require.Equal(t, "[REDACTED]", entry.Response.Cookies[0].Value)
+	require.Equal(t, "light", entry.Response.Cookies[1].Value)
+	body, err := redacted.Body(entry)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"token":"[REDACTED]","user":{"name":"bob"}}`, string(body))
+
+	require.NotNil(t, report)
+	require.Equal(t, path, report.Path)
+	kinds := map[string]int{}
+	for _, redaction := range report.Redactions {
+		kinds[redaction.Kind]++
+		require.NotContains(t, redaction.Location, "abc")
+	}
+	require.Equal(t, map[string]int{"header": 1, "cookie": 3, "json": 1, "form": 4, "pattern": 1}, kinds)
+}
+
+func TestRedactHarFileRemovesAttachments(t *testing.T) {
+	dir := t.TempDir()
+	secret := []byte(`{"token":"jwt"}`)
+	original := "body.json"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, original), secret, 0o644))
+	archive := har.New(har.Creator{Name: "test"})
+	archive.Log.Entries = append(archive.Log.Entries, &har.Entry{
+		StartedDateTime: time.Unix(0, 0),
+		Request:         har.Request{Method: "GET", URL: "https://example.com/api", HeadersSize: -1, BodySize: -1},
+		Response: har.Response{
+			Status:      200,
+			Content:     har.Content{MimeType: "application/json", File: original},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	})
+	path := filepath.Join(dir, "out.har")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, archive.Write(f))
+	require.NoError(t, f.Close())
+
+	r, err := newRedactor(&RedactionOptions{JSONPaths: []string{"token"}})
+	require.NoError(t, err)
+	require.NoError(t, r.redactHarFile(path))
+
+	require.NoFileExists(t, filepath.Join(dir, original))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "jwt")
+	}
+	redacted, err := har.ReadFile(path)
+	require.NoError(t, err)
+	body, err := redacted.Body(redacted.Log.Entries[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"token":"[REDACTED]"}`, string(body))
+}
+
+func TestRedactTraceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.zip")
+	writeTestZip(t, path, map[string]string{
+		"trace.trace": strings.Join([]string{
+			`{"type":"before","callId":"call@1","method":"fill","params":{"selector":"#pw","value":"hunter2"}}`,
+			`{"type":"log","callId":"call@1","message":"  locator resolved to <input id=\"pw\" type=\"password\"/>"}`,
+			`{"type":"log","callId":"call@1","message":"  fill(\"hunter2\")"}`,
+			`{"type":"before","callId":"call@2","method":"fill","params":{"selector":"#user","value":"bob"}}`,
+			`{"type":"before","callId":"call@3","method":"setExtraHTTPHeaders","params":{"headers":[{"name":"Authorization","value":"Basic xyz"}]}}`,
+			`{"type":"frame-snapshot","snapshot":{"callId":"call@4","html":["HTML",{},["BODY",{},["INPUT",{"type":"Password","__playwright_value_":"s3cr3t"}],["INPUT",{"__playwright_value_":"visible"}]]]}}`,
+		}, "\n") + "\n",
+		"trace.network": unchangedNetworkLine + "\n" +
+			`{"type":"resource-snapshot","snapshot":{"_frameref":"frame@1","startedDateTime":"2024-01-01T00:00:00Z","time":1,` +
+			`"request":{"method":"GET","url":"https://example.com/api","httpVersion":"HTTP/1.1","cookies":[],` +
+			`"headers":[{"name":"Authorization","value":"Bearer t"}],"queryString":[],"headersSize":-1,"bodySize":0},` +
+			`"response":{"status":200,"statusText":"OK","httpVersion":"HTTP/1.1","cookies":[],"headers":[],` +
+			`"content":{"size":16,"mimeType":"application/json","_sha1":"abc.json"},"redirectURL":"","headersSize":-1,"bodySize":16},` +
+			`"cache":{},"timings":{"send":0,"wait":0,"receive":0}}}` + "\n",
+		"resources/abc.json": `{"secret":"s3"}`,
+	})
+
+	var report *RedactionReport
+	r, err := newRedactor(&RedactionOptions{
+		Headers:    []string{"Authorization"},
+		JSONPaths:  []string{"secret"},
+		OnRedacted: func(r *RedactionReport) { report = r },
+	})
+	require.NoError(t, err)
+	target := filepath.Join(filepath.Dir(path), "redacted.zip")
+	require.NoError(t, r.redactTraceFile(path, target))
+	require.Equal(t, target, report.Path)
+
+	files := readTestZip(t, target)
+	require.NotContains(t, files["trace.trace"], "hunter2")
+	require.Contains(t, files["trace.trace"], `"value":"bob"`)
+	require.NotContains(t, files["trace.trace"], "Basic xyz")
+	require.NotContains(t, files["trace.trace"], "s3cr3t")
+	require.Contains(t, files["trace.trace"], `"__playwright_value_":"visible"`)
+	require.NotContains(t, files["trace.network"], "Bearer t")
+	require.Contains(t, files["trace.network"], `"_sha1":"abc.json"`)
+	require.Contains(t, files["trace.network"], `"_frameref":"frame@1"`)
+	require.True(t, strings.HasPrefix(files["trace.network"], unchangedNetworkLine+"\n"))
+	require.JSONEq(t, `{"secret":"[REDACTED]"}`, files["resources/abc.json"])
+
+	kinds := map[string]int{}
+	for _, redaction := range report.Redactions {
+		kinds[redaction.Kind]++
+	}
+	require.Equal(t, map[string]int{"password": 2, "header": 2, "json": 1}, kinds)
+}
+
+// unchangedNetworkLine has fields har.Entry doesn't know and nothing to redact,
+// it must be written back as is.
+const unchangedNetworkLine = `{"type":"resource-snapshot","snapshot":{"_monotonicTime":12.5,"request":{"method":"GET",` +
+	`"url":"https://example.com/","headers":[{"name":"Accept","value":"*/*"}]},"response":{"status":200,"content":{}}}}`
+
+func TestSetRedactionConcurrentStop(t *testing.T) {
+	tracing := &tracingImpl{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, SetRedaction(tracing, &RedactionOptions{}))
+			require.NoError(t, SetRedaction(tracing, nil))
+		}
+	}()
+	// Run with -race: Stop and StopChunk read the rules while they are set.
+	for i := 0; i < 100; i++ {
+		_ = tracing.currentRedactor()
+	}
+	<-done
+	require.Nil(t, tracing.currentRedactor())
+}
+
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	writer := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, f.Close())
+}
+
+func readTestZip(t *testing.T, path string) map[string]string {
+	t.Helper()
+	reader, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	files := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[file.Name] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,228 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// BlockAdsAndAnalytics lists common advertising and analytics hosts, to be used
+// as [BlockResourcesOptions.Hosts].
+var BlockAdsAndAnalytics = []string{
+	"adnxs.com",
+	"adservice.google.com",
+	"ads-twitter.com",
+	"amazon-adsystem.com",
+	"amplitude.com",
+	"analytics.twitter.com",
+	"bat.bing.com",
+	"clarity.ms",
+	"connect.facebook.net",
+	"criteo.com",
+	"doubleclick.net",
+	"google-analytics.com",
+	"googleadservices.com",
+	"googlesyndication.com",
+	"googletagmanager.com",
+	"hotjar.com",
+	"mixpanel.com",
+	"outbrain.com",
+	"scorecardresearch.com",
+	"segment.com",
+	"segment.io",
+	"taboola.com",
+}
+
+// resourceTypeExtensions are the file extensions requests of a resource type
+// usually have, used to intercept only those requests.
+var resourceTypeExtensions = map[string][]string{
+	"image":      {"apng", "avif", "bmp", "gif", "ico", "jpeg", "jpg", "png", "svg", "tif", "tiff", "webp"},
+	"font":       {"eot", "otf", "ttf", "woff", "woff2"},
+	"media":      {"aac", "flac", "m3u8", "m4a", "m4v", "mov", "mp3", "mp4", "mpd", "oga", "ogg", "ogv", "opus", "ts", "wav", "weba", "webm"},
+	"stylesheet": {"css"},
+	"script":     {"js", "mjs"},
+	"manifest":   {"webmanifest"},
+}
+
+// BlockResourcesOptions are the options for [BlockResources].
+type BlockResourcesOptions struct {
+	// Resource types to block, as reported by [Request.ResourceType], e.g.
+	// "image", "font" or "media".
+	ResourceTypes []string
+	// Hosts to block, including their subdomains, e.g. [BlockAdsAndAnalytics].
+	Hosts []string
+	// Glob patterns of URLs to block.
+	URLs []string
+	// By default only requests whose URL has a file extension typical for one of
+	// ResourceTypes are sent to Go and checked, e.g. ".png" for "image". Set
+	// this to intercept and check every request, which also blocks resources
+	// without a known extension at the cost of a round trip per request.
+	CheckAllResourceTypes *bool
+	// The error code blocked requests are aborted with. Defaults to
+	// `blockedbyclient`, see [Route.Abort].
+	ErrorCode *string
+}
+
+// BlockedResources counts the requests blocked by [BlockResources].
+type BlockedResources struct {
+	Total          int
+	ByResourceType map[string]int
+	ByHost         map[string]int
+}
+
+// ResourceBlocker blocks requests, see [BlockResources].
+type ResourceBlocker struct {
+	router    Router
+	errorCode string
+	types     map[string]bool
+	routes    []resourceBlockerRoute
+
+	mu    sync.Mutex
+	stats BlockedResources
+}
+
+type resourceBlockerRoute struct {
+	url     any
+	handler func(Route)
+}
+
+// BlockResources aborts requests to router by resource type, host or URL, e.g.
+// to speed up scraping:
+//
+//	blocker, _ := playwright.BlockResources(context, playwright.BlockResourcesOptions{
+//		ResourceTypes: []string{"image", "font", "media"},
+//		Hosts:         playwright.BlockAdsAndAnalytics,
+//	})
+//
+// Hosts and URLs are compiled into routes with a regular expression and globs,
+// so requests that don't match them never reach Go. Resource types are only
+// known once a request is intercepted; see
+// [BlockResourcesOptions.CheckAllResourceTypes].
+func BlockResources(router Router, options BlockResourcesOptions) (*ResourceBlocker, error) {
+	b := &ResourceBlocker{
+		router:    router,
+		errorCode: "blockedbyclient",
+		types:     make(map[string]bool),
+		stats:     BlockedResources{ByResourceType: map[string]int{}, ByHost: map[string]int{}},
+	}
+	if options.ErrorCode != nil {
+		b.errorCode = *options.ErrorCode
+	}
+	for _, resourceType := range options.ResourceTypes {
+		b.types[strings.ToLower(resourceType)] = true
+	}
+	if len(b.types) > 0 {
+		var pattern any
+		if options.CheckAllResourceTypes != nil && *options.CheckAllResourceTypes {
+			pattern = "**/*"
+		} else if extensions := resourceTypesExtensionRegexp(options.ResourceTypes); extensions != nil {
+			pattern = extensions
+		}
+		if pattern == nil {
+			return nil, fmt.Errorf("no known file extensions for resource types %v, set CheckAllResourceTypes", options.ResourceTypes)
+		}
+		b.routes = append(b.routes, resourceBlockerRoute{pattern, b.blockResourceType})
+	}
+	if len(options.Hosts) > 0 {
+		hosts, err := hostsRegexp(options.Hosts)
+		if err != nil {
+			return nil, err
+		}
+		b.routes = append(b.routes, resourceBlockerRoute{hosts, b.block})
+	}
+	for _, glob := range options.URLs {
+		b.routes = append(b.routes, resourceBlockerRoute{glob, b.block})
+	}
+	for i, route := range b.routes {
+		if err := router.Route(route.url, route.handler); err != nil {
+			b.routes = b.routes[:i]
+			return nil, errors.Join(err, b.Stop())
+		}
+	}
+	return b, nil
+}
+
+func (b *ResourceBlocker) blockResourceType(route Route) {
+	if !b.types[route.Request().ResourceType()] {
+		if err := route.Fallback(); err != nil {
+			logger.Error("Error blocking resource", "error", err)
+		}
+		return
+	}
+	b.block(route)
+}
+
+func (b *ResourceBlocker) block(route Route) {
+	request := route.Request()
+	host := ""
+	if u, err := url.Parse(request.URL()); err == nil {
+		host = u.Hostname()
+	}
+	b.mu.Lock()
+	b.stats.Total++
+	b.stats.ByResourceType[request.ResourceType()]++
+	b.stats.ByHost[host]++
+	b.mu.Unlock()
+	if err := route.Abort(b.errorCode); err != nil {
+		logger.Error("Error blocking resource", "error", err)
+	}
+}
+
+// Blocked returns the counts of the requests blocked so far.
+func (b *ResourceBlocker) Blocked() BlockedResources {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := BlockedResources{
+		Total:          b.stats.Total,
+		ByResourceType: make(map[string]int, len(b.stats.ByResourceType)),
+		ByHost:         make(map[string]int, len(b.stats.ByHost)),
+	}
+	for resourceType, count := range b.stats.ByResourceType {
+		stats.ByResourceType[resourceType] = count
+	}
+	for host, count := range b.stats.ByHost {
+		stats.ByHost[host] = count
+	}
+	return stats
+}
+
+// Stop removes the routes, so requests are no longer blocked.
+func (b *ResourceBlocker) Stop() error {
+	var errs []error
+	for _, route := range b.routes {
+		errs = append(errs, b.router.Unroute(route.url, route.handler))
+	}
+	b.routes = nil
+	return errors.Join(errs...)
+}
+
+// resourceTypesExtensionRegexp matches URLs whose path ends with an extension
+// of one of the resource types, or returns nil if none is known.
+func resourceTypesExtensionRegexp(resourceTypes []string) *regexp.Regexp {
+	var extensions []string
+	for _, resourceType := range resourceTypes {
+		extensions = append(extensions, resourceTypeExtensions[strings.ToLower(resourceType)]...)
+	}
+	if len(extensions) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)^[^?#]*\.(?:` + strings.Join(extensions, "|") + `)(?:[?#]|$)`)
+}
+
+// hostsRegexp matches URLs whose host is one of hosts or a subdomain of it. It
+// only uses syntax that is the same in Go and JavaScript, so it can be used as
+// an interception pattern.
+func hostsRegexp(hosts []string) (*regexp.Regexp, error) {
+	quoted := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		host = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(host)), "*.")
+		if host == "" || strings.ContainsAny(host, "/:?#") {
+			return nil, fmt.Errorf("invalid host %q", host)
+		}
+		quoted = append(quoted, regexp.QuoteMeta(host))
+	}
+	return regexp.Compile(`(?i)^[a-z][a-z0-9+.-]*://(?:[^/?#]*@)?(?:[^/?#@]*\.)?(?:` + strings.Join(quoted, "|") + `)(?::[0-9]+)?(?:[/?#]|$)`)
+}
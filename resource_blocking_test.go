@@ -0,0 +1,86 @@
+package playwright
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceTypesExtensionRegexp(t *testing.T) {
+	require.Nil(t, resourceTypesExtensionRegexp([]string{"xhr"}))
+	re := resourceTypesExtensionRegexp([]string{"image", "Font"})
+	for _, u := range []string{
+		"https://example.com/logo.png",
+		"https://example.com/a/b/photo.JPG?w=100",
+		"https://example.com/font.woff2#x",
+	} {
+		require.True(t, re.MatchString(u), u)
+	}
+	for _, u := range []string{
+		"https://example.com/",
+		"https://example.com/app.js",
+		"https://example.com/page?img=logo.png",
+		"https://example.com/pngs",
+	} {
+		require.False(t, re.MatchString(u), u)
+	}
+	pattern, flags := convertRegexp(re)
+	require.Equal(t, "i", flags)
+	require.NotContains(t, pattern, "(?i)")
+}
+
+func TestHostsRegexp(t *testing.T) {
+	re, err := hostsRegexp([]string{"doubleclick.net", "*.Example.com"})
+	require.NoError(t, err)
+	for _, u := range []string{
+		"https://doubleclick.net/",
+		"https://ad.doubleclick.net/pixel?x=1",
+		"http://user@stats.example.com:8080",
+		"wss://example.com/socket",
+	} {
+		require.True(t, re.MatchString(u), u)
+	}
+	for _, u := range []string{
+		"https://notdoubleclick.net/",
+		"https://doubleclick.net.evil.com/",
+		"https://example.org/?ref=example.com",
+		"https://other.com/doubleclick.net",
+	} {
+		require.False(t, re.MatchString(u), u)
+	}
+	_, err = hostsRegexp([]string{"https://example.com"})
+	require.Error(t, err)
+}
+
+type fakeBlockedRequest struct {
+	Request
+	url          string
+	resourceType string
+}
+
+func (r *fakeBlockedRequest) URL() string          { return r.url }
+func (r *fakeBlockedRequest) ResourceType() string { return r.resourceType }
+
+func TestResourceBlockerCounts(t *testing.T) {
+	b := &ResourceBlocker{
+		errorCode: "blockedbyclient",
+		types:     map[string]bool{"image": true},
+		stats:     BlockedResources{ByResourceType: map[string]int{}, ByHost: map[string]int{}},
+	}
+	image := &fakeEmulationRoute{request: &fakeBlockedRequest{url: "https://cdn.example.com/a.png", resourceType: "image"}}
+	b.blockResourceType(image)
+	require.Equal(t, []string{"abort:blockedbyclient"}, image.actions)
+	script := &fakeEmulationRoute{request: &fakeBlockedRequest{url: "https://cdn.example.com/a.png", resourceType: "script"}}
+	b.blockResourceType(script)
+	require.Equal(t, []string{"fallback"}, script.actions)
+	b.block(&fakeEmulationRoute{request: &fakeBlockedRequest{url: "https://doubleclick.net/x", resourceType: "script"}})
+
+	stats := b.Blocked()
+	require.Equal(t, BlockedResources{
+		Total:          2,
+		ByResourceType: map[string]int{"image": 1, "script": 1},
+		ByHost:         map[string]int{"cdn.example.com": 1, "doubleclick.net": 1},
+	}, stats)
+	stats.ByHost["other"] = 1
+	require.Len(t, b.Blocked().ByHost, 2)
+}
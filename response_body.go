@@ -0,0 +1,59 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SaveBodyAs writes the body of response, an [APIResponse] or a [Response], to
+// path. The directory of path is created if needed.
+//
+// The driver sends a body in a single message, so it is held in memory as a
+// whole, as with Body. It is written to a temporary file next to path that is
+// renamed into place, so path never holds a partial body. Use
+// [Download.SaveAs] for files too large for memory, which reads them from the
+// driver in chunks.
+func SaveBodyAs(response any, path string) error {
+	var (
+		body []byte
+		err  error
+	)
+	switch r := response.(type) {
+	case APIResponse:
+		body, err = r.Body()
+	case Response:
+		body, err = r.Body()
+	default:
+		return fmt.Errorf("response must be an APIResponse or a Response, got %T", response)
+	}
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, body)
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path and
+// renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(file.Name()))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package playwright
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBodyResponse struct {
+	APIResponse
+	body []byte
+}
+
+func (r *fakeBodyResponse) Body() ([]byte, error) { return r.body, nil }
+
+func TestSaveBodyAs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "body.txt")
+	require.NoError(t, SaveBodyAs(&fakeBodyResponse{body: []byte("hello")}, path))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(content))
+	// The temporary file was renamed.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	err = SaveBodyAs("hello", path)
+	require.ErrorContains(t, err, "must be an APIResponse or a Response")
+}
+
+func TestWriteFileAtomicKeepsTargetOnError(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, "keep"), nil, 0o644))
+	// Renaming a file over a non-empty directory fails.
+	require.Error(t, writeFileAtomic(target, []byte("x")))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
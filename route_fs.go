@@ -0,0 +1,135 @@
+package playwright
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// RouteFSOptions are the options for [RouteFS].
+type RouteFSOptions struct {
+	// File served for paths that don't exist, so client-side routing of single page
+	// applications works. Only paths whose last segment has no file extension fall
+	// back, missing assets still answer with 404. Defaults to `index.html`, pass an
+	// empty string to disable the fallback.
+	Fallback *string
+}
+
+// RouteFS serves the files of fsys under baseURL, e.g. an [embed.FS] of a built
+// front-end, so it can be tested without starting a server. baseURL does not
+// need to resolve; requests outside of it are not intercepted.
+//
+// Content types are derived from file extensions. Directories serve their
+// index.html, and Range, HEAD and conditional requests are answered as by
+// [http.ServeContent]. Missing files answer with 404 and methods other than GET
+// and HEAD with 405.
+func RouteFS(router Router, baseURL string, fsys fs.FS, options ...RouteFSOptions) error {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/") + "/"
+	base.RawQuery = ""
+	base.Fragment = ""
+	handler := &fsHandler{
+		fsys:     fsys,
+		prefix:   base.Path,
+		fallback: "index.html",
+	}
+	if len(options) == 1 && options[0].Fallback != nil {
+		handler.fallback = strings.TrimPrefix(*options[0].Fallback, "/")
+	}
+	origin := base.String()
+	return RouteToHandler(router, func(u string) bool {
+		return strings.HasPrefix(u, origin) || u+"/" == origin
+	}, handler)
+}
+
+type fsHandler struct {
+	fsys     fs.FS
+	prefix   string
+	fallback string
+}
+
+func (h *fsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(r.URL.Path, h.prefix)), "/")
+	if name == "" {
+		name = "."
+	}
+	if h.serveFile(w, r, name) {
+		return
+	}
+	if h.fallback != "" && path.Ext(name) == "" && h.serveFile(w, r, h.fallback) {
+		return
+	}
+	http.Error(w, "Not Found", http.StatusNotFound)
+}
+
+// serveFile serves name, or the index.html of a directory. It reports false if
+// there is no such file.
+func (h *fsHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		name = path.Join(name, "index.html")
+		if info, err = fs.Stat(h.fsys, name); err != nil || info.IsDir() {
+			return false
+		}
+	}
+	content, err := h.open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return true
+	}
+	if closer, ok := content.(io.Closer); ok {
+		defer closer.Close() //nolint:errcheck
+	}
+	contentType := getMimeTypeForPath(path.Base(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return true
+}
+
+// open returns a seekable reader for name, buffering files that can't seek.
+func (h *fsHandler) open(name string) (io.ReadSeeker, error) {
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if seeker, ok := f.(io.ReadSeeker); ok {
+		return readSeekCloser{seeker, f}, nil
+	}
+	defer f.Close() //nolint:errcheck
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+type readSeekCloser struct {
+	io.ReadSeeker
+	io.Closer
+}
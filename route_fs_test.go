@@ -0,0 +1,72 @@
+package playwright
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":       {Data: []byte("<h1>app</h1>")},
+		"assets/app.js":    {Data: []byte("console.log('app')")},
+		"assets/data.bin":  {Data: []byte("0123456789")},
+		"docs/index.html":  {Data: []byte("<h1>docs</h1>")},
+		"empty/readme.txt": {Data: []byte("nothing here")},
+	}
+	handler := &fsHandler{fsys: fsys, prefix: "/app/", fallback: "index.html"}
+	serve := func(method, target string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("GET", "http://app.local/app/")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "<h1>app</h1>", w.Body.String())
+	require.Equal(t, "text/html", w.Header().Get("Content-Type"))
+
+	w = serve("GET", "http://app.local/app/assets/app.js")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/javascript", w.Header().Get("Content-Type"))
+
+	w = serve("GET", "http://app.local/app/assets/data.bin", "Range", "bytes=2-4")
+	require.Equal(t, http.StatusPartialContent, w.Code)
+	require.Equal(t, "234", w.Body.String())
+	require.Equal(t, "bytes 2-4/10", w.Header().Get("Content-Range"))
+	require.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+
+	w = serve("GET", "http://app.local/app/docs")
+	require.Equal(t, "<h1>docs</h1>", w.Body.String())
+
+	w = serve("GET", "http://app.local/app/users/42")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "<h1>app</h1>", w.Body.String())
+
+	w = serve("GET", "http://app.local/app/assets/missing.js")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve("GET", "http://app.local/app/../../etc/passwd.txt")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve("HEAD", "http://app.local/app/assets/app.js")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Body.String())
+
+	w = serve("POST", "http://app.local/app/")
+	require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	require.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+
+	handler.fallback = ""
+	w = serve("GET", "http://app.local/app/users/42")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = serve("GET", "http://app.local/app/empty/")
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,291 @@
+package playwright
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrGraphQLFallback is returned by a [GraphQLHandler] to pass the operation to
+	// the next matching route handler, see [Route.Fallback].
+	ErrGraphQLFallback = errors.New("graphql: fallback")
+	// ErrGraphQLContinue is returned by a [GraphQLHandler] to send the operation to
+	// the server, see [Route.Continue].
+	ErrGraphQLContinue = errors.New("graphql: continue")
+)
+
+// GraphQLRequest is a single GraphQL operation sent to an endpoint.
+type GraphQLRequest struct {
+	// OperationName is the name of the operation, taken from the operationName
+	// field or else from the query.
+	OperationName string `json:"operationName,omitempty"`
+	Query         string `json:"query,omitempty"`
+	// Variables are decoded from JSON, numbers are float64.
+	Variables  map[string]any `json:"variables,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+	// Request is the intercepted HTTP request, shared by all operations of a batch.
+	Request Request `json:"-"`
+}
+
+// GraphQLResponse is the result of a GraphQL operation.
+type GraphQLResponse struct {
+	Data       any            `json:"data"`
+	Errors     []GraphQLError `json:"errors,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLError is an entry of the errors list of a [GraphQLResponse].
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLHandler answers a GraphQL operation. Returning [ErrGraphQLFallback] or
+// [ErrGraphQLContinue] hands the operation on; any other error is answered with
+// a GraphQL error carrying its message.
+type GraphQLHandler func(request *GraphQLRequest) (*GraphQLResponse, error)
+
+// GraphQLRoute answers the GraphQL operations sent to an endpoint, see
+// [RouteGraphQL].
+type GraphQLRoute struct {
+	router   Router
+	endpoint any
+	// route is the handler registered with the router.
+	route func(Route)
+
+	mu       sync.Mutex
+	handlers []*graphQLOperationHandler
+}
+
+// RouteGraphQL routes the GraphQL requests sent to endpoint, a glob pattern,
+// regular expression or predicate as accepted by [Page.Route]. Operations are
+// answered by the handlers added with [GraphQLRoute.Handle]:
+//
+//	graphql, _ := playwright.RouteGraphQL(page, "**/graphql")
+//	graphql.Handle("GetUser", getUser)
+//	graphql.Handle("DeleteUser", deleteUser)
+//
+// Operations are read from JSON POST bodies, application/graphql POST bodies
+// and GET query parameters. A request for an operation without a handler falls
+// back to the previously registered route handlers, see [Route.Fallback].
+//
+// Batched requests, whose body is a JSON array of operations, are answered with
+// an array of results. Operations of a batch that no handler answers are fetched
+// from the server in a single request and merged into the result.
+func RouteGraphQL(router Router, endpoint any) (*GraphQLRoute, error) {
+	g := &GraphQLRoute{router: router, endpoint: endpoint}
+	g.route = func(route Route) {
+		if err := g.handle(route); err != nil {
+			logger.Error("Error handling GraphQL route", "error", err)
+		}
+	}
+	if err := router.Route(endpoint, g.route); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Handle answers the operation operationName with handler, "*" matches every
+// operation. Handlers added later take precedence; one returning
+// [ErrGraphQLFallback] hands the operation to the previously added handler
+// matching it.
+func (g *GraphQLRoute) Handle(operationName string, handler GraphQLHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.handlers = append(g.handlers, &graphQLOperationHandler{name: operationName, handler: handler})
+}
+
+// Unroute removes the route from the router.
+func (g *GraphQLRoute) Unroute() error {
+	return g.router.Unroute(g.endpoint, g.route)
+}
+
+type graphQLOperationHandler struct {
+	name    string
+	handler GraphQLHandler
+}
+
+func (h *graphQLOperationHandler) matches(operationName string) bool {
+	return h.name == "*" || h.name == operationName
+}
+
+// answer runs the handlers matching the operation, most recently added first,
+// until one doesn't fall back. It returns [ErrGraphQLFallback] if none answers.
+func (g *GraphQLRoute) answer(request *GraphQLRequest) (*GraphQLResponse, error) {
+	g.mu.Lock()
+	handlers := slices.Clone(g.handlers)
+	g.mu.Unlock()
+	for i := len(handlers) - 1; i >= 0; i-- {
+		if !handlers[i].matches(request.OperationName) {
+			continue
+		}
+		response, err := handlers[i].handler(request)
+		if !errors.Is(err, ErrGraphQLFallback) {
+			return response, err
+		}
+	}
+	return nil, ErrGraphQLFallback
+}
+
+func (g *GraphQLRoute) handle(route Route) error {
+	requests, batch, err := parseGraphQLRequests(route.Request())
+	if err != nil || len(requests) == 0 {
+		// Not a GraphQL request, e.g. a CORS preflight.
+		return route.Fallback()
+	}
+	if !batch {
+		response, err := g.answer(requests[0])
+		switch {
+		case errors.Is(err, ErrGraphQLFallback):
+			return route.Fallback()
+		case errors.Is(err, ErrGraphQLContinue):
+			return route.Continue()
+		}
+		return fulfillGraphQL(route, graphQLResult(response, err))
+	}
+
+	results := make([]any, len(requests))
+	var pending []int
+	for i, request := range requests {
+		response, err := g.answer(request)
+		if errors.Is(err, ErrGraphQLFallback) || errors.Is(err, ErrGraphQLContinue) {
+			pending = append(pending, i)
+			continue
+		}
+		results[i] = graphQLResult(response, err)
+	}
+	if len(pending) == len(requests) {
+		return route.Fallback()
+	}
+	if len(pending) > 0 {
+		if err := fetchGraphQLBatch(route, requests, pending, results); err != nil {
+			return errors.Join(err, route.Abort())
+		}
+	}
+	return fulfillGraphQL(route, results)
+}
+
+// fetchGraphQLBatch sends the pending operations to the server as one batch and
+// stores their results.
+func fetchGraphQLBatch(route Route, requests []*GraphQLRequest, pending []int, results []any) error {
+	batch := make([]*GraphQLRequest, len(pending))
+	for i, index := range pending {
+		batch[i] = requests[index]
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	response, err := route.Fetch(RouteFetchOptions{
+		Method:   String("POST"),
+		PostData: body,
+	})
+	if err != nil {
+		return err
+	}
+	var fetched []json.RawMessage
+	if err := response.JSON(&fetched); err != nil {
+		return err
+	}
+	if len(fetched) != len(pending) {
+		return fmt.Errorf("graphql: server answered %d of %d batched operations", len(fetched), len(pending))
+	}
+	for i, index := range pending {
+		results[index] = fetched[i]
+	}
+	return nil
+}
+
+func graphQLResult(response *GraphQLResponse, err error) *GraphQLResponse {
+	if err != nil {
+		return &GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}}
+	}
+	if response == nil {
+		return &GraphQLResponse{Data: nil}
+	}
+	return response
+}
+
+func fulfillGraphQL(route Route, result any) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	return route.Fulfill(RouteFulfillOptions{
+		Status:      Int(200),
+		ContentType: String("application/json"),
+		Body:        body,
+	})
+}
+
+// parseGraphQLRequests reads the operations of a request. It reports whether the
+// request is a batch.
+func parseGraphQLRequests(request Request) ([]*GraphQLRequest, bool, error) {
+	var requests []*GraphQLRequest
+	batch := false
+	switch request.Method() {
+	case "GET":
+		u, err := url.Parse(request.URL())
+		if err != nil {
+			return nil, false, err
+		}
+		query := u.Query()
+		if !query.Has("query") {
+			return nil, false, nil
+		}
+		gqlRequest := &GraphQLRequest{Query: query.Get("query"), OperationName: query.Get("operationName")}
+		for name, target := range map[string]*map[string]any{"variables": &gqlRequest.Variables, "extensions": &gqlRequest.Extensions} {
+			if value := query.Get(name); value != "" {
+				if err := json.Unmarshal([]byte(value), target); err != nil {
+					return nil, false, fmt.Errorf("graphql: invalid %s: %w", name, err)
+				}
+			}
+		}
+		requests = append(requests, gqlRequest)
+	case "POST":
+		body, err := request.PostDataBuffer()
+		if err != nil {
+			return nil, false, err
+		}
+		contentType, _ := request.HeaderValue("content-type")
+		if strings.HasPrefix(contentType, "application/graphql") {
+			requests = append(requests, &GraphQLRequest{Query: string(body)})
+			break
+		}
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			batch = true
+			if err := json.Unmarshal(body, &requests); err != nil {
+				return nil, false, err
+			}
+		} else {
+			gqlRequest := &GraphQLRequest{}
+			if err := json.Unmarshal(body, gqlRequest); err != nil {
+				return nil, false, err
+			}
+			requests = append(requests, gqlRequest)
+		}
+	default:
+		return nil, false, nil
+	}
+	for i, gqlRequest := range requests {
+		if gqlRequest == nil {
+			gqlRequest = &GraphQLRequest{}
+			requests[i] = gqlRequest
+		}
+		gqlRequest.Request = request
+		if gqlRequest.OperationName == "" {
+			if match := graphQLOperationRegexp.FindStringSubmatch(gqlRequest.Query); match != nil {
+				gqlRequest.OperationName = match[1]
+			}
+		}
+	}
+	return requests, batch, nil
+}
@@ -0,0 +1,159 @@
+package playwright
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGraphQLRequest struct {
+	Request
+	method      string
+	url         string
+	body        []byte
+	contentType string
+}
+
+func (r *fakeGraphQLRequest) Method() string                  { return r.method }
+func (r *fakeGraphQLRequest) URL() string                     { return r.url }
+func (r *fakeGraphQLRequest) PostDataBuffer() ([]byte, error) { return r.body, nil }
+func (r *fakeGraphQLRequest) HeaderValue(name string) (string, error) {
+	if name == "content-type" {
+		return r.contentType, nil
+	}
+	return "", nil
+}
+
+func TestParseGraphQLRequests(t *testing.T) {
+	request := &fakeGraphQLRequest{
+		method:      "POST",
+		url:         "https://api.example.com/graphql",
+		body:        []byte(`{"query":"query GetUser($id: ID!) { user(id: $id) { name } }","variables":{"id":"1"}}`),
+		contentType: "application/json",
+	}
+	requests, batch, err := parseGraphQLRequests(request)
+	require.NoError(t, err)
+	require.False(t, batch)
+	require.Len(t, requests, 1)
+	require.Equal(t, "GetUser", requests[0].OperationName)
+	require.Equal(t, map[string]any{"id": "1"}, requests[0].Variables)
+	require.Equal(t, request, requests[0].Request)
+
+	requests, batch, err = parseGraphQLRequests(&fakeGraphQLRequest{
+		method:      "POST",
+		body:        []byte(` [{"operationName":"A","query":"query X { a }"}, null, {"query":"mutation B { b }"}]`),
+		contentType: "application/json",
+	})
+	require.NoError(t, err)
+	require.True(t, batch)
+	require.Len(t, requests, 3)
+	require.Equal(t, "A", requests[0].OperationName)
+	require.Equal(t, "", requests[1].OperationName)
+	require.Equal(t, "B", requests[2].OperationName)
+
+	requests, _, err = parseGraphQLRequests(&fakeGraphQLRequest{
+		method:      "POST",
+		body:        []byte(`subscription OnEvent { event }`),
+		contentType: "application/graphql; charset=utf-8",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "OnEvent", requests[0].OperationName)
+
+	query := url.Values{
+		"query":     {"{ viewer { id } }"},
+		"variables": {`{"first":10}`},
+	}
+	requests, _, err = parseGraphQLRequests(&fakeGraphQLRequest{
+		method: "GET",
+		url:    "https://api.example.com/graphql?" + query.Encode(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "", requests[0].OperationName)
+	require.Equal(t, map[string]any{"first": float64(10)}, requests[0].Variables)
+
+	requests, _, err = parseGraphQLRequests(&fakeGraphQLRequest{method: "GET", url: "https://api.example.com/graphql"})
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	requests, _, err = parseGraphQLRequests(&fakeGraphQLRequest{method: "OPTIONS"})
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	_, _, err = parseGraphQLRequests(&fakeGraphQLRequest{method: "POST", body: []byte(`not json`)})
+	require.Error(t, err)
+}
+
+type fakeGraphQLRouter struct {
+	Router
+	routes   []func(Route)
+	unrouted []func(Route)
+}
+
+func (r *fakeGraphQLRouter) Route(url any, handler func(Route), times ...int) error {
+	r.routes = append(r.routes, handler)
+	return nil
+}
+
+func (r *fakeGraphQLRouter) Unroute(url any, handlers ...func(Route)) error {
+	r.unrouted = append(r.unrouted, handlers...)
+	return nil
+}
+
+func TestGraphQLRouteAnswer(t *testing.T) {
+	graphql, err := RouteGraphQL(&fakeGraphQLRouter{}, "**/graphql")
+	require.NoError(t, err)
+	answer := func(name string) GraphQLHandler {
+		return func(request *GraphQLRequest) (*GraphQLResponse, error) {
+			return &GraphQLResponse{Data: name}, nil
+		}
+	}
+	_, err = graphql.answer(&GraphQLRequest{OperationName: "GetUser"})
+	require.ErrorIs(t, err, ErrGraphQLFallback)
+
+	graphql.Handle("GetUser", answer("getUser"))
+	graphql.Handle("*", answer("all"))
+	response, err := graphql.answer(&GraphQLRequest{OperationName: "GetUser"})
+	require.NoError(t, err)
+	require.Equal(t, "all", response.Data)
+
+	graphql.Handle("GetUser", func(request *GraphQLRequest) (*GraphQLResponse, error) {
+		return nil, ErrGraphQLFallback
+	})
+	response, err = graphql.answer(&GraphQLRequest{OperationName: "GetUser"})
+	require.NoError(t, err)
+	require.Equal(t, "all", response.Data)
+
+	graphql.Handle("ListUsers", func(request *GraphQLRequest) (*GraphQLResponse, error) {
+		return nil, ErrGraphQLContinue
+	})
+	_, err = graphql.answer(&GraphQLRequest{OperationName: "ListUsers"})
+	require.ErrorIs(t, err, ErrGraphQLContinue)
+}
+
+func TestRouteGraphQLUnroute(t *testing.T) {
+	router := &fakeGraphQLRouter{}
+	first, err := RouteGraphQL(router, "**/graphql")
+	require.NoError(t, err)
+	// Routes created from the same function literal are told apart.
+	second, err := RouteGraphQL(router, func(string) bool { return true })
+	require.NoError(t, err)
+	require.Len(t, router.routes, 2)
+
+	require.NoError(t, second.Unroute())
+	require.Len(t, router.unrouted, 1)
+	require.Equal(t, funcIdentity(router.routes[1]), funcIdentity(router.unrouted[0]))
+	require.NotEqual(t, funcIdentity(first.route), funcIdentity(router.unrouted[0]))
+}
+
+func TestGraphQLResult(t *testing.T) {
+	result := graphQLResult(nil, ErrGraphQLFallback)
+	require.Equal(t, []GraphQLError{{Message: "graphql: fallback"}}, result.Errors)
+	require.NotNil(t, graphQLResult(nil, nil))
+	body, err := json.Marshal(graphQLResult(nil, nil))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data":null}`, string(body))
+	response := &GraphQLResponse{Data: map[string]any{"a": 1}}
+	require.Same(t, response, graphQLResult(response, nil))
+}
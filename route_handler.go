@@ -0,0 +1,155 @@
+package playwright
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RouteToHandler fulfills requests to router matching url with the responses of
+// handler, without opening a port. url is a glob pattern, regular expression or
+// predicate as accepted by [Page.Route].
+//
+// Each intercepted request is converted into an *http.Request carrying the
+// method, URL, headers and post data of the original request, and handler
+// writes to an in-memory [http.ResponseWriter]. If the handler does not set a
+// Content-Type it is sniffed from the body, like [http.Server] does. A handler
+// panicking with [http.ErrAbortHandler] aborts the request.
+func RouteToHandler(router Router, url any, handler http.Handler) error {
+	return router.Route(url, func(route Route) {
+		if err := serveRouteFromHandler(route, handler); err != nil {
+			logger.Error("Error handling route with http.Handler", "error", err)
+		}
+	})
+}
+
+func serveRouteFromHandler(route Route, handler http.Handler) error {
+	req, err := newHTTPRequestFromRoute(route.Request())
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	w := newRouteResponseWriter()
+	if err := serveHTTPRecovered(handler, w, req); err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	return route.Fulfill(w.fulfillOptions())
+}
+
+func serveHTTPRecovered(handler http.Handler, w http.ResponseWriter, req *http.Request) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r == http.ErrAbortHandler {
+				err = http.ErrAbortHandler
+				return
+			}
+			err = fmt.Errorf("http handler panicked: %v", r)
+		}
+	}()
+	handler.ServeHTTP(w, req)
+	return nil
+}
+
+// newHTTPRequestFromRoute converts an intercepted request into an *http.Request
+// as a server would receive it.
+func newHTTPRequestFromRoute(request Request) (*http.Request, error) {
+	body, err := request.PostDataBuffer()
+	if err != nil {
+		return nil, err
+	}
+	headers, err := request.AllHeaders()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), request.Method(), request.URL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	req.RequestURI = req.URL.RequestURI()
+	req.RemoteAddr = "127.0.0.1:0"
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+	}
+	return req, nil
+}
+
+// routeResponseWriter is an in-memory [http.ResponseWriter].
+type routeResponseWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newRouteResponseWriter() *routeResponseWriter {
+	return &routeResponseWriter{header: make(http.Header)}
+}
+
+func (w *routeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *routeResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader || status < 200 {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *routeResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.header.Get("Content-Type") == "" && w.body.Len() == 0 && len(p) > 0 && w.header.Get("Transfer-Encoding") == "" {
+		w.header.Set("Content-Type", http.DetectContentType(p))
+	}
+	return w.body.Write(p)
+}
+
+// Flush implements [http.Flusher]; the response is only sent once the handler
+// returns.
+func (w *routeResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (w *routeResponseWriter) fulfillOptions() RouteFulfillOptions {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return RouteFulfillOptions{
+		Status:  Int(status),
+		Headers: httpHeaderToMap(w.header),
+		Body:    w.body.Bytes(),
+	}
+}
+
+// httpHeaderToMap flattens h the way route.Fulfill expects: Set-Cookie values are
+// joined by newlines, other repeated headers by commas.
+func httpHeaderToMap(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for name, values := range h {
+		if strings.EqualFold(name, "Content-Length") || strings.EqualFold(name, "Transfer-Encoding") {
+			continue
+		}
+		separator := ", "
+		if strings.EqualFold(name, "Set-Cookie") {
+			separator = "\n"
+		}
+		headers[name] = strings.Join(values, separator)
+	}
+	return headers
+}
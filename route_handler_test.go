@@ -0,0 +1,89 @@
+package playwright
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHandlerRequest struct {
+	Request
+	method  string
+	url     string
+	body    []byte
+	headers map[string]string
+}
+
+func (r *fakeHandlerRequest) Method() string                         { return r.method }
+func (r *fakeHandlerRequest) URL() string                            { return r.url }
+func (r *fakeHandlerRequest) PostDataBuffer() ([]byte, error)        { return r.body, nil }
+func (r *fakeHandlerRequest) AllHeaders() (map[string]string, error) { return r.headers, nil }
+
+func TestNewHTTPRequestFromRoute(t *testing.T) {
+	req, err := newHTTPRequestFromRoute(&fakeHandlerRequest{
+		method: "POST",
+		url:    "https://api.example.com/items?limit=2",
+		body:   []byte(`{"name":"x"}`),
+		headers: map[string]string{
+			"host":         "api.example.com",
+			"content-type": "application/json",
+			"cookie":       "a=1; b=2",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "POST", req.Method)
+	require.Equal(t, "api.example.com", req.Host)
+	require.Equal(t, "/items?limit=2", req.RequestURI)
+	require.Equal(t, "2", req.URL.Query().Get("limit"))
+	require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	require.Empty(t, req.Header.Get("Host"))
+	cookie, err := req.Cookie("b")
+	require.NoError(t, err)
+	require.Equal(t, "2", cookie.Value)
+	require.Equal(t, int64(12), req.ContentLength)
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"x"}`, string(body))
+
+	req, err = newHTTPRequestFromRoute(&fakeHandlerRequest{method: "GET", url: "https://example.com/"})
+	require.NoError(t, err)
+	require.Equal(t, http.NoBody, req.Body)
+}
+
+func TestRouteResponseWriter(t *testing.T) {
+	w := newRouteResponseWriter()
+	http.SetCookie(w, &http.Cookie{Name: "a", Value: "1"})
+	http.SetCookie(w, &http.Cookie{Name: "b", Value: "2"})
+	w.Header().Add("Vary", "Accept")
+	w.Header().Add("Vary", "Cookie")
+	w.Header().Set("Content-Length", "999")
+	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, err := w.Write([]byte("<html><body>hi</body></html>"))
+	require.NoError(t, err)
+
+	options := w.fulfillOptions()
+	require.Equal(t, http.StatusCreated, *options.Status)
+	require.Equal(t, "<html><body>hi</body></html>", string(options.Body.([]byte)))
+	require.Equal(t, map[string]string{
+		"Set-Cookie":   "a=1\nb=2",
+		"Vary":         "Accept, Cookie",
+		"Content-Type": "text/html; charset=utf-8",
+	}, options.Headers)
+
+	w = newRouteResponseWriter()
+	require.Equal(t, http.StatusOK, *w.fulfillOptions().Status)
+}
+
+func TestServeHTTPRecovered(t *testing.T) {
+	err := serveHTTPRecovered(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), newRouteResponseWriter(), nil)
+	require.ErrorIs(t, err, http.ErrAbortHandler)
+	err = serveHTTPRecovered(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}), newRouteResponseWriter(), nil)
+	require.ErrorContains(t, err, "boom")
+}
@@ -0,0 +1,355 @@
+package playwright
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mxschmitt/playwright-go/har"
+)
+
+// HARMatcher reports whether a recorded entry answers request.
+type HARMatcher func(request Request, entry *har.Entry) bool
+
+// RouteHAROptions are the options for [RouteHAR].
+type RouteHAROptions struct {
+	// Only requests matching this glob pattern, regular expression or predicate are
+	// served from the archive. Defaults to all requests.
+	URL any
+	// What to do with requests no entry matches. Defaults to [HarNotFoundAbort].
+	NotFound *HarNotFound
+	// Decides which entries answer a request. Defaults to
+	// MatchHARAll(MatchHARMethod(), MatchHARURL(), MatchHARBody()).
+	Matcher HARMatcher
+	// When several entries match, answer the first request with the first entry,
+	// the second with the second and so on, reusing the last entry once they are
+	// exhausted. Useful for polling endpoints whose responses change. By default the
+	// first matching entry always answers.
+	Sequential *bool
+}
+
+// RouteHAR serves requests to router from a parsed archive, matching entries in
+// Go instead of the driver. Unlike [BrowserContext.RouteFromHAR] the matching is
+// configurable, so recordings with timestamps, nonces or random IDs in URLs or
+// bodies can still be replayed:
+//
+//	archive, _ := har.ReadFile("api.har")
+//	jsonBody, _ := playwright.MatchHARJSONBody("$.requestId")
+//	err := playwright.RouteHAR(page, archive, playwright.RouteHAROptions{
+//		Matcher: playwright.MatchHARAll(
+//			playwright.MatchHARMethod(),
+//			playwright.MatchHARURL("ts", "nonce"),
+//			jsonBody,
+//		),
+//	})
+//
+// The requests are served from a copy of archive with all attached request and
+// response bodies embedded up front, so archive itself is left unchanged and
+// later changes to it don't affect the route. Recorded redirects are followed:
+// navigation requests are redirected, other requests are fulfilled with the
+// final response.
+func RouteHAR(router Router, archive *har.HAR, options ...RouteHAROptions) error {
+	option := RouteHAROptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	archive = archive.Clone()
+	if err := archive.Embed(); err != nil {
+		return err
+	}
+	r := &harMatchRouter{
+		archive:    archive,
+		matcher:    option.Matcher,
+		notFound:   HarNotFoundAbort,
+		sequential: option.Sequential != nil && *option.Sequential,
+		calls:      make(map[*har.Entry]int),
+	}
+	if r.matcher == nil {
+		r.matcher = MatchHARAll(MatchHARMethod(), MatchHARURL(), MatchHARBody())
+	}
+	if option.NotFound != nil {
+		r.notFound = option.NotFound
+	}
+	var pattern any = "**/*"
+	if option.URL != nil {
+		pattern = option.URL
+	}
+	return router.Route(pattern, func(route Route) {
+		if err := r.handle(route); err != nil {
+			logger.Error("Error handling HAR route", "error", err)
+		}
+	})
+}
+
+type harMatchRouter struct {
+	archive    *har.HAR
+	matcher    HARMatcher
+	notFound   *HarNotFound
+	sequential bool
+
+	mu sync.Mutex
+	// calls counts the requests answered per group of candidates, keyed by the
+	// first candidate.
+	calls map[*har.Entry]int
+}
+
+func (r *harMatchRouter) handle(route Route) error {
+	request := route.Request()
+	entry := r.find(request)
+	if entry == nil {
+		if *r.notFound == *HarNotFoundAbort {
+			return route.Abort()
+		}
+		return route.Fallback()
+	}
+	if final := r.followRedirects(entry); final != entry {
+		// Other Route implementations can't redirect, they get the final response.
+		if impl, ok := route.(*routeImpl); ok && request.IsNavigationRequest() {
+			return impl.redirectedNavigationRequest(final.Request.URL)
+		}
+		entry = final
+	}
+	// The request was canceled or stalled while recording, so stall it here too.
+	if entry.Response.Status == -1 {
+		return nil
+	}
+	body, err := r.archive.Body(entry)
+	if err != nil {
+		return err
+	}
+	headers := make([]map[string]string, 0, len(entry.Response.Headers))
+	for _, header := range entry.Response.Headers {
+		headers = append(headers, map[string]string{"name": header.Name, "value": header.Value})
+	}
+	return route.Fulfill(RouteFulfillOptions{
+		Body:   body,
+		Status: Int(entry.Response.Status),
+		// route.Fulfill does not support multiple set-cookie headers, so we merge them into one.
+		Headers: mergeHeaders(headers),
+	})
+}
+
+func (r *harMatchRouter) find(request Request) *har.Entry {
+	candidates := []*har.Entry{}
+	for _, entry := range r.archive.Log.Entries {
+		if r.matcher(request, entry) {
+			if !r.sequential {
+				return entry
+			}
+			candidates = append(candidates, entry)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	call := r.calls[candidates[0]]
+	r.calls[candidates[0]]++
+	return candidates[min(call, len(candidates)-1)]
+}
+
+func (r *harMatchRouter) followRedirects(entry *har.Entry) *har.Entry {
+	visited := map[*har.Entry]bool{entry: true}
+	for entry.Response.Status >= 300 && entry.Response.Status < 400 {
+		location := entry.Response.RedirectURL
+		if location == "" {
+			location = entry.Response.Header("location")
+		}
+		if location == "" {
+			break
+		}
+		if base, err := url.Parse(entry.Request.URL); err == nil {
+			if resolved, err := base.Parse(location); err == nil {
+				location = resolved.String()
+			}
+		}
+		var next *har.Entry
+		for _, candidate := range r.archive.Log.Entries {
+			if candidate.Request.URL == location && candidate.Request.Method == "GET" && !visited[candidate] {
+				next = candidate
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		visited[next] = true
+		entry = next
+	}
+	return entry
+}
+
+// MatchHARAll matches entries matched by every matcher.
+func MatchHARAll(matchers ...HARMatcher) HARMatcher {
+	return func(request Request, entry *har.Entry) bool {
+		for _, matcher := range matchers {
+			if !matcher(request, entry) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchHARMethod matches entries recorded with the request's method.
+func MatchHARMethod() HARMatcher {
+	return func(request Request, entry *har.Entry) bool {
+		return strings.EqualFold(request.Method(), entry.Request.Method)
+	}
+}
+
+// MatchHARURL matches entries recorded for the request's URL. Query parameters
+// are compared regardless of their order, ignoring the named ones; "*" ignores
+// the whole query. The fragment is never compared.
+func MatchHARURL(ignoreParams ...string) HARMatcher {
+	ignoreAll := slices.Contains(ignoreParams, "*")
+	normalize := func(rawURL string) (string, url.Values, bool) {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", nil, false
+		}
+		query := u.Query()
+		for _, name := range ignoreParams {
+			query.Del(name)
+		}
+		u.RawQuery = ""
+		u.Fragment = ""
+		u.RawFragment = ""
+		if ignoreAll {
+			query = nil
+		}
+		return u.String(), query, true
+	}
+	return func(request Request, entry *har.Entry) bool {
+		requestURL, requestQuery, ok := normalize(request.URL())
+		if !ok {
+			return false
+		}
+		entryURL, entryQuery, ok := normalize(entry.Request.URL)
+		if !ok || requestURL != entryURL || len(requestQuery) != len(entryQuery) {
+			return false
+		}
+		for name, values := range requestQuery {
+			if !slices.Equal(values, entryQuery[name]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchHARBody matches entries recorded with exactly the request's body.
+// Requests without a body match entries without one.
+func MatchHARBody() HARMatcher {
+	return func(request Request, entry *har.Entry) bool {
+		body, err := request.PostDataBuffer()
+		if err != nil {
+			return false
+		}
+		return bytes.Equal(body, harPostData(entry))
+	}
+}
+
+// MatchHARJSONBody matches entries whose request body is JSON equal to the
+// request's body, ignoring formatting, key order and the values at ignorePaths.
+// The paths use the syntax of [RedactionOptions.JSONPaths]. Bodies that are not
+// JSON are compared exactly. It returns an error if a path is invalid.
+func MatchHARJSONBody(ignorePaths ...string) (HARMatcher, error) {
+	paths := make([]jsonPath, 0, len(ignorePaths))
+	for _, source := range ignorePaths {
+		path, err := parseJSONPath(source)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	normalize := func(body []byte) (any, bool) {
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return nil, false
+		}
+		for _, path := range paths {
+			path.apply(value, path.segments, "")
+		}
+		return value, true
+	}
+	return func(request Request, entry *har.Entry) bool {
+		body, err := request.PostDataBuffer()
+		if err != nil {
+			return false
+		}
+		recorded := harPostData(entry)
+		requestValue, ok := normalize(body)
+		if !ok {
+			return bytes.Equal(body, recorded)
+		}
+		entryValue, ok := normalize(recorded)
+		return ok && reflect.DeepEqual(requestValue, entryValue)
+	}, nil
+}
+
+// MatchHARGraphQLOperation matches GraphQL requests by operation name, read from
+// the operationName field or else the first named operation in the query. It
+// understands POST bodies and GET query parameters. Requests without an
+// operation name never match.
+func MatchHARGraphQLOperation() HARMatcher {
+	return func(request Request, entry *har.Entry) bool {
+		body, err := request.PostDataBuffer()
+		if err != nil {
+			return false
+		}
+		name := graphQLOperationName(request.URL(), body)
+		return name != "" && name == graphQLOperationName(entry.Request.URL, harPostData(entry))
+	}
+}
+
+// MatchHARHeaders matches entries whose request carried the same values for the
+// given headers as request.
+func MatchHARHeaders(names ...string) HARMatcher {
+	return func(request Request, entry *har.Entry) bool {
+		for _, name := range names {
+			value, err := request.HeaderValue(name)
+			if err != nil || value != entry.Request.Header(name) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func harPostData(entry *har.Entry) []byte {
+	if entry.Request.PostData == nil || entry.Request.PostData.Text == "" {
+		return nil
+	}
+	return []byte(entry.Request.PostData.Text)
+}
+
+var graphQLOperationRegexp = regexp.MustCompile(`\b(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+func graphQLOperationName(rawURL string, body []byte) string {
+	var payload struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return ""
+		}
+	} else if u, err := url.Parse(rawURL); err == nil {
+		payload.Query = u.Query().Get("query")
+		payload.OperationName = u.Query().Get("operationName")
+	}
+	if payload.OperationName != "" {
+		return payload.OperationName
+	}
+	if match := graphQLOperationRegexp.FindStringSubmatch(payload.Query); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package playwright
+
+import (
+	"testing"
+
+	"github.com/mxschmitt/playwright-go/har"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHARRequest struct {
+	Request
+	method  string
+	url     string
+	body    []byte
+	headers map[string]string
+}
+
+func (r *fakeHARRequest) Method() string                          { return r.method }
+func (r *fakeHARRequest) URL() string                             { return r.url }
+func (r *fakeHARRequest) PostDataBuffer() ([]byte, error)         { return r.body, nil }
+func (r *fakeHARRequest) HeaderValue(name string) (string, error) { return r.headers[name], nil }
+
+func harEntry(method, url, body string) *har.Entry {
+	entry := &har.Entry{Request: har.Request{Method: method, URL: url}}
+	if body != "" {
+		entry.Request.PostData = &har.PostData{MimeType: "application/json", Text: body}
+	}
+	return entry
+}
+
+func TestHARMatchers(t *testing.T) {
+	get := &fakeHARRequest{method: "GET", url: "https://example.com/items?b=2&a=1&ts=123#top"}
+	require.True(t, MatchHARURL("ts")(get, harEntry("GET", "https://example.com/items?a=1&b=2&ts=999", "")))
+	require.False(t, MatchHARURL()(get, harEntry("GET", "https://example.com/items?a=1&b=2&ts=999", "")))
+	require.False(t, MatchHARURL("ts")(get, harEntry("GET", "https://example.com/items?a=1", "")))
+	require.True(t, MatchHARURL("*")(get, harEntry("GET", "https://example.com/items", "")))
+	require.False(t, MatchHARURL("*")(get, harEntry("GET", "https://example.com/other", "")))
+	require.True(t, MatchHARMethod()(get, harEntry("get", "", "")))
+	require.False(t, MatchHARMethod()(get, harEntry("POST", "", "")))
+
+	post := &fakeHARRequest{method: "POST", url: "https://example.com/api", body: []byte(`{"id":1, "nonce":"abc","items":[1,2]}`)}
+	require.False(t, MatchHARBody()(post, harEntry("POST", "", `{"id":1,"nonce":"abc","items":[1,2]}`)))
+	jsonBody, err := MatchHARJSONBody()
+	require.NoError(t, err)
+	require.True(t, jsonBody(post, harEntry("POST", "", `{"items":[1,2],"nonce":"abc","id":1}`)))
+	jsonBody, err = MatchHARJSONBody("nonce")
+	require.NoError(t, err)
+	require.True(t, jsonBody(post, harEntry("POST", "", `{"id":1,"nonce":"xyz","items":[1,2]}`)))
+	require.False(t, jsonBody(post, harEntry("POST", "", `{"id":2,"nonce":"xyz","items":[1,2]}`)))
+	_, err = MatchHARJSONBody("$.items[")
+	require.Error(t, err)
+	require.True(t, MatchHARBody()(get, harEntry("GET", "", "")))
+
+	graphQL := &fakeHARRequest{method: "POST", body: []byte(`{"query":"query GetUser($id: ID!) { user(id: $id) { name } }","variables":{"id":1}}`)}
+	require.True(t, MatchHARGraphQLOperation()(graphQL, harEntry("POST", "", `{"operationName":"GetUser","query":"..."}`)))
+	require.False(t, MatchHARGraphQLOperation()(graphQL, harEntry("POST", "", `{"operationName":"GetPosts"}`)))
+	graphQLGet := &fakeHARRequest{method: "GET", url: "https://example.com/graphql?query=query%20GetUser%7Buser%7D"}
+	require.True(t, MatchHARGraphQLOperation()(graphQLGet, harEntry("POST", "", `{"operationName":"GetUser"}`)))
+	require.False(t, MatchHARGraphQLOperation()(get, harEntry("GET", "https://example.com/items", "")))
+
+	withHeader := &fakeHARRequest{headers: map[string]string{"x-tenant": "a"}}
+	entry := harEntry("GET", "", "")
+	entry.Request.Headers = []har.NameValue{{Name: "X-Tenant", Value: "a"}}
+	require.True(t, MatchHARHeaders("x-tenant")(withHeader, entry))
+	entry.Request.Headers[0].Value = "b"
+	require.False(t, MatchHARHeaders("x-tenant")(withHeader, entry))
+}
+
+func TestHARMatchRouterFind(t *testing.T) {
+	first := harEntry("GET", "https://example.com/poll?ts=1", "")
+	second := harEntry("GET", "https://example.com/poll?ts=2", "")
+	archive := har.New(har.Creator{})
+	archive.Log.Entries = []*har.Entry{first, second}
+	request := &fakeHARRequest{method: "GET", url: "https://example.com/poll?ts=3"}
+
+	r := &harMatchRouter{archive: archive, matcher: MatchHARURL("ts"), calls: map[*har.Entry]int{}}
+	require.Same(t, first, r.find(request))
+	require.Same(t, first, r.find(request))
+
+	r.sequential = true
+	require.Same(t, first, r.find(request))
+	require.Same(t, second, r.find(request))
+	require.Same(t, second, r.find(request))
+
+	r.matcher = MatchHARURL()
+	require.Nil(t, r.find(request))
+}
+
+func TestHARMatchRouterFollowRedirects(t *testing.T) {
+	start := harEntry("GET", "https://example.com/a", "")
+	start.Response = har.Response{Status: 302, Headers: []har.NameValue{{Name: "Location", Value: "/b"}}}
+	middle := harEntry("GET", "https://example.com/b", "")
+	middle.Response = har.Response{Status: 301, RedirectURL: "https://example.com/c"}
+	final := harEntry("GET", "https://example.com/c", "")
+	final.Response = har.Response{Status: 200}
+	archive := har.New(har.Creator{})
+	archive.Log.Entries = []*har.Entry{start, middle, final}
+
+	r := &harMatchRouter{archive: archive}
+	require.Same(t, final, r.followRedirects(start))
+	require.Same(t, final, r.followRedirects(final))
+}
+
+type fakeHARRoute struct {
+	Route
+	request   Request
+	fulfilled *RouteFulfillOptions
+}
+
+func (r *fakeHARRoute) Request() Request { return r.request }
+func (r *fakeHARRoute) Fulfill(options ...RouteFulfillOptions) error {
+	r.fulfilled = &options[0]
+	return nil
+}
+
+type fakeHARNavigationRequest struct {
+	fakeHARRequest
+}
+
+func (r *fakeHARNavigationRequest) IsNavigationRequest() bool { return true }
+
+func TestHARMatchRouterRedirectWithOtherRoute(t *testing.T) {
+	start := harEntry("GET", "https://example.com/a", "")
+	start.Response = har.Response{Status: 302, Headers: []har.NameValue{{Name: "Location", Value: "/b"}}}
+	final := harEntry("GET", "https://example.com/b", "")
+	final.Response = har.Response{Status: 200, Content: har.Content{MimeType: "text/html", Text: "b"}}
+	archive := har.New(har.Creator{})
+	archive.Log.Entries = []*har.Entry{start, final}
+
+	route := &fakeHARRoute{request: &fakeHARNavigationRequest{fakeHARRequest{method: "GET", url: "https://example.com/a"}}}
+	r := &harMatchRouter{archive: archive, matcher: MatchHARURL(), notFound: HarNotFoundFallback, calls: map[*har.Entry]int{}}
+	require.NoError(t, r.handle(route))
+	require.NotNil(t, route.fulfilled)
+	require.Equal(t, 200, *route.fulfilled.Status)
+	require.Equal(t, []byte("b"), route.fulfilled.Body)
+}
+
+func TestRouteHARLeavesArchiveUnchanged(t *testing.T) {
+	archive := har.New(har.Creator{Name: "test"})
+	entry := harEntry("POST", "https://example.com/api", `{"id":1}`)
+	entry.Response.Content = har.Content{MimeType: "application/json", File: "body.json"}
+	archive.Log.Entries = []*har.Entry{entry}
+	archive.SetBody(entry, []byte(`{"ok":true}`))
+	file := entry.Response.Content.File
+
+	router := &fakeGraphQLRouter{}
+	require.NoError(t, RouteHAR(router, archive))
+	require.Len(t, router.routes, 1)
+	require.Equal(t, file, entry.Response.Content.File)
+	require.Empty(t, entry.Response.Content.Text)
+}
@@ -0,0 +1,500 @@
+package playwright
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SSEEvent is a server-sent event, see
+// https://html.spec.whatwg.org/multipage/server-sent-events.html.
+type SSEEvent struct {
+	// Event is the event type, dispatched as a "message" event when empty.
+	Event string
+	// Data is the payload; line breaks are sent as multiple data lines.
+	Data string
+	// ID sets the last event ID, which the browser sends back as Last-Event-ID
+	// when it reconnects. Pass an empty string to reset it.
+	ID *string
+	// Retry sets the time the browser waits before reconnecting. It is sent in
+	// whole milliseconds; zero leaves it unchanged.
+	Retry time.Duration
+}
+
+func (e SSEEvent) encode() []byte {
+	var b bytes.Buffer
+	if e.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", sseLine(e.Event))
+	}
+	if e.ID != nil {
+		fmt.Fprintf(&b, "id: %s\n", sseLine(*e.ID))
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry.Milliseconds())
+	}
+	data := strings.ReplaceAll(strings.ReplaceAll(e.Data, "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.Bytes()
+}
+
+// sseLine drops line breaks from single-line fields, which would otherwise
+// start a new field.
+func sseLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// RouteSSEOptions are the options for [RouteSSE].
+type RouteSSEOptions struct {
+	// Extra response headers.
+	Headers map[string]string
+}
+
+// SSERoute serves server-sent event streams to a page, see [RouteSSE].
+type SSERoute struct {
+	router  Router
+	url     any
+	handler func(Route)
+	headers map[string]string
+
+	mu sync.Mutex
+	// servers and hosts hold the loopback server per URL scheme.
+	servers map[string]*http.Server
+	hosts   map[string]string
+	// pending holds the requests continued to a server until they arrive there.
+	pending     map[string]*pendingSSERequest
+	connections []*SSEConnection
+	// waited counts the connections returned by WaitForConnection, connected
+	// is closed and replaced whenever a connection opens.
+	waited    int
+	connected chan struct{}
+	rejecting bool
+	closed    bool
+	scheduled []SSEEvent
+	exposed   map[Page]string
+	nextID    atomic.Int64
+}
+
+// RouteSSE answers requests to router matching url with event streams driven
+// from Go, e.g. for an EventSource:
+//
+//	sse, _ := playwright.RouteSSE(page, "**/events")
+//	defer sse.Close()
+//	page.Goto(server.URL)
+//	conn, _ := sse.WaitForConnection(5 * time.Second)
+//	conn.Send(playwright.SSEEvent{Event: "update", Data: `{"count":1}`})
+//
+// The response is held open until [SSEConnection.Close] or [SSERoute.Close].
+// Since [Route.Fulfill] only sends complete bodies, the matched requests are
+// continued to an in-process server on a loopback port, one per URL scheme.
+// For https URLs the server uses a self-signed certificate, so the context must
+// be created with IgnoreHttpsErrors.
+//
+// When the stream is closed, the browser reconnects after the retry delay with
+// a new connection carrying the Last-Event-ID. Use [SSERoute.RejectReconnects]
+// to answer reconnects with 204 No Content, which stops the EventSource.
+func RouteSSE(router Router, url any, options ...RouteSSEOptions) (*SSERoute, error) {
+	s := &SSERoute{
+		router:    router,
+		url:       url,
+		pending:   make(map[string]*pendingSSERequest),
+		exposed:   make(map[Page]string),
+		connected: make(chan struct{}),
+	}
+	if len(options) == 1 {
+		s.headers = options[0].Headers
+	}
+	s.handler = func(route Route) {
+		if err := s.handle(route); err != nil {
+			logger.Error("Error handling SSE route", "error", err)
+		}
+	}
+	if err := router.Route(url, s.handler); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+const sseConnectionHeader = "x-playwright-sse-connection"
+
+// ssePendingTimeout is how long a continued request is waited for by the
+// server, e.g. it never arrives if the page navigates away meanwhile.
+const ssePendingTimeout = 30 * time.Second
+
+type pendingSSERequest struct {
+	request Request
+	expire  *time.Timer
+}
+
+func (s *SSERoute) handle(route Route) error {
+	request := route.Request()
+	target, err := url.Parse(request.URL())
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	host, err := s.startServer(target.Scheme)
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	target.Host = host
+	headers, err := request.AllHeaders()
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	id := strconv.FormatInt(s.nextID.Add(1), 10)
+	headers[sseConnectionHeader] = id
+	s.mu.Lock()
+	s.pending[id] = &pendingSSERequest{
+		request: request,
+		expire:  time.AfterFunc(ssePendingTimeout, func() { s.takePending(id) }),
+	}
+	s.mu.Unlock()
+	err = route.Continue(RouteContinueOptions{
+		URL:     String(target.String()),
+		Headers: headers,
+	})
+	if err != nil {
+		s.takePending(id)
+	}
+	return err
+}
+
+// takePending removes the pending request id and returns it, nil if it is not
+// pending anymore.
+func (s *SSERoute) takePending(id string) Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, ok := s.pending[id]
+	if !ok {
+		return nil
+	}
+	delete(s.pending, id)
+	pending.expire.Stop()
+	return pending.request
+}
+
+// startServer starts the server for scheme on a loopback port and returns its
+// host. https requests are served with a self-signed certificate.
+func (s *SSERoute) startServer(scheme string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return "", errors.New("SSE route is closed")
+	}
+	if host, ok := s.hosts[scheme]; ok {
+		return host, nil
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	if scheme == "https" {
+		certificate, err := selfSignedCertificate()
+		if err != nil {
+			listener.Close() //nolint:errcheck
+			return "", err
+		}
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{certificate}})
+	}
+	if s.servers == nil {
+		s.servers = make(map[string]*http.Server)
+		s.hosts = make(map[string]string)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+	host := listener.Addr().String()
+	s.servers[scheme] = server
+	s.hosts[scheme] = host
+	go server.Serve(listener) //nolint:errcheck
+	return host, nil
+}
+
+// selfSignedCertificate returns a certificate for 127.0.0.1 and localhost.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{Organization: []string{"Playwright SSE"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+func (s *SSERoute) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	request := s.takePending(r.Header.Get(sseConnectionHeader))
+	s.mu.Lock()
+	rejecting := s.rejecting || s.closed
+	s.mu.Unlock()
+	if rejecting {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	for name, value := range s.headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	conn := &SSEConnection{
+		Request:     request,
+		LastEventID: r.Header.Get("Last-Event-ID"),
+		events:      make(chan []byte, 16),
+		done:        make(chan struct{}),
+		closing:     make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.connections = append(s.connections, conn)
+	close(s.connected)
+	s.connected = make(chan struct{})
+	s.mu.Unlock()
+	defer close(conn.done)
+	for {
+		select {
+		case data := <-conn.events:
+			if _, err := w.Write(data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-conn.closing:
+			// Flush what was sent before closing.
+			for {
+				select {
+				case data := <-conn.events:
+					if _, err := w.Write(data); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// Connections returns the connections opened so far, including closed ones.
+func (s *SSERoute) Connections() []*SSEConnection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*SSEConnection{}, s.connections...)
+}
+
+// WaitForConnection returns the first connection it has not returned yet,
+// waiting for the page to open one. Reconnects are new connections.
+func (s *SSERoute) WaitForConnection(timeout time.Duration) (*SSEConnection, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		if s.waited < len(s.connections) {
+			conn := s.connections[s.waited]
+			s.waited++
+			s.mu.Unlock()
+			return conn, nil
+		}
+		connected := s.connected
+		s.mu.Unlock()
+		select {
+		case <-connected:
+		case <-timer.C:
+			return nil, fmt.Errorf("%w: waiting for SSE connection", ErrTimeout)
+		}
+	}
+}
+
+// Send sends event to every open connection.
+func (s *SSERoute) Send(event SSEEvent) error {
+	var errs []error
+	for _, conn := range s.Connections() {
+		if conn.IsClosed() {
+			continue
+		}
+		if err := conn.Send(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Schedule sends event to every open connection once delay has elapsed on the
+// timers of page. When the page clock is faked with [Clock.Install], the event
+// is sent as [Clock.RunFor] or [Clock.FastForward] move past it, so streams can
+// be timed together with the rest of the page.
+func (s *SSERoute) Schedule(page Page, delay time.Duration, event SSEEvent) error {
+	binding, err := s.exposeTo(page)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	index := len(s.scheduled)
+	s.scheduled = append(s.scheduled, event)
+	s.mu.Unlock()
+	_, err = page.Evaluate(`([binding, index, delay]) => { setTimeout(() => window[binding](index), delay); }`,
+		[]any{binding, index, delay.Milliseconds()})
+	return err
+}
+
+func (s *SSERoute) exposeTo(page Page) (string, error) {
+	s.mu.Lock()
+	binding, ok := s.exposed[page]
+	if ok {
+		s.mu.Unlock()
+		return binding, nil
+	}
+	binding = fmt.Sprintf("__playwrightSSE%p", s)
+	s.exposed[page] = binding
+	s.mu.Unlock()
+	err := page.ExposeFunction(binding, func(args ...any) any {
+		var index int
+		switch v := args[0].(type) {
+		case int:
+			index = v
+		case float64:
+			index = int(v)
+		default:
+			return nil
+		}
+		s.mu.Lock()
+		event := s.scheduled[index]
+		s.mu.Unlock()
+		if err := s.Send(event); err != nil {
+			logger.Error("Error sending scheduled SSE event", "error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.mu.Lock()
+		delete(s.exposed, page)
+		s.mu.Unlock()
+		return "", err
+	}
+	return binding, nil
+}
+
+// RejectReconnects answers new connections with 204 No Content, which makes
+// EventSource stop reconnecting. Open connections are not affected.
+func (s *SSERoute) RejectReconnects() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejecting = true
+}
+
+// Close removes the route, closes all connections and stops the server.
+func (s *SSERoute) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	servers := s.servers
+	connections := s.connections
+	for id, pending := range s.pending {
+		pending.expire.Stop()
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+	err := s.router.Unroute(s.url, s.handler)
+	for _, conn := range connections {
+		conn.Close()
+	}
+	for _, server := range servers {
+		err = errors.Join(err, server.Close())
+	}
+	return err
+}
+
+// SSEConnection is an event stream opened by the page, see [RouteSSE].
+type SSEConnection struct {
+	// Request is the intercepted request.
+	Request Request
+	// LastEventID is the Last-Event-ID the browser sent when reconnecting.
+	LastEventID string
+
+	events    chan []byte
+	done      chan struct{}
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+// Send writes event to the stream.
+func (c *SSEConnection) Send(event SSEEvent) error {
+	return c.write(event.encode())
+}
+
+// Comment writes a comment line, which browsers ignore, e.g. as a keep-alive.
+func (c *SSEConnection) Comment(text string) error {
+	return c.write([]byte(": " + sseLine(text) + "\n\n"))
+}
+
+func (c *SSEConnection) write(data []byte) error {
+	if c.IsClosed() {
+		return errors.New("SSE connection is closed")
+	}
+	select {
+	case c.events <- data:
+		return nil
+	case <-c.done:
+		return errors.New("SSE connection is closed")
+	}
+}
+
+// Close ends the stream. The browser reconnects unless reconnects are rejected.
+func (c *SSEConnection) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closing)
+	})
+	<-c.done
+}
+
+// Done is closed once the stream has ended, because it was closed or the page
+// disconnected.
+func (c *SSEConnection) Done() <-chan struct{} {
+	return c.done
+}
+
+// IsClosed reports whether the stream has ended.
+func (c *SSEConnection) IsClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
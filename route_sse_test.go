@@ -0,0 +1,145 @@
+package playwright
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEEventEncode(t *testing.T) {
+	require.Equal(t, "data: hello\n\n", string(SSEEvent{Data: "hello"}.encode()))
+	require.Equal(t,
+		"event: update\nid: 7\nretry: 1500\ndata: line 1\ndata: line 2\ndata: \n\n",
+		string(SSEEvent{Event: "update", ID: String("7"), Retry: 1500 * time.Millisecond, Data: "line 1\r\nline 2\n"}.encode()))
+	require.Equal(t, "event: ab\nid: \ndata: \n\n", string(SSEEvent{Event: "a\nb", ID: String("")}.encode()))
+}
+
+func newTestSSERoute(t *testing.T) *SSERoute {
+	t.Helper()
+	s := &SSERoute{
+		router:    &fakeGraphQLRouter{},
+		pending:   make(map[string]*pendingSSERequest),
+		exposed:   make(map[Page]string),
+		connected: make(chan struct{}),
+	}
+	_, err := s.startServer("http")
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() }) //nolint:errcheck
+	return s
+}
+
+func TestSSERouteTLS(t *testing.T) {
+	s := newTestSSERoute(t)
+	host, err := s.startServer("https")
+	require.NoError(t, err)
+	require.NotEqual(t, s.hosts["http"], host)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	s.RejectReconnects()
+	resp, err := client.Get("https://" + host + "/events")
+	require.NoError(t, err)
+	resp.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.NotNil(t, resp.TLS)
+}
+
+func TestSSERouteStream(t *testing.T) {
+	s := newTestSSERoute(t)
+
+	_, err := s.WaitForConnection(10 * time.Millisecond)
+	require.ErrorIs(t, err, ErrTimeout)
+
+	req, err := http.NewRequest("GET", "http://"+s.hosts["http"]+"/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "41")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, 200, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	conn, err := s.WaitForConnection(time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "41", conn.LastEventID)
+	require.False(t, conn.IsClosed())
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var event string
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return event
+			}
+			event += line
+		}
+	}
+	require.NoError(t, s.Send(SSEEvent{Event: "tick", Data: "1"}))
+	require.Equal(t, "event: tick\ndata: 1\n", readEvent())
+	require.NoError(t, conn.Comment("keep-alive"))
+	require.Equal(t, ": keep-alive\n", readEvent())
+
+	require.NoError(t, conn.Send(SSEEvent{ID: String("42"), Data: "last"}))
+	conn.Close()
+	require.True(t, conn.IsClosed())
+	require.Equal(t, "id: 42\ndata: last\n", readEvent())
+	_, err = reader.ReadString('\n')
+	require.Error(t, err)
+	require.Error(t, conn.Send(SSEEvent{Data: "too late"}))
+
+	s.RejectReconnects()
+	resp, err = http.Get("http://" + s.hosts["http"] + "/events")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Len(t, s.Connections(), 1)
+}
+
+type fakeSSERequest struct {
+	Request
+	url string
+}
+
+func (r *fakeSSERequest) URL() string                            { return r.url }
+func (r *fakeSSERequest) AllHeaders() (map[string]string, error) { return map[string]string{}, nil }
+
+type fakeSSERoute struct {
+	Route
+	request   Request
+	continued *RouteContinueOptions
+	err       error
+}
+
+func (r *fakeSSERoute) Request() Request { return r.request }
+func (r *fakeSSERoute) Continue(options ...RouteContinueOptions) error {
+	r.continued = &options[0]
+	return r.err
+}
+
+func TestSSERoutePending(t *testing.T) {
+	s := newTestSSERoute(t)
+	request := &fakeSSERequest{url: "https://example.com/events"}
+
+	failing := &fakeSSERoute{request: request, err: ErrTargetClosed}
+	require.ErrorIs(t, s.handle(failing), ErrTargetClosed)
+	require.Empty(t, s.pending)
+
+	route := &fakeSSERoute{request: request}
+	require.NoError(t, s.handle(route))
+	require.Len(t, s.pending, 1)
+	require.Contains(t, *route.continued.URL, "https://"+s.hosts["https"]+"/events")
+
+	s.RejectReconnects()
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	req, err := http.NewRequest("GET", *route.continued.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(sseConnectionHeader, route.continued.Headers[sseConnectionHeader])
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Empty(t, s.pending)
+}
@@ -0,0 +1,188 @@
+package playwright
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// TransformContext is passed to the transform function of [RouteTransform]. It
+// holds the response fetched from the server, which the function rewrites in
+// place.
+type TransformContext struct {
+	// Request is the intercepted request.
+	Request Request
+	// Response is the original response as fetched from the server.
+	Response APIResponse
+	// Status is the status code sent to the page.
+	Status int
+	// Headers are the response headers sent to the page, keyed by lower-case name.
+	// Content-Length and encoding headers are fixed up after the transform.
+	Headers map[string]string
+
+	body []byte
+}
+
+// Body returns the decoded response body.
+func (c *TransformContext) Body() []byte {
+	return c.body
+}
+
+// SetBody replaces the response body.
+func (c *TransformContext) SetBody(body []byte) {
+	c.body = body
+}
+
+// Text returns the response body as a string.
+func (c *TransformContext) Text() string {
+	return string(c.body)
+}
+
+// SetText replaces the response body with text.
+func (c *TransformContext) SetText(text string) {
+	c.body = []byte(text)
+}
+
+// JSON unmarshals the response body into v.
+func (c *TransformContext) JSON(v any) error {
+	return json.Unmarshal(c.body, v)
+}
+
+// SetJSON replaces the response body with v marshaled as JSON and sets the
+// Content-Type to application/json unless it is already a JSON type.
+func (c *TransformContext) SetJSON(v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.body = body
+	if !strings.Contains(c.Headers["content-type"], "json") {
+		c.Headers["content-type"] = "application/json"
+	}
+	return nil
+}
+
+// InjectIntoHead inserts html right before the closing </head> tag, or at the
+// start of the document if there is none.
+func (c *TransformContext) InjectIntoHead(html string) {
+	if i := lastIndexFold(c.body, "</head>"); i >= 0 {
+		c.body = insertBytes(c.body, i, html)
+		return
+	}
+	c.body = insertBytes(c.body, 0, html)
+}
+
+// InjectIntoBody inserts html right before the closing </body> tag, or at the
+// end of the document if there is none.
+func (c *TransformContext) InjectIntoBody(html string) {
+	if i := lastIndexFold(c.body, "</body>"); i >= 0 {
+		c.body = insertBytes(c.body, i, html)
+		return
+	}
+	c.body = insertBytes(c.body, len(c.body), html)
+}
+
+// RouteTransform fetches requests to router matching url from the server, lets
+// transform rewrite the status, headers and body, and fulfills the request with
+// the result. url is a glob pattern, regular expression or predicate as accepted
+// by [Page.Route].
+//
+// The body passed to transform is decoded: the fetch API decompresses gzip,
+// deflate and br responses, and Content-Encoding and Content-Length are removed
+// so the rewritten body is sent as is. If fetching fails or transform returns
+// an error the request is aborted and the error logged.
+func RouteTransform(router Router, url any, transform func(*TransformContext) error) error {
+	return router.Route(url, func(route Route) {
+		if err := transformRoute(route, transform); err != nil {
+			logger.Error("Error transforming route", "error", err)
+		}
+	})
+}
+
+func transformRoute(route Route, transform func(*TransformContext) error) error {
+	response, err := route.Fetch()
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	body, err := response.Body()
+	if err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	headers := response.Headers()
+	if body, err = decodeContentEncoding(body, headers["content-encoding"]); err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	ctx := &TransformContext{
+		Request:  route.Request(),
+		Response: response,
+		Status:   response.Status(),
+		Headers:  headers,
+		body:     body,
+	}
+	if err := transform(ctx); err != nil {
+		return errors.Join(err, route.Abort())
+	}
+	for name := range ctx.Headers {
+		switch strings.ToLower(name) {
+		case "content-length", "content-encoding", "transfer-encoding":
+			delete(ctx.Headers, name)
+		}
+	}
+	return route.Fulfill(RouteFulfillOptions{
+		Status:  Int(ctx.Status),
+		Headers: ctx.Headers,
+		Body:    ctx.body,
+	})
+}
+
+// decodeContentEncoding decompresses body if it is still encoded. The fetch API
+// decodes gzip, deflate and br already, so this is only a fallback for bodies
+// that still carry the gzip magic number or a zlib header.
+func decodeContentEncoding(body []byte, encoding string) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		if len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+			return body, nil
+		}
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		if len(body) < 2 || (uint16(body[0])<<8|uint16(body[1]))%31 != 0 || body[0]&0x0f != 8 {
+			return body, nil
+		}
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+	default:
+		return body, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close() //nolint:errcheck
+	return io.ReadAll(reader)
+}
+
+// lastIndexFold returns the index of the last case-insensitive occurrence of the
+// ASCII tag in body, or -1. Only ASCII letters are folded so the indices of the
+// lowered copy match body.
+func lastIndexFold(body []byte, tag string) int {
+	lower := make([]byte, len(body))
+	for i, c := range body {
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		lower[i] = c
+	}
+	return bytes.LastIndex(lower, []byte(strings.ToLower(tag)))
+}
+
+func insertBytes(body []byte, at int, s string) []byte {
+	out := make([]byte, 0, len(body)+len(s))
+	out = append(out, body[:at]...)
+	out = append(out, s...)
+	return append(out, body[at:]...)
+}
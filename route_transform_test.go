@@ -0,0 +1,105 @@
+package playwright
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeContentEncoding(t *testing.T) {
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write([]byte("hello gzip"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	body, err := decodeContentEncoding(gzipped.Bytes(), "gzip")
+	require.NoError(t, err)
+	require.Equal(t, "hello gzip", string(body))
+
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	_, err = zw.Write([]byte("hello deflate"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	body, err = decodeContentEncoding(deflated.Bytes(), "deflate")
+	require.NoError(t, err)
+	require.Equal(t, "hello deflate", string(body))
+
+	// Already decoded by the fetch API.
+	for _, encoding := range []string{"gzip", "deflate", "br", "identity", ""} {
+		body, err = decodeContentEncoding([]byte("plain"), encoding)
+		require.NoError(t, err)
+		require.Equal(t, "plain", string(body))
+	}
+}
+
+func TestTransformContext(t *testing.T) {
+	ctx := &TransformContext{Headers: map[string]string{}, body: []byte("<html><HEAD><title>x</title></HEAD><body><p>hi</p></BODY></html>")}
+	ctx.InjectIntoHead("<script>1</script>")
+	ctx.InjectIntoBody("<footer/>")
+	require.Equal(t, "<html><HEAD><title>x</title><script>1</script></HEAD><body><p>hi</p><footer/></BODY></html>", ctx.Text())
+
+	ctx.SetText("<p>İ</p></body>")
+	ctx.InjectIntoBody("<b>")
+	require.Equal(t, "<p>İ</p><b></body>", ctx.Text())
+
+	ctx.SetText("fragment")
+	ctx.InjectIntoHead("<a>")
+	ctx.InjectIntoBody("<b>")
+	require.Equal(t, "<a>fragment<b>", ctx.Text())
+
+	ctx.SetBody([]byte(`{"items":[1,2]}`))
+	var payload map[string][]int
+	require.NoError(t, ctx.JSON(&payload))
+	payload["items"] = append(payload["items"], 3)
+	require.NoError(t, ctx.SetJSON(payload))
+	require.JSONEq(t, `{"items":[1,2,3]}`, ctx.Text())
+	require.Equal(t, "application/json", ctx.Headers["content-type"])
+
+	ctx.Headers["content-type"] = "application/vnd.api+json"
+	require.NoError(t, ctx.SetJSON(payload))
+	require.Equal(t, "application/vnd.api+json", ctx.Headers["content-type"])
+}
+
+type fakeTransformResponse struct {
+	APIResponse
+	body    []byte
+	headers map[string]string
+}
+
+func (r *fakeTransformResponse) Body() ([]byte, error)      { return r.body, nil }
+func (r *fakeTransformResponse) Headers() map[string]string { return r.headers }
+func (r *fakeTransformResponse) Status() int                { return 200 }
+
+type fakeTransformRoute struct {
+	Route
+	response  *fakeTransformResponse
+	fulfilled *RouteFulfillOptions
+}
+
+func (r *fakeTransformRoute) Request() Request { return nil }
+func (r *fakeTransformRoute) Fetch(options ...RouteFetchOptions) (APIResponse, error) {
+	return r.response, nil
+}
+
+func (r *fakeTransformRoute) Fulfill(options ...RouteFulfillOptions) error {
+	r.fulfilled = &options[0]
+	return nil
+}
+
+func TestTransformRouteDecodedEncodings(t *testing.T) {
+	// The fetch API has decoded the br body already.
+	route := &fakeTransformRoute{response: &fakeTransformResponse{
+		body:    []byte("<html><body></body></html>"),
+		headers: map[string]string{"content-encoding": "br", "content-length": "12", "content-type": "text/html"},
+	}}
+	require.NoError(t, transformRoute(route, func(ctx *TransformContext) error {
+		ctx.InjectIntoBody("<p>hi</p>")
+		return nil
+	}))
+	require.Equal(t, []byte("<html><body><p>hi</p></body></html>"), route.fulfilled.Body)
+	require.Equal(t, map[string]string{"content-type": "text/html"}, route.fulfilled.Headers)
+}
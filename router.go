@@ -0,0 +1,13 @@
+package playwright
+
+// Router is implemented by [Page] and [BrowserContext]. Helpers that install
+// route handlers accept a Router so they work on either.
+type Router interface {
+	Route(url any, handler func(Route), times ...int) error
+	Unroute(url any, handler ...func(Route)) error
+}
+
+var (
+	_ Router = (*pageImpl)(nil)
+	_ Router = (*browserContextImpl)(nil)
+)
@@ -0,0 +1,276 @@
+package playwright
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mxschmitt/playwright-go/animation"
+)
+
+// ScreencastFrame is a decoded frame of a screencast.
+type ScreencastFrame struct {
+	Image image.Image
+	// Timestamp is when the browser presented the frame.
+	Timestamp      time.Time
+	ViewportWidth  int
+	ViewportHeight int
+}
+
+// DecodeScreencastFrame decodes a frame passed to
+// [ScreencastStartOptions.OnFrame].
+func DecodeScreencastFrame(frame OnFrame) (ScreencastFrame, error) {
+	img, err := jpeg.Decode(bytes.NewReader(frame.Data))
+	if err != nil {
+		return ScreencastFrame{}, err
+	}
+	return ScreencastFrame{
+		Image:          img,
+		Timestamp:      screencastTimestamp(frame.Timestamp),
+		ViewportWidth:  frame.ViewportWidth,
+		ViewportHeight: frame.ViewportHeight,
+	}, nil
+}
+
+func screencastTimestamp(milliseconds float64) time.Time {
+	whole, fraction := math.Modf(milliseconds)
+	return time.UnixMilli(int64(whole)).Add(time.Duration(fraction * float64(time.Millisecond)))
+}
+
+// FrameOverflow decides what happens to frames when the channel of
+// [ScreencastFrames] is full.
+type FrameOverflow int
+
+const (
+	// FrameOverflowBlock waits until there is room. The browser doesn't send
+	// new frames until a frame was delivered, so a slow consumer lowers the
+	// frame rate instead of losing frames.
+	FrameOverflowBlock FrameOverflow = iota
+	// FrameOverflowDropNewest drops the frame that doesn't fit.
+	FrameOverflowDropNewest
+	// FrameOverflowDropOldest drops the oldest frame in the channel to make
+	// room, so the consumer always sees the latest frames.
+	FrameOverflowDropOldest
+)
+
+// ScreencastFramesOptions are the options for [StartScreencastFrames].
+type ScreencastFramesOptions struct {
+	// Capacity of the channel. Defaults to 16.
+	Buffer int
+	// What to do when the channel is full. Defaults to [FrameOverflowBlock].
+	Overflow FrameOverflow
+	// JPEG quality of the frames, between 0 and 100.
+	Quality *int
+	// Bounds of the frame size, see [ScreencastStartOptions.Size].
+	Size *Size
+}
+
+// ScreencastFrames delivers the decoded frames of a screencast on a channel,
+// see [StartScreencastFrames].
+type ScreencastFrames struct {
+	screencast Screencast
+	overflow   FrameOverflow
+	frames     chan ScreencastFrame
+	done       chan struct{}
+	dropped    atomic.Int64
+	stopOnce   sync.Once
+	stopErr    error
+
+	// mu is held for reading while frames are sent, so the channel is only
+	// closed once no send is in progress.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// StartScreencastFrames starts the screencast of page and delivers its frames
+// as images on a bounded channel:
+//
+//	frames, _ := playwright.StartScreencastFrames(page, playwright.ScreencastFramesOptions{
+//		Overflow: playwright.FrameOverflowDropOldest,
+//	})
+//	for frame := range frames.Frames() {
+//		// frame.Image
+//	}
+//
+// Frames that can't be decoded are skipped. The channel is closed by Stop.
+func StartScreencastFrames(page Page, options ...ScreencastFramesOptions) (*ScreencastFrames, error) {
+	opts := ScreencastFramesOptions{}
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 16
+	}
+	screencast, err := page.Screencast()
+	if err != nil {
+		return nil, err
+	}
+	s := &ScreencastFrames{
+		screencast: screencast,
+		overflow:   opts.Overflow,
+		frames:     make(chan ScreencastFrame, opts.Buffer),
+		done:       make(chan struct{}),
+	}
+	err = screencast.Start(ScreencastStartOptions{
+		OnFrame: s.onFrame,
+		Quality: opts.Quality,
+		Size:    opts.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *ScreencastFrames) onFrame(data OnFrame) {
+	frame, err := DecodeScreencastFrame(data)
+	if err != nil {
+		logger.Error("Error decoding screencast frame", "error", err)
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	switch s.overflow {
+	case FrameOverflowDropNewest:
+		select {
+		case s.frames <- frame:
+		default:
+			s.dropped.Add(1)
+		}
+	case FrameOverflowDropOldest:
+		for {
+			select {
+			case s.frames <- frame:
+				return
+			default:
+			}
+			select {
+			case <-s.frames:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.frames <- frame:
+		case <-s.done:
+		}
+	}
+}
+
+// Frames returns the channel the frames are delivered on.
+func (s *ScreencastFrames) Frames() <-chan ScreencastFrame {
+	return s.frames
+}
+
+// Dropped returns the number of frames dropped because the channel was full.
+func (s *ScreencastFrames) Dropped() int {
+	return int(s.dropped.Load())
+}
+
+// Stop stops the screencast and closes the channel. Frames still in the
+// channel can be received afterwards.
+func (s *ScreencastFrames) Stop() error {
+	s.stopOnce.Do(func() {
+		s.stopErr = s.screencast.Stop()
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.frames)
+		s.mu.Unlock()
+	})
+	return s.stopErr
+}
+
+// ScreencastAnimationOptions are the options for [RecordScreencastAnimation].
+type ScreencastAnimationOptions struct {
+	// Format of the animation, [animation.GIF] by default.
+	Format animation.Format
+	// How often the animation is played, zero means forever.
+	LoopCount int
+	// JPEG quality of the captured frames, between 0 and 100.
+	Quality *int
+	// Bounds of the frame size, see [ScreencastStartOptions.Size]. Smaller
+	// frames result in smaller files.
+	Size *Size
+}
+
+// ScreencastAnimation records a screencast as an animated image, see
+// [RecordScreencastAnimation].
+type ScreencastAnimation struct {
+	frames  *ScreencastFrames
+	encoder *animation.Encoder
+	done    chan error
+}
+
+// RecordScreencastAnimation records the screencast of page as an animated GIF
+// or APNG written to w, e.g. to attach a short clip to a bug report without
+// converting a video:
+//
+//	var clip bytes.Buffer
+//	recording, _ := playwright.RecordScreencastAnimation(page, &clip)
+//	// interact with the page ...
+//	err := recording.Stop()
+//
+// Each frame is shown as long as it was presented by the browser. Frames are
+// encoded while they arrive, the browser waits for the encoder if it falls
+// behind.
+func RecordScreencastAnimation(page Page, w io.Writer, options ...ScreencastAnimationOptions) (*ScreencastAnimation, error) {
+	opts := ScreencastAnimationOptions{}
+	if len(options) == 1 {
+		opts = options[0]
+	}
+	frames, err := StartScreencastFrames(page, ScreencastFramesOptions{
+		Quality: opts.Quality,
+		Size:    opts.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	a := &ScreencastAnimation{
+		frames: frames,
+		encoder: animation.NewEncoder(w, animation.Options{
+			Format:    opts.Format,
+			LoopCount: opts.LoopCount,
+		}),
+		done: make(chan error, 1),
+	}
+	go a.encode()
+	return a, nil
+}
+
+func (a *ScreencastAnimation) encode() {
+	var err error
+	captured := false
+	// Keep receiving after an error, so the screencast isn't blocked.
+	for frame := range a.frames.Frames() {
+		if err == nil {
+			err = a.encoder.Add(frame.Image, frame.Timestamp)
+			captured = true
+		}
+	}
+	switch {
+	case err != nil:
+	case !captured:
+		err = errors.New("no screencast frames were captured")
+	default:
+		err = a.encoder.Close()
+	}
+	a.done <- err
+}
+
+// Stop stops the screencast and finishes the animation. It fails if no frame
+// was captured.
+func (a *ScreencastAnimation) Stop() error {
+	stopErr := a.frames.Stop()
+	return errors.Join(stopErr, <-a.done)
+}
@@ -0,0 +1,112 @@
+package playwright
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeScreencast struct {
+	Screencast
+	stopped bool
+}
+
+func (s *fakeScreencast) Stop() error {
+	s.stopped = true
+	return nil
+}
+
+func jpegFrame(t *testing.T, timestamp float64) OnFrame {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var data bytes.Buffer
+	require.NoError(t, jpeg.Encode(&data, img, nil))
+	return OnFrame{Data: data.Bytes(), Timestamp: timestamp, ViewportWidth: 40, ViewportHeight: 20}
+}
+
+func newTestScreencastFrames(overflow FrameOverflow, buffer int) (*ScreencastFrames, *fakeScreencast) {
+	screencast := &fakeScreencast{}
+	return &ScreencastFrames{
+		screencast: screencast,
+		overflow:   overflow,
+		frames:     make(chan ScreencastFrame, buffer),
+		done:       make(chan struct{}),
+	}, screencast
+}
+
+func TestDecodeScreencastFrame(t *testing.T) {
+	frame, err := DecodeScreencastFrame(jpegFrame(t, 1700000000123.5))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 4, 2), frame.Image.Bounds())
+	r, g, b, _ := frame.Image.At(1, 1).RGBA()
+	require.InDelta(t, 0xffff, r, 0x300)
+	require.InDelta(t, 0xffff, g, 0x300)
+	require.InDelta(t, 0xffff, b, 0x300)
+	require.Equal(t, time.UnixMilli(1700000000123).Add(500*time.Microsecond), frame.Timestamp)
+	require.Equal(t, 40, frame.ViewportWidth)
+	require.Equal(t, 20, frame.ViewportHeight)
+
+	_, err = DecodeScreencastFrame(OnFrame{Data: []byte("not a jpeg")})
+	require.Error(t, err)
+}
+
+func TestScreencastFramesDropNewest(t *testing.T) {
+	frames, _ := newTestScreencastFrames(FrameOverflowDropNewest, 2)
+	for i := 1; i <= 4; i++ {
+		frames.onFrame(jpegFrame(t, float64(i)))
+	}
+	require.Equal(t, 2, frames.Dropped())
+	require.NoError(t, frames.Stop())
+	var timestamps []int64
+	for frame := range frames.Frames() {
+		timestamps = append(timestamps, frame.Timestamp.UnixMilli())
+	}
+	require.Equal(t, []int64{1, 2}, timestamps)
+}
+
+func TestScreencastFramesDropOldest(t *testing.T) {
+	frames, _ := newTestScreencastFrames(FrameOverflowDropOldest, 2)
+	for i := 1; i <= 4; i++ {
+		frames.onFrame(jpegFrame(t, float64(i)))
+	}
+	require.Equal(t, 2, frames.Dropped())
+	require.NoError(t, frames.Stop())
+	var timestamps []int64
+	for frame := range frames.Frames() {
+		timestamps = append(timestamps, frame.Timestamp.UnixMilli())
+	}
+	require.Equal(t, []int64{3, 4}, timestamps)
+}
+
+func TestScreencastFramesBlockUntilStopped(t *testing.T) {
+	frames, screencast := newTestScreencastFrames(FrameOverflowBlock, 1)
+	frames.onFrame(jpegFrame(t, 1))
+	delivered := make(chan struct{})
+	go func() {
+		frames.onFrame(jpegFrame(t, 2))
+		close(delivered)
+	}()
+	select {
+	case <-delivered:
+		t.Fatal("frame should wait for room in the channel")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, frames.Stop())
+	<-delivered
+	require.True(t, screencast.stopped)
+	require.Equal(t, 0, frames.Dropped())
+	frames.onFrame(jpegFrame(t, 3))
+	count := 0
+	for range frames.Frames() {
+		count++
+	}
+	require.Equal(t, 1, count)
+	require.NoError(t, frames.Stop())
+}
@@ -0,0 +1,398 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ScreencastViewerOptions are the options for [NewScreencastViewer].
+type ScreencastViewerOptions struct {
+	// JPEG quality of the frames, between 0 and 100.
+	Quality *int
+	// Bounds of the frame size, see [ScreencastStartOptions.Size].
+	Size *Size
+	// Annotate actions on watched pages, see [Screencast.ShowActions].
+	ShowActions *ScreencastShowActionsOptions
+}
+
+// ScreencastViewer is an [http.Handler] to watch running pages live in a
+// browser. It serves an index of the open pages at its root, a page with the
+// live view of every page, and the view itself as a multipart MJPEG stream at
+// pages/{id}/stream, which also works in tools like VLC or ffplay. Links are
+// relative, so the handler can be mounted under a prefix:
+//
+//	viewer := playwright.NewScreencastViewer(browser)
+//	defer viewer.Close()
+//	http.Handle("/live/", http.StripPrefix("/live", viewer))
+//	go http.ListenAndServe("localhost:8080", nil)
+//
+// The screencast of a page runs while it is watched. A page can only have one
+// screencast, so pages using [Screencast.Start] otherwise can't be watched.
+type ScreencastViewer struct {
+	browser Browser
+	options ScreencastViewerOptions
+	mux     *http.ServeMux
+
+	mu       sync.Mutex
+	contexts []BrowserContext
+	ids      map[Page]string
+	pages    map[string]Page
+	feeds    map[Page]*screencastFeed
+	nextID   int
+}
+
+// NewScreencastViewer returns a viewer listing the pages of all contexts of
+// browser, which may be nil if contexts are added with AddContext.
+func NewScreencastViewer(browser Browser, options ...ScreencastViewerOptions) *ScreencastViewer {
+	v := &ScreencastViewer{
+		browser: browser,
+		mux:     http.NewServeMux(),
+		ids:     make(map[Page]string),
+		pages:   make(map[string]Page),
+		feeds:   make(map[Page]*screencastFeed),
+	}
+	if len(options) == 1 {
+		v.options = options[0]
+	}
+	v.mux.HandleFunc("GET /{$}", v.serveIndex)
+	v.mux.HandleFunc("GET /pages/{id}", v.serveView)
+	v.mux.HandleFunc("GET /pages/{id}/stream", v.serveStream)
+	return v
+}
+
+// AddContext adds the pages of context to the viewer, for contexts that don't
+// belong to the browser of the viewer, e.g. persistent contexts.
+func (v *ScreencastViewer) AddContext(context BrowserContext) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.contexts = append(v.contexts, context)
+}
+
+func (v *ScreencastViewer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mux.ServeHTTP(w, r)
+}
+
+// Close stops all screencasts and ends the streams.
+func (v *ScreencastViewer) Close() error {
+	v.mu.Lock()
+	feeds := make([]*screencastFeed, 0, len(v.feeds))
+	for _, feed := range v.feeds {
+		feeds = append(feeds, feed)
+	}
+	v.feeds = make(map[Page]*screencastFeed)
+	v.mu.Unlock()
+	for _, feed := range feeds {
+		feed.close()
+	}
+	return nil
+}
+
+type viewerPage struct {
+	ID      string
+	URL     string
+	Title   string
+	Context int
+}
+
+// openPages returns the open pages of all contexts and assigns them IDs.
+func (v *ScreencastViewer) openPages() []viewerPage {
+	v.mu.Lock()
+	contexts := append([]BrowserContext(nil), v.contexts...)
+	v.mu.Unlock()
+	if v.browser != nil {
+		contexts = append(v.browser.Contexts(), contexts...)
+	}
+	var pages []viewerPage
+	seen := make(map[BrowserContext]bool)
+	for i, context := range contexts {
+		if seen[context] {
+			continue
+		}
+		seen[context] = true
+		for _, page := range context.Pages() {
+			if page.IsClosed() {
+				continue
+			}
+			title, _ := page.Title()
+			pages = append(pages, viewerPage{
+				ID:      v.pageID(page),
+				URL:     page.URL(),
+				Title:   title,
+				Context: i + 1,
+			})
+		}
+	}
+	return pages
+}
+
+func (v *ScreencastViewer) pageID(page Page) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if id, ok := v.ids[page]; ok {
+		return id
+	}
+	v.nextID++
+	id := strconv.Itoa(v.nextID)
+	v.ids[page] = id
+	v.pages[id] = page
+	page.OnClose(func(Page) {
+		v.mu.Lock()
+		delete(v.ids, page)
+		delete(v.pages, id)
+		feed := v.feeds[page]
+		delete(v.feeds, page)
+		v.mu.Unlock()
+		if feed != nil {
+			feed.close()
+		}
+	})
+	return id
+}
+
+func (v *ScreencastViewer) page(r *http.Request) Page {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.pages[r.PathValue("id")]
+}
+
+var screencastViewerIndex = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>Playwright pages</title>
+<style>body { font-family: sans-serif; margin: 2em; } td { padding: 0.3em 1em 0.3em 0; }</style>
+</head>
+<body>
+<h1>Pages</h1>
+{{if .}}<table>
+<tr><th>Context</th><th>Title</th><th>URL</th></tr>
+{{range .}}<tr><td>{{.Context}}</td><td><a href="pages/{{.ID}}">{{or .Title "(untitled)"}}</a></td><td>{{.URL}}</td></tr>
+{{end}}</table>{{else}}<p>No open pages.</p>{{end}}
+</body>
+</html>
+`))
+
+var screencastViewerView = template.Must(template.New("view").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>body { font-family: sans-serif; margin: 2em; } img { max-width: 100%; border: 1px solid #ccc; }</style>
+</head>
+<body>
+<p><a href="../">All pages</a> · {{.URL}}</p>
+<img src="{{.ID}}/stream" alt="Live view">
+</body>
+</html>
+`))
+
+func (v *ScreencastViewer) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := screencastViewerIndex.Execute(w, v.openPages()); err != nil {
+		logger.Error("Error rendering screencast viewer", "error", err)
+	}
+}
+
+func (v *ScreencastViewer) serveView(w http.ResponseWriter, r *http.Request) {
+	page := v.page(r)
+	if page == nil {
+		http.NotFound(w, r)
+		return
+	}
+	title, _ := page.Title()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := screencastViewerView.Execute(w, viewerPage{ID: r.PathValue("id"), URL: page.URL(), Title: title})
+	if err != nil {
+		logger.Error("Error rendering screencast viewer", "error", err)
+	}
+}
+
+func (v *ScreencastViewer) serveStream(w http.ResponseWriter, r *http.Request) {
+	page := v.page(r)
+	if page == nil {
+		http.NotFound(w, r)
+		return
+	}
+	v.mu.Lock()
+	feed, ok := v.feeds[page]
+	if !ok {
+		feed = &screencastFeed{page: page, options: v.options, subscribers: make(map[chan []byte]bool)}
+		v.feeds[page] = feed
+	}
+	v.mu.Unlock()
+	frames, err := feed.subscribe()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer feed.unsubscribe(frames)
+
+	const boundary = "frame"
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+boundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case frame, ok := <-frames:
+			if !ok {
+				return
+			}
+			_, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, len(frame))
+			if err == nil {
+				// frame is shared with the other subscribers, don't append to it.
+				_, err = w.Write(frame)
+			}
+			if err == nil {
+				_, err = io.WriteString(w, "\r\n")
+			}
+			if err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// screencastFeed runs the screencast of a page while it has subscribers and
+// passes the latest frame to each of them.
+type screencastFeed struct {
+	page    Page
+	options ScreencastViewerOptions
+
+	// lifecycle orders starting and stopping the screencast. mu is not held
+	// while talking to the driver, so frames keep being dispatched.
+	lifecycle   sync.Mutex
+	mu          sync.Mutex
+	screencast  Screencast
+	subscribers map[chan []byte]bool
+	closed      bool
+}
+
+func (f *screencastFeed) subscribe() (chan []byte, error) {
+	f.lifecycle.Lock()
+	defer f.lifecycle.Unlock()
+	f.mu.Lock()
+	closed, running := f.closed, f.screencast != nil
+	f.mu.Unlock()
+	if closed {
+		return nil, errors.New("page is closed")
+	}
+	var screencast Screencast
+	if !running {
+		var err error
+		if screencast, err = f.start(); err != nil {
+			return nil, err
+		}
+	}
+	frames := make(chan []byte, 1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if screencast != nil {
+		f.screencast = screencast
+	}
+	f.subscribers[frames] = true
+	return frames, nil
+}
+
+func (f *screencastFeed) start() (Screencast, error) {
+	screencast, err := f.page.Screencast()
+	if err != nil {
+		return nil, err
+	}
+	err = screencast.Start(ScreencastStartOptions{
+		OnFrame: f.onFrame,
+		Quality: f.options.Quality,
+		Size:    f.options.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if f.options.ShowActions != nil {
+		if err := screencast.ShowActions(*f.options.ShowActions); err != nil {
+			logger.Error("Error showing actions", "error", err)
+		}
+	}
+	return screencast, nil
+}
+
+func (f *screencastFeed) unsubscribe(frames chan []byte) {
+	f.lifecycle.Lock()
+	defer f.lifecycle.Unlock()
+	f.mu.Lock()
+	if !f.subscribers[frames] {
+		f.mu.Unlock()
+		return
+	}
+	delete(f.subscribers, frames)
+	var screencast Screencast
+	if len(f.subscribers) == 0 {
+		screencast = f.detach()
+	}
+	f.mu.Unlock()
+	f.stop(screencast)
+}
+
+// onFrame passes frame to every subscriber, replacing a frame it hasn't
+// received yet.
+func (f *screencastFeed) onFrame(frame OnFrame) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for subscriber := range f.subscribers {
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- frame.Data
+	}
+}
+
+// close ends all streams of the feed.
+func (f *screencastFeed) close() {
+	f.lifecycle.Lock()
+	defer f.lifecycle.Unlock()
+	f.mu.Lock()
+	f.closed = true
+	for subscriber := range f.subscribers {
+		close(subscriber)
+	}
+	f.subscribers = make(map[chan []byte]bool)
+	screencast := f.detach()
+	f.mu.Unlock()
+	f.stop(screencast)
+}
+
+// detach returns the running screencast and forgets it, f.mu must be held.
+func (f *screencastFeed) detach() Screencast {
+	screencast := f.screencast
+	f.screencast = nil
+	return screencast
+}
+
+// stop stops screencast, which may be nil.
+func (f *screencastFeed) stop(screencast Screencast) {
+	if screencast == nil || f.page.IsClosed() {
+		return
+	}
+	if f.options.ShowActions != nil {
+		if err := screencast.HideActions(); err != nil {
+			logger.Error("Error hiding actions", "error", err)
+		}
+	}
+	if err := screencast.Stop(); err != nil {
+		logger.Error("Error stopping screencast", "error", err)
+	}
+}
@@ -0,0 +1,253 @@
+package playwright
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeViewerScreencast struct {
+	Screencast
+	mu          sync.Mutex
+	onFrame     func(OnFrame)
+	started     int
+	stopped     int
+	showActions int
+	// frameOnStop dispatches a frame while stopping, like a frame event
+	// arriving before the driver answers.
+	frameOnStop bool
+}
+
+func (s *fakeViewerScreencast) Start(options ...ScreencastStartOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onFrame = options[0].OnFrame
+	s.started++
+	return nil
+}
+
+func (s *fakeViewerScreencast) Stop() error {
+	if s.frameOnStop {
+		s.send("last frame")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onFrame = nil
+	s.stopped++
+	return nil
+}
+
+func (s *fakeViewerScreencast) ShowActions(options ...ScreencastShowActionsOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.showActions++
+	return nil
+}
+
+func (s *fakeViewerScreencast) HideActions() error { return nil }
+
+func (s *fakeViewerScreencast) send(data string) bool {
+	s.mu.Lock()
+	onFrame := s.onFrame
+	s.mu.Unlock()
+	if onFrame == nil {
+		return false
+	}
+	// Spare capacity, so appending to a shared frame would overwrite it.
+	frame := make([]byte, len(data), len(data)+16)
+	copy(frame, data)
+	onFrame(OnFrame{Data: frame})
+	return true
+}
+
+type fakeViewerPage struct {
+	Page
+	url        string
+	title      string
+	screencast *fakeViewerScreencast
+}
+
+func (p *fakeViewerPage) URL() string                     { return p.url }
+func (p *fakeViewerPage) Title() (string, error)          { return p.title, nil }
+func (p *fakeViewerPage) IsClosed() bool                  { return false }
+func (p *fakeViewerPage) OnClose(func(Page))              {}
+func (p *fakeViewerPage) Screencast() (Screencast, error) { return p.screencast, nil }
+
+type fakeViewerContext struct {
+	BrowserContext
+	pages []Page
+}
+
+func (c *fakeViewerContext) Pages() []Page { return c.pages }
+
+func TestScreencastViewerIndex(t *testing.T) {
+	viewer := NewScreencastViewer(nil)
+	viewer.AddContext(&fakeViewerContext{pages: []Page{
+		&fakeViewerPage{url: "https://example.com/", title: "Example <Domain>"},
+		&fakeViewerPage{url: "about:blank"},
+	}})
+	server := httptest.NewServer(viewer)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Contains(t, string(body), `<a href="pages/1">Example &lt;Domain&gt;</a>`)
+	require.Contains(t, string(body), `https://example.com/`)
+	require.Contains(t, string(body), `<a href="pages/2">(untitled)</a>`)
+
+	resp, err = http.Get(server.URL + "/pages/1")
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Contains(t, string(body), `<img src="1/stream"`)
+
+	resp, err = http.Get(server.URL + "/pages/7")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestScreencastViewerStream(t *testing.T) {
+	screencast := &fakeViewerScreencast{}
+	viewer := NewScreencastViewer(nil, ScreencastViewerOptions{
+		ShowActions: &ScreencastShowActionsOptions{},
+	})
+	viewer.AddContext(&fakeViewerContext{pages: []Page{
+		&fakeViewerPage{url: "https://example.com/", screencast: screencast},
+	}})
+	server := httptest.NewServer(viewer)
+	defer server.Close()
+	viewer.openPages()
+
+	resp, err := http.Get(server.URL + "/pages/1/stream")
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/x-mixed-replace", mediaType)
+
+	// A part ends with the boundary of the next one, so keep sending frames.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+				screencast.send("frame-1")
+			}
+		}
+	}()
+	reader := multipart.NewReader(bufio.NewReader(resp.Body), params["boundary"])
+	part, err := reader.NextPart()
+	require.NoError(t, err)
+	require.Equal(t, "image/jpeg", part.Header.Get("Content-Type"))
+	data, err := io.ReadAll(part)
+	require.NoError(t, err)
+	require.Equal(t, "frame-1", string(data))
+
+	require.NoError(t, resp.Body.Close())
+	require.Eventually(t, func() bool {
+		screencast.mu.Lock()
+		defer screencast.mu.Unlock()
+		return screencast.stopped == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	screencast.mu.Lock()
+	require.Equal(t, 1, screencast.started)
+	require.Equal(t, 1, screencast.showActions)
+	screencast.mu.Unlock()
+	require.NoError(t, viewer.Close())
+}
+
+func TestScreencastViewerStreamSharesFrames(t *testing.T) {
+	screencast := &fakeViewerScreencast{}
+	viewer := NewScreencastViewer(nil)
+	viewer.AddContext(&fakeViewerContext{pages: []Page{
+		&fakeViewerPage{url: "https://example.com/", screencast: screencast},
+	}})
+	server := httptest.NewServer(viewer)
+	defer server.Close()
+	viewer.openPages()
+
+	readers := make([]*multipart.Reader, 2)
+	for i := range readers {
+		resp, err := http.Get(server.URL + "/pages/1/stream")
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		readers[i] = multipart.NewReader(bufio.NewReader(resp.Body), params["boundary"])
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+				screencast.send("frame")
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for _, reader := range readers {
+		wg.Add(1)
+		go func(reader *multipart.Reader) {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				part, err := reader.NextPart()
+				if !assert.NoError(t, err) {
+					return
+				}
+				data, err := io.ReadAll(part)
+				assert.NoError(t, err)
+				assert.Equal(t, "frame", string(data))
+			}
+		}(reader)
+	}
+	wg.Wait()
+	require.NoError(t, viewer.Close())
+}
+
+func TestScreencastFeedStopsWithoutHoldingLock(t *testing.T) {
+	screencast := &fakeViewerScreencast{frameOnStop: true}
+	feed := &screencastFeed{
+		page:        &fakeViewerPage{screencast: screencast},
+		subscribers: make(map[chan []byte]bool),
+	}
+	frames, err := feed.subscribe()
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		feed.unsubscribe(frames)
+		frames, err = feed.subscribe()
+		assert.NoError(t, err)
+		feed.close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopping the screencast blocked on a frame")
+	}
+	screencast.mu.Lock()
+	defer screencast.mu.Unlock()
+	require.Equal(t, 2, screencast.started)
+	require.Equal(t, 2, screencast.stopped)
+	_, ok := <-frames
+	require.False(t, ok)
+}
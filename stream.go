@@ -1,64 +1,81 @@
 package playwright
 
 import (
-	"bufio"
 	"encoding/base64"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 )
 
 type streamImpl struct {
 	channelOwner
+	pending []byte
+	eof     bool
 }
 
-func (s *streamImpl) SaveAs(path string) error {
-	err := os.MkdirAll(filepath.Dir(path), 0o777)
-	if err != nil {
-		return err
+// Read reads the stream in chunks of up to 1 MiB, so it can be consumed
+// without holding all of it in memory.
+func (s *streamImpl) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
 	}
-	file, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close() //nolint:errcheck
-	writer := bufio.NewWriter(file)
-	for {
+	if len(s.pending) == 0 {
+		if s.eof {
+			return 0, io.EOF
+		}
 		binary, err := s.channel.Send("read", map[string]any{"size": 1024 * 1024})
 		if err != nil {
-			return err
+			return 0, err
 		}
 		bytes, err := base64.StdEncoding.DecodeString(binary.(string))
 		if err != nil {
-			return err
+			return 0, err
 		}
 		if len(bytes) == 0 {
-			break
-		}
-		_, err = writer.Write(bytes)
-		if err != nil {
-			return err
+			s.eof = true
+			return 0, io.EOF
 		}
+		s.pending = bytes
 	}
-	return writer.Flush()
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *streamImpl) Close() error {
+	s.pending = nil
+	s.eof = true
+	_, err := s.channel.Send("close")
+	return err
+}
+
+func (s *streamImpl) SaveAs(path string) error {
+	return saveReaderAs(path, s)
 }
 
 func (s *streamImpl) ReadAll() ([]byte, error) {
-	var data []byte
-	for {
-		binary, err := s.channel.Send("read", map[string]any{"size": 1024 * 1024})
-		if err != nil {
-			return nil, err
-		}
-		bytes, err := base64.StdEncoding.DecodeString(binary.(string))
-		if err != nil {
-			return nil, err
-		}
-		if len(bytes) == 0 {
-			break
-		}
-		data = append(data, bytes...)
+	return io.ReadAll(s)
+}
+
+// saveReaderAs writes everything read from r to path, creating its directory.
+// The file is removed again if reading fails.
+func saveReaderAs(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(path))
 	}
-	return data, nil
+	return nil
 }
 
 func newStream(parent *channelOwner, objectType string, guid string, initializer map[string]any) *streamImpl {
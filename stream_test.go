@@ -0,0 +1,28 @@
+package playwright
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("broken") }
+
+func TestSaveReaderAsRemovesPartialFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.txt")
+	err := saveReaderAs(path, io.MultiReader(io.LimitReader(zeroReader{}, 10), failingReader{}))
+	require.ErrorContains(t, err, "broken")
+	require.NoFileExists(t, path)
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
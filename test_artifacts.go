@@ -0,0 +1,285 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// ArtifactsMode decides when [NewTestContext] keeps an artifact of a test.
+type ArtifactsMode string
+
+const (
+	// ArtifactsOff doesn't record the artifact.
+	ArtifactsOff ArtifactsMode = "off"
+	// ArtifactsOn keeps the artifact of every test.
+	ArtifactsOn ArtifactsMode = "on"
+	// ArtifactsRetainOnFailure records the artifact of every test but only
+	// keeps it if the test failed. Screenshots are only taken on failure.
+	ArtifactsRetainOnFailure ArtifactsMode = "retain-on-failure"
+)
+
+// TestingTB is the part of [testing.TB] used by [NewTestContext].
+type TestingTB interface {
+	Name() string
+	Helper()
+	Cleanup(func())
+	Failed() bool
+	Errorf(format string, args ...any)
+	Logf(format string, args ...any)
+}
+
+// TestContextOptions are the options for [NewTestContext].
+type TestContextOptions struct {
+	// Directory the artifacts are kept in, each test gets a subdirectory
+	// named after the test, which is cleared when the context is created.
+	// Defaults to "test-results".
+	Dir string
+	// When to keep the trace, recorded with screenshots and snapshots.
+	// Defaults to ArtifactsRetainOnFailure.
+	Trace ArtifactsMode
+	// When to keep the videos of the pages. Defaults to
+	// ArtifactsRetainOnFailure.
+	Video ArtifactsMode
+	// When to keep a screenshot of the pages open at the end of the test.
+	// Defaults to ArtifactsRetainOnFailure.
+	Screenshot ArtifactsMode
+	// Options the context is created with. RecordVideo.Dir is replaced by a
+	// temporary directory unless Video is ArtifactsOff.
+	Context BrowserNewContextOptions
+}
+
+// NewTestContext creates a context for the test t which records a trace and
+// videos, and closes the context when the test ends:
+//
+//	func TestLogin(t *testing.T) {
+//		context, err := playwright.NewTestContext(t, browser)
+//		require.NoError(t, err)
+//		page, err := context.NewPage()
+//		...
+//	}
+//
+// By default the trace, the videos and a final screenshot of every open page
+// are only kept if the test failed, in Dir/<test name>, e.g.
+// test-results/TestLogin/trace.zip. The trace can be opened with
+// "playwright show-trace" or the trace package. Otherwise they are discarded.
+// Errors while saving the artifacts are reported with t.Errorf.
+func NewTestContext(t TestingTB, browser Browser, options ...TestContextOptions) (BrowserContext, error) {
+	t.Helper()
+	option := TestContextOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	if option.Dir == "" {
+		option.Dir = "test-results"
+	}
+	for _, mode := range []*ArtifactsMode{&option.Trace, &option.Video, &option.Screenshot} {
+		switch *mode {
+		case "":
+			*mode = ArtifactsRetainOnFailure
+		case ArtifactsOff, ArtifactsOn, ArtifactsRetainOnFailure:
+		default:
+			return nil, fmt.Errorf("invalid artifacts mode: %q", *mode)
+		}
+	}
+
+	testDir := filepath.Join(option.Dir, testDirName(t.Name()))
+	if err := os.RemoveAll(testDir); err != nil {
+		return nil, err
+	}
+
+	contextOptions := option.Context
+	videoDir := ""
+	if option.Video != ArtifactsOff {
+		dir, err := os.MkdirTemp("", "playwright-videos-")
+		if err != nil {
+			return nil, err
+		}
+		videoDir = dir
+		recordVideo := RecordVideo{}
+		if contextOptions.RecordVideo != nil {
+			recordVideo = *contextOptions.RecordVideo
+		}
+		recordVideo.Dir = &videoDir
+		contextOptions.RecordVideo = &recordVideo
+	}
+	context, err := browser.NewContext(contextOptions)
+	if err != nil {
+		if videoDir != "" {
+			os.RemoveAll(videoDir) //nolint:errcheck
+		}
+		return nil, err
+	}
+	recorder := &testRecorder{
+		t:        t,
+		options:  option,
+		context:  context,
+		dir:      testDir,
+		videoDir: videoDir,
+	}
+	context.OnPage(recorder.onPage)
+	context.OnClose(func(BrowserContext) {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.closed = true
+	})
+	if option.Trace != ArtifactsOff {
+		err := context.Tracing().Start(TracingStartOptions{
+			Title:       String(t.Name()),
+			Screenshots: Bool(true),
+			Snapshots:   Bool(true),
+			Sources:     Bool(true),
+		})
+		if err != nil {
+			recorder.cleanup()
+			return nil, err
+		}
+		recorder.tracing = true
+	}
+	t.Cleanup(recorder.cleanup)
+	return context, nil
+}
+
+// testRecorder collects the artifacts of a test context.
+type testRecorder struct {
+	t        TestingTB
+	options  TestContextOptions
+	context  BrowserContext
+	dir      string
+	videoDir string
+	// tracing is set once the tracing was started.
+	tracing bool
+
+	mu     sync.Mutex
+	pages  []Page
+	closed bool
+}
+
+func (r *testRecorder) onPage(page Page) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages = append(r.pages, page)
+}
+
+// keep reports whether an artifact recorded with mode is kept.
+func (r *testRecorder) keep(mode ArtifactsMode) bool {
+	return mode == ArtifactsOn || (mode == ArtifactsRetainOnFailure && r.t.Failed())
+}
+
+// path returns the path of an artifact in the directory of the test, creating
+// the directory.
+func (r *testRecorder) path(name string) (string, error) {
+	if err := os.MkdirAll(r.dir, 0o777); err != nil {
+		return "", err
+	}
+	return filepath.Join(r.dir, name), nil
+}
+
+// cleanup saves or discards the artifacts and closes the context.
+func (r *testRecorder) cleanup() {
+	r.mu.Lock()
+	pages := append([]Page(nil), r.pages...)
+	closed := r.closed
+	r.mu.Unlock()
+
+	var errs []error
+	if !closed {
+		if r.keep(r.options.Screenshot) {
+			errs = append(errs, r.saveScreenshots(pages))
+		}
+		if r.tracing {
+			errs = append(errs, r.stopTracing())
+		}
+		errs = append(errs, r.context.Close())
+	} else if r.tracing && r.keep(r.options.Trace) {
+		r.t.Logf("trace not saved, the context was closed by the test")
+	}
+	if r.options.Video != ArtifactsOff {
+		errs = append(errs, r.saveVideos(pages))
+		errs = append(errs, os.RemoveAll(r.videoDir))
+	}
+	if err := errors.Join(errs...); err != nil {
+		r.t.Errorf("could not save test artifacts: %v", err)
+	}
+}
+
+func (r *testRecorder) saveScreenshots(pages []Page) error {
+	var open []Page
+	for _, page := range pages {
+		if !page.IsClosed() {
+			open = append(open, page)
+		}
+	}
+	for i, page := range open {
+		path, err := r.path(numberedName("screenshot", i, len(open), ".png"))
+		if err != nil {
+			return err
+		}
+		if _, err := page.Screenshot(PageScreenshotOptions{Path: &path}); err != nil {
+			return err
+		}
+		r.t.Logf("screenshot: %s", path)
+	}
+	return nil
+}
+
+func (r *testRecorder) stopTracing() error {
+	if !r.keep(r.options.Trace) {
+		return r.context.Tracing().Stop()
+	}
+	path, err := r.path("trace.zip")
+	if err != nil {
+		return err
+	}
+	if err := r.context.Tracing().Stop(path); err != nil {
+		return err
+	}
+	r.t.Logf("trace: %s", path)
+	return nil
+}
+
+// saveVideos keeps or deletes the videos, which are finished once the context
+// is closed.
+func (r *testRecorder) saveVideos(pages []Page) error {
+	keep := r.keep(r.options.Video)
+	var errs []error
+	for i, page := range pages {
+		video := page.Video()
+		if video == nil {
+			continue
+		}
+		if keep {
+			path, err := r.path(numberedName("video", i, len(pages), ".webm"))
+			if err == nil {
+				err = video.SaveAs(path)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				r.t.Logf("video: %s", path)
+			}
+		}
+		errs = append(errs, video.Delete())
+	}
+	return errors.Join(errs...)
+}
+
+// numberedName returns e.g. "video.webm" for a single artifact and
+// "video-2.webm" for the second of several.
+func numberedName(name string, index, count int, ext string) string {
+	if count == 1 {
+		return name + ext
+	}
+	return fmt.Sprintf("%s-%d%s", name, index+1, ext)
+}
+
+var testDirNameReplacer = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// testDirName returns a directory name for a test, subtests are separated by
+// "-", e.g. "TestLogin-invalid_password".
+func testDirName(name string) string {
+	return testDirNameReplacer.ReplaceAllString(name, "-")
+}
@@ -0,0 +1,221 @@
+package playwright
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTB struct {
+	name     string
+	failed   bool
+	cleanups []func()
+	errors   []string
+	logs     []string
+}
+
+func (t *fakeTB) Name() string      { return t.name }
+func (t *fakeTB) Helper()           {}
+func (t *fakeTB) Cleanup(fn func()) { t.cleanups = append(t.cleanups, fn) }
+func (t *fakeTB) Failed() bool      { return t.failed }
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+type fakeArtifactsTracing struct {
+	Tracing
+	startErr error
+	started  *TracingStartOptions
+	stopped  []string
+}
+
+func (t *fakeArtifactsTracing) Start(options ...TracingStartOptions) error {
+	if t.startErr != nil {
+		return t.startErr
+	}
+	t.started = &options[0]
+	return nil
+}
+
+func (t *fakeArtifactsTracing) Stop(path ...string) error {
+	if len(path) == 0 {
+		t.stopped = append(t.stopped, "")
+		return nil
+	}
+	t.stopped = append(t.stopped, path[0])
+	return os.WriteFile(path[0], []byte("trace"), 0o644)
+}
+
+type fakeArtifactsVideo struct {
+	Video
+	saved   string
+	deleted bool
+}
+
+func (v *fakeArtifactsVideo) SaveAs(path string) error {
+	v.saved = path
+	return os.WriteFile(path, []byte("video"), 0o644)
+}
+
+func (v *fakeArtifactsVideo) Delete() error {
+	v.deleted = true
+	return nil
+}
+
+type fakeArtifactsPage struct {
+	Page
+	closed bool
+	video  *fakeArtifactsVideo
+}
+
+func (p *fakeArtifactsPage) IsClosed() bool { return p.closed }
+func (p *fakeArtifactsPage) Video() Video   { return p.video }
+func (p *fakeArtifactsPage) Screenshot(options ...PageScreenshotOptions) ([]byte, error) {
+	return []byte("png"), os.WriteFile(*options[0].Path, []byte("png"), 0o644)
+}
+
+type fakeArtifactsContext struct {
+	BrowserContext
+	tracing *fakeArtifactsTracing
+	onPage  func(Page)
+	closed  int
+}
+
+func (c *fakeArtifactsContext) Tracing() Tracing                { return c.tracing }
+func (c *fakeArtifactsContext) OnPage(fn func(Page))            { c.onPage = fn }
+func (c *fakeArtifactsContext) OnClose(fn func(BrowserContext)) {}
+func (c *fakeArtifactsContext) Close(options ...BrowserContextCloseOptions) error {
+	c.closed++
+	return nil
+}
+
+func (c *fakeArtifactsContext) newPage(closed bool) *fakeArtifactsPage {
+	page := &fakeArtifactsPage{closed: closed, video: &fakeArtifactsVideo{}}
+	c.onPage(page)
+	return page
+}
+
+type fakeArtifactsBrowser struct {
+	Browser
+	startErr error
+	options  BrowserNewContextOptions
+	context  *fakeArtifactsContext
+}
+
+func (b *fakeArtifactsBrowser) NewContext(options ...BrowserNewContextOptions) (BrowserContext, error) {
+	b.options = options[0]
+	b.context = &fakeArtifactsContext{tracing: &fakeArtifactsTracing{startErr: b.startErr}}
+	return b.context, nil
+}
+
+func TestNewTestContextRetainsArtifactsOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	tb := &fakeTB{name: "TestLogin/bad password"}
+	browser := &fakeArtifactsBrowser{}
+	_, err := NewTestContext(tb, browser, TestContextOptions{
+		Dir:     dir,
+		Context: BrowserNewContextOptions{RecordVideo: &RecordVideo{Size: &Size{Width: 320, Height: 240}}},
+	})
+	require.NoError(t, err)
+	context := browser.context
+	require.NotNil(t, context.tracing.started)
+	require.True(t, *context.tracing.started.Snapshots)
+	require.Equal(t, 320, browser.options.RecordVideo.Size.Width)
+	videoDir := *browser.options.RecordVideo.Dir
+	require.DirExists(t, videoDir)
+
+	first := context.newPage(false)
+	second := context.newPage(true)
+	tb.failed = true
+	tb.finish()
+
+	require.Empty(t, tb.errors)
+	require.Equal(t, 1, context.closed)
+	testDir := filepath.Join(dir, "TestLogin-bad-password")
+	require.Equal(t, []string{filepath.Join(testDir, "trace.zip")}, context.tracing.stopped)
+	require.FileExists(t, filepath.Join(testDir, "trace.zip"))
+	require.FileExists(t, filepath.Join(testDir, "screenshot.png"))
+	require.Equal(t, filepath.Join(testDir, "video-1.webm"), first.video.saved)
+	require.Equal(t, filepath.Join(testDir, "video-2.webm"), second.video.saved)
+	require.True(t, first.video.deleted)
+	require.True(t, second.video.deleted)
+	require.NoDirExists(t, videoDir)
+}
+
+func TestNewTestContextDiscardsArtifactsOnSuccess(t *testing.T) {
+	dir := t.TempDir()
+	tb := &fakeTB{name: "TestLogin"}
+	browser := &fakeArtifactsBrowser{}
+	_, err := NewTestContext(tb, browser, TestContextOptions{Dir: dir})
+	require.NoError(t, err)
+	page := browser.context.newPage(false)
+	tb.finish()
+
+	require.Empty(t, tb.errors)
+	require.Equal(t, []string{""}, browser.context.tracing.stopped)
+	require.Empty(t, page.video.saved)
+	require.True(t, page.video.deleted)
+	require.NoDirExists(t, filepath.Join(dir, "TestLogin"))
+}
+
+func TestNewTestContextModes(t *testing.T) {
+	dir := t.TempDir()
+	tb := &fakeTB{name: "TestLogin"}
+	browser := &fakeArtifactsBrowser{}
+	_, err := NewTestContext(tb, browser, TestContextOptions{
+		Dir:        dir,
+		Trace:      ArtifactsOn,
+		Video:      ArtifactsOff,
+		Screenshot: ArtifactsOff,
+	})
+	require.NoError(t, err)
+	require.Nil(t, browser.options.RecordVideo)
+	page := browser.context.newPage(false)
+	tb.finish()
+
+	require.Empty(t, tb.errors)
+	require.FileExists(t, filepath.Join(dir, "TestLogin", "trace.zip"))
+	require.NoFileExists(t, filepath.Join(dir, "TestLogin", "screenshot.png"))
+	require.False(t, page.video.deleted)
+
+	_, err = NewTestContext(tb, browser, TestContextOptions{Trace: "on-first-retry"})
+	require.ErrorContains(t, err, "invalid artifacts mode")
+}
+
+func TestNewTestContextClearsPreviousArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "TestLogin", "video-3.webm")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o777))
+	require.NoError(t, os.WriteFile(stale, []byte("video"), 0o644))
+	tb := &fakeTB{name: "TestLogin", failed: true}
+	browser := &fakeArtifactsBrowser{}
+	_, err := NewTestContext(tb, browser, TestContextOptions{Dir: dir})
+	require.NoError(t, err)
+	require.NoFileExists(t, stale)
+	tb.finish()
+	require.FileExists(t, filepath.Join(dir, "TestLogin", "trace.zip"))
+}
+
+func TestNewTestContextTracingStartFails(t *testing.T) {
+	tb := &fakeTB{name: "TestLogin"}
+	browser := &fakeArtifactsBrowser{startErr: errors.New("tracing failed")}
+	_, err := NewTestContext(tb, browser, TestContextOptions{Dir: t.TempDir()})
+	require.EqualError(t, err, "tracing failed")
+	require.Empty(t, browser.context.tracing.stopped)
+	require.Equal(t, 1, browser.context.closed)
+	require.Empty(t, tb.errors)
+}
@@ -0,0 +1,48 @@
+package playwright_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieJarShouldShareCookiesWithHTTPClient(t *testing.T) {
+	BeforeEach(t)
+
+	server.SetRoute("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "from-client", Path: "/", HttpOnly: true})
+	})
+	server.SetRoute("/whoami", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("browser")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, cookie.Value)
+	})
+	client := &http.Client{Jar: playwright.NewCookieJar(context)}
+
+	resp, err := client.Get(server.PREFIX + "/login")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	cookies, err := context.Cookies(server.PREFIX)
+	require.NoError(t, err)
+	require.Len(t, cookies, 1)
+	require.Equal(t, "session", cookies[0].Name)
+	require.Equal(t, "from-client", cookies[0].Value)
+	require.True(t, cookies[0].HttpOnly)
+
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	_, err = page.Evaluate(`() => document.cookie = 'browser=from-page'`)
+	require.NoError(t, err)
+	resp, err = client.Get(server.PREFIX + "/whoami")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "from-page", string(body))
+}
@@ -0,0 +1,59 @@
+package playwright_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadManagerShouldSaveAllDownloads(t *testing.T) {
+	BeforeEach(t)
+
+	server.SetRoute("/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/octet-stream")
+		w.Header().Add("Content-Disposition", "attachment; filename=export.csv")
+		_, _ = w.Write([]byte("id,name\n" + r.URL.Query().Get("id")))
+	})
+	dir := t.TempDir()
+	manager, err := playwright.NewDownloadManager(context, playwright.DownloadManagerOptions{
+		Dir: dir,
+	})
+	require.NoError(t, err)
+	defer manager.Stop()
+
+	require.NoError(t, page.SetContent(fmt.Sprintf(
+		`<a id="one" href="%[1]s/export?id=1">one</a><a id="two" href="%[1]s/export?id=2">two</a>`, server.PREFIX,
+	)))
+	for _, id := range []string{"#one", "#two"} {
+		_, err := page.ExpectDownload(func() error {
+			return page.Locator(id).Click()
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, manager.Wait(30*time.Second))
+
+	results := manager.Results()
+	require.Len(t, results, 2)
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	for i, result := range results {
+		require.NoError(t, result.Err)
+		content, err := os.ReadFile(result.Path)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("id,name\n%d", i+1), string(content))
+		sum := sha256.Sum256(content)
+		require.Equal(t, hex.EncodeToString(sum[:]), result.SHA256)
+		require.Equal(t, int64(len(content)), result.Size)
+	}
+	paths := []string{filepath.Base(results[0].Path), filepath.Base(results[1].Path)}
+	sort.Strings(paths)
+	require.Equal(t, []string{"export (1).csv", "export.csv"}, paths)
+}
@@ -0,0 +1,68 @@
+package playwright_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyShouldRetryUnavailableResponses(t *testing.T) {
+	BeforeEach(t)
+
+	var requests atomic.Int32
+	server.SetRoute("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+	var attempts []playwright.RetryAttempt
+	require.NoError(t, playwright.SetRetryPolicy(page.Request(), &playwright.RetryPolicy{
+		InitialInterval: 10 * time.Millisecond,
+		OnAttempt: func(attempt playwright.RetryAttempt) {
+			attempts = append(attempts, attempt)
+		},
+	}))
+	defer func() {
+		require.NoError(t, playwright.SetRetryPolicy(page.Request(), nil))
+	}()
+
+	response, err := page.Request().Get(server.PREFIX + "/flaky")
+	require.NoError(t, err)
+	require.Equal(t, 200, response.Status())
+	body, err := response.Text()
+	require.NoError(t, err)
+	require.Equal(t, "ok", body)
+	require.EqualValues(t, 3, requests.Load())
+	require.Len(t, attempts, 3)
+	require.Equal(t, 503, attempts[0].Response.Status())
+	require.True(t, attempts[0].Retry)
+	require.False(t, attempts[2].Retry)
+}
+
+func TestWithRetryPolicyShouldNotRetryPostByDefault(t *testing.T) {
+	BeforeEach(t)
+
+	var requests atomic.Int32
+	server.SetRoute("/unavailable", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	request := playwright.WithRetryPolicy(page.Request(), playwright.RetryPolicy{InitialInterval: time.Millisecond})
+
+	response, err := request.Post(server.PREFIX + "/unavailable")
+	require.NoError(t, err)
+	require.Equal(t, 503, response.Status())
+	require.EqualValues(t, 1, requests.Load())
+
+	response, err = request.Get(server.PREFIX + "/unavailable")
+	require.NoError(t, err)
+	require.Equal(t, 503, response.Status())
+	require.EqualValues(t, 4, requests.Load())
+}
@@ -0,0 +1,50 @@
+package playwright_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTripperShouldShareBrowserCookies(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, context.AddCookies([]playwright.OptionalCookie{
+		{Name: "session", Value: "secret", URL: playwright.String(server.PREFIX)},
+	}))
+	server.SetRedirect("/redirect", "/echo")
+	server.SetRoute("/echo", func(w http.ResponseWriter, r *http.Request) {
+		cookie, _ := r.Cookie("session")
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		_, _ = io.WriteString(w, cookie.Value+":"+string(body))
+	})
+	var redirects []string
+	client := &http.Client{
+		Transport: playwright.RoundTripper(page.Request()),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			redirects = append(redirects, req.URL.Path)
+			return nil
+		},
+	}
+
+	resp, err := client.Get(server.PREFIX + "/redirect")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "secret:", string(body))
+	require.Equal(t, []string{"/echo"}, redirects)
+
+	resp, err = client.Post(server.PREFIX+"/echo", "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "secret:payload", string(body))
+	require.Equal(t, "POST", resp.Header.Get("X-Method"))
+}
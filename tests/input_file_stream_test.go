@@ -0,0 +1,130 @@
+package playwright_test
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetInputFilesShouldStreamFromReader(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.PREFIX + "/input/fileupload.html")
+	require.NoError(t, err)
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	content := strings.Repeat("streamed ", 300_000)
+	require.NoError(t, page.Locator("input").SetInputFiles(playwright.InputFileStream{
+		Name:         "large.txt",
+		Reader:       strings.NewReader(content),
+		LastModified: modified,
+	}))
+
+	result, err := page.Locator("input").Evaluate(`async e => {
+		const file = e.files[0];
+		return { name: file.name, size: file.size, lastModified: file.lastModified, text: await file.text() };
+	}`, nil)
+	require.NoError(t, err)
+	file := result.(map[string]interface{})
+	require.Equal(t, "large.txt", file["name"])
+	require.EqualValues(t, len(content), file["size"])
+	require.EqualValues(t, modified.UnixMilli(), file["lastModified"])
+	require.Equal(t, content, file["text"])
+}
+
+func TestSetInputFilesShouldFailWhenAStreamCannotBeOpened(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`<input type=file multiple>`))
+	err := page.Locator("input").SetInputFiles([]playwright.InputFileStream{
+		{Name: "a.txt", Reader: strings.NewReader("a content")},
+		{Name: "b.txt"},
+		{Name: "c.txt", Reader: strings.NewReader("c content")},
+	})
+	require.ErrorContains(t, err, `input file "b.txt" has no reader`)
+
+	require.NoError(t, page.Locator("input").SetInputFiles(playwright.InputFileStream{
+		Name:   "d.txt",
+		Reader: strings.NewReader("d content"),
+	}))
+	result, err := page.Locator("input").Evaluate(`e => Promise.all([...e.files].map(async f => f.name + ":" + await f.text()))`, nil)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"d.txt:d content"}, result)
+}
+
+func TestSetInputFilesShouldUploadFilesFromFS(t *testing.T) {
+	BeforeEach(t)
+
+	fixtures := fstest.MapFS{
+		"fixtures/a.txt": {Data: []byte("a content")},
+		"fixtures/b.txt": {Data: []byte("b content")},
+	}
+	require.NoError(t, page.SetContent(`<input type=file multiple>`))
+	require.NoError(t, page.Locator("input").SetInputFiles(playwright.InputFilesFS{
+		FS:    fixtures,
+		Paths: []string{"fixtures/a.txt", "fixtures/b.txt"},
+	}))
+	result, err := page.Locator("input").Evaluate(`e => Promise.all([...e.files].map(async f => f.name + ":" + await f.text()))`, nil)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"a.txt:a content", "b.txt:b content"}, result)
+}
+
+func TestSetInputFilesShouldUploadDirectoryFromFS(t *testing.T) {
+	BeforeEach(t)
+
+	fixtures := fstest.MapFS{
+		"fixtures/upload/file1.txt": {Data: []byte("file1 content")},
+		"fixtures/upload/file2":     {Data: []byte("file2 content")},
+	}
+	_, err := page.Goto(server.PREFIX + "/input/folderupload.html")
+	require.NoError(t, err)
+	require.NoError(t, page.Locator("input").SetInputFiles(playwright.InputFilesFS{
+		FS:    fixtures,
+		Paths: []string{"fixtures/upload"},
+	}, playwright.LocatorSetInputFilesOptions{
+		Timeout: playwright.Float(90 * 1000),
+	}))
+	result, err := page.Locator("input").Evaluate(`e => Promise.all([...e.files].map(async f => f.webkitRelativePath + ":" + await f.text()))`, nil)
+	require.NoError(t, err)
+	paths := result.([]interface{})
+	slices.SortFunc(paths, func(i, j interface{}) int {
+		return strings.Compare(i.(string), j.(string))
+	})
+	require.Equal(t, []interface{}{"upload/file1.txt:file1 content", "upload/file2:file2 content"}, paths)
+}
+
+func TestMultipartShouldAcceptInputFileStream(t *testing.T) {
+	BeforeEach(t)
+
+	fixtures := fstest.MapFS{
+		"fixtures/data.json": {Data: []byte(`{"a":1}`)},
+	}
+	server.SetRoute("/upload", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		_, _ = io.WriteString(w, header.Filename+"|"+header.Header.Get("Content-Type")+"|"+string(content)+"|"+r.FormValue("name"))
+	})
+	file, err := playwright.InputFileFromFS(fixtures, "fixtures/data.json")
+	require.NoError(t, err)
+	response, err := context.Request().Post(server.PREFIX+"/upload", playwright.APIRequestContextPostOptions{
+		Multipart: map[string]interface{}{
+			"name": "fixture",
+			"file": file,
+		},
+	})
+	require.NoError(t, err)
+	body, err := response.Text()
+	require.NoError(t, err)
+	require.Equal(t, `data.json|application/json|{"a":1}|fixture`, body)
+}
@@ -0,0 +1,66 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractStructsAndSlices(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`
+		<div class="product" data-id="1">
+			<h2> Widget </h2>
+			<span class="price" data-value="9.99">$9.99</span>
+			<ul><li>new</li><li>sale</li></ul>
+		</div>
+		<div class="product" data-id="2">
+			<h2>Gadget</h2>
+			<span class="price" data-value="19">$19</span>
+			<span class="stock">4</span>
+		</div>`))
+
+	type product struct {
+		ID    int      `pw:"" attr:"data-id"`
+		Name  string   `pw:"h2"`
+		Price float64  `pw:".price" attr:"data-value"`
+		Stock *int     `pw:".stock"`
+		Tags  []string `pw:"li"`
+	}
+	var products []product
+	require.NoError(t, playwright.Extract(page.Locator(".product"), &products))
+	require.Len(t, products, 2)
+	require.Equal(t, 1, products[0].ID)
+	require.Equal(t, "Widget", products[0].Name)
+	require.Equal(t, 9.99, products[0].Price)
+	require.Nil(t, products[0].Stock)
+	require.Equal(t, []string{"new", "sale"}, products[0].Tags)
+	require.Equal(t, 4, *products[1].Stock)
+	require.Empty(t, products[1].Tags)
+
+	var single product
+	require.NoError(t, playwright.Extract(page.Locator(".product").Last(), &single))
+	require.Equal(t, "Gadget", single.Name)
+
+	err := playwright.Extract(page.Locator(".product"), &single)
+	require.ErrorContains(t, err, "expected exactly one element")
+
+	var names []string
+	require.NoError(t, playwright.Extract(page.Locator("h2"), &names))
+	require.Equal(t, []string{"Widget", "Gadget"}, names)
+}
+
+func TestExtractRequiredField(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`<div class="card"><h2>Title</h2></div>`))
+	var card struct {
+		Title    string `pw:"h2"`
+		Subtitle string `pw:"h3"`
+	}
+	err := playwright.Extract(page.Locator(".card"), &card)
+	require.ErrorIs(t, err, playwright.ErrExtractRequired)
+	require.ErrorContains(t, err, "Subtitle")
+}
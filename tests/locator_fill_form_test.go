@@ -0,0 +1,78 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+const fillFormContent = `
+	<form>
+		<label>Email <input type="email" name="email"></label>
+		<input name="nickname">
+		<input placeholder="City">
+		<input data-testid="zip">
+		<label><input type="checkbox" name="terms"> Accept terms</label>
+		<select name="country"><option value="de">Germany</option><option value="fr">France</option></select>
+		<input type="radio" name="plan" value="free"><input type="radio" name="plan" value="pro">
+		<input class="dup" name="dup"><input class="dup" name="dup" type="text">
+		<input type="file" name="avatar">
+	</form>`
+
+func TestFillFormMatchesByStrategy(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(fillFormContent))
+	type signup struct {
+		Email   string
+		Nick    string `form:"nickname"`
+		City    string
+		Zip     string               `form:"testid=zip"`
+		Terms   bool                 `form:"Accept terms"`
+		Country string               `form:"country"`
+		Plan    string               `form:"plan"`
+		Avatar  playwright.InputFile `form:"avatar"`
+	}
+	result, err := playwright.FillForm(page.Locator("form"), signup{
+		Email:   "jane@example.com",
+		Nick:    "jd",
+		City:    "Berlin",
+		Zip:     "10115",
+		Terms:   true,
+		Country: "France",
+		Plan:    "pro",
+		Avatar:  playwright.InputFile{Name: "a.txt", MimeType: "text/plain", Buffer: []byte("hi")},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Filled, 8)
+	require.Empty(t, result.Unmatched)
+	require.Empty(t, result.Ambiguous)
+
+	require.NoError(t, expect.Locator(page.Locator(`[name=email]`)).ToHaveValue("jane@example.com"))
+	require.NoError(t, expect.Locator(page.Locator(`[name=nickname]`)).ToHaveValue("jd"))
+	require.NoError(t, expect.Locator(page.GetByPlaceholder("City")).ToHaveValue("Berlin"))
+	require.NoError(t, expect.Locator(page.GetByTestId("zip")).ToHaveValue("10115"))
+	require.NoError(t, expect.Locator(page.Locator(`[name=terms]`)).ToBeChecked())
+	require.NoError(t, expect.Locator(page.Locator(`[name=country]`)).ToHaveValue("fr"))
+	require.NoError(t, expect.Locator(page.Locator(`[value=pro]`)).ToBeChecked())
+	files, err := page.Locator(`[name=avatar]`).Evaluate(`e => e.files.length`, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, files)
+}
+
+func TestFillFormReportsUnmatchedAndAmbiguous(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(fillFormContent))
+	result, err := playwright.FillForm(page.Locator("form"), map[string]any{
+		"email":   "jane@example.com",
+		"dup":     "x",
+		"missing": "y",
+	})
+	require.ErrorIs(t, err, playwright.ErrFormFieldNotFound)
+	require.ErrorIs(t, err, playwright.ErrFormFieldAmbiguous)
+	require.Equal(t, []string{"email"}, result.Filled)
+	require.Equal(t, []string{"missing"}, result.Unmatched)
+	require.Equal(t, []string{"dup"}, result.Ambiguous)
+}
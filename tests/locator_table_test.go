@@ -0,0 +1,92 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTableResolvesSpans(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`
+		<table>
+			<thead>
+				<tr><th rowspan="2">Region</th><th colspan="2">Sales</th></tr>
+				<tr><th>Q1</th><th>Q2</th></tr>
+			</thead>
+			<tbody>
+				<tr><td rowspan="2">North</td><td>1</td><td>2</td></tr>
+				<tr><td>3</td><td>4</td></tr>
+				<tr><td>South</td><td colspan="2">5</td></tr>
+			</tbody>
+			<tfoot><tr><td>Total</td><td>9</td><td>11</td></tr></tfoot>
+		</table>`))
+	table, err := playwright.ExtractTable(page.Locator("table"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Region", "Q1", "Q2"}, table.Header)
+	require.Equal(t, [][]string{
+		{"North", "1", "2"},
+		{"North", "3", "4"},
+		{"South", "5", "5"},
+	}, table.Rows)
+	require.Equal(t, [][]string{{"Total", "9", "11"}}, table.Footer)
+
+	type sales struct {
+		Region string
+		Q1     int
+		Q2     int
+	}
+	rows, err := playwright.TableAs[sales](page.Locator("table"))
+	require.NoError(t, err)
+	require.Equal(t, []sales{
+		{Region: "North", Q1: 1, Q2: 2},
+		{Region: "North", Q1: 3, Q2: 4},
+		{Region: "South", Q1: 5, Q2: 5},
+	}, rows)
+}
+
+func TestExtractTableSpansStayInRowGroup(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`
+		<table>
+			<thead><tr><th rowspan="3">Region</th><th>Sales</th></tr></thead>
+			<tbody>
+				<tr><td rowspan="0">North</td><td>1</td></tr>
+				<tr><td>2</td></tr>
+			</tbody>
+			<tbody>
+				<tr><td rowspan="5">South</td><td>3</td></tr>
+			</tbody>
+			<tfoot><tr><td>Total</td><td>6</td></tr></tfoot>
+		</table>`))
+	table, err := playwright.ExtractTable(page.Locator("table"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Region", "Sales"}, table.Header)
+	require.Equal(t, [][]string{
+		{"North", "1"},
+		{"North", "2"},
+		{"South", "3"},
+	}, table.Rows)
+	require.Equal(t, [][]string{{"Total", "6"}}, table.Footer)
+}
+
+func TestExtractTableWithoutHead(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.SetContent(`
+		<table>
+			<tr><th>Name</th><th>Age</th></tr>
+			<tr><td>Ann</td><td>31</td></tr>
+		</table>
+		<div id="not-a-table"></div>`))
+	table, err := playwright.ExtractTable(page.Locator("table"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Name", "Age"}, table.Header)
+	require.Equal(t, [][]string{{"Ann", "31"}}, table.Rows)
+
+	_, err = playwright.ExtractTable(page.Locator("#not-a-table"))
+	require.ErrorContains(t, err, "not a <table>")
+}
@@ -0,0 +1,44 @@
+package playwright_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulateNetworkShouldAddLatency(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, playwright.EmulateNetwork(context, playwright.NetworkProfile{Latency: 500 * time.Millisecond}))
+
+	elapsed, err := page.Evaluate(`async () => {
+		const start = performance.now();
+		await fetch('/title.html?' + Math.random());
+		return performance.now() - start;
+	}`)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, toFloat(elapsed), 450.0)
+
+	require.NoError(t, playwright.EmulateNetwork(context, playwright.NetworkNoThrottling))
+	elapsed, err = page.Evaluate(`async () => {
+		const start = performance.now();
+		await fetch('/title.html?' + Math.random());
+		return performance.now() - start;
+	}`)
+	require.NoError(t, err)
+	require.Less(t, toFloat(elapsed), 450.0)
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
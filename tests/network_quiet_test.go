@@ -0,0 +1,86 @@
+package playwright_test
+
+import (
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectNetworkQuietWaitsForInflightRequests(t *testing.T) {
+	BeforeEach(t)
+
+	var finished atomic.Bool
+	server.SetRoute("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		finished.Store(true)
+		w.WriteHeader(http.StatusOK)
+	})
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	err = playwright.ExpectNetworkQuiet(page, func() error {
+		_, err := page.Evaluate(`() => { fetch('/slow'); }`)
+		return err
+	}, playwright.NetworkQuietOptions{
+		QuietDuration: playwright.Float(100),
+	})
+	require.NoError(t, err)
+	require.True(t, finished.Load())
+}
+
+func TestExpectNetworkQuietReportsPendingRequests(t *testing.T) {
+	BeforeEach(t)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	server.SetRoute("/poll", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	server.SetRoute("/pending", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	startRequests := func() error {
+		_, err := page.Evaluate(`() => { fetch('/poll'); fetch('/pending'); }`)
+		return err
+	}
+	err = playwright.ExpectNetworkQuiet(page, startRequests, playwright.NetworkQuietOptions{
+		QuietDuration: playwright.Float(200),
+		IgnoreURLs:    []any{regexp.MustCompile(`/poll$`)},
+		Timeout:       playwright.Float(1000),
+	})
+	require.ErrorIs(t, err, playwright.ErrTimeout)
+	require.Contains(t, err.Error(), "GET "+server.PREFIX+"/pending")
+	require.NotContains(t, err.Error(), "/poll")
+
+	err = playwright.ExpectNetworkQuiet(page, startRequests, playwright.NetworkQuietOptions{
+		MaxInflight:   playwright.Int(1),
+		QuietDuration: playwright.Float(100),
+		Timeout:       playwright.Float(1000),
+	})
+	require.ErrorIs(t, err, playwright.ErrTimeout)
+	require.Contains(t, err.Error(), "2 requests pending")
+
+	err = playwright.ExpectNetworkQuiet(page, startRequests, playwright.NetworkQuietOptions{
+		MaxInflight:   playwright.Int(2),
+		QuietDuration: playwright.Float(100),
+	})
+	require.NoError(t, err)
+}
+
+func TestWaitForNetworkQuietShouldResolveOnIdlePage(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, playwright.WaitForNetworkQuiet(page, playwright.NetworkQuietOptions{
+		QuietDuration: playwright.Float(50),
+	}))
+}
@@ -0,0 +1,69 @@
+package playwright_test
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactionShouldRedactRecordedHar(t *testing.T) {
+	harPath := filepath.Join(t.TempDir(), "log.har")
+	BeforeEach(t, playwright.BrowserNewContextOptions{
+		RecordHarPath: playwright.String(harPath),
+		ExtraHttpHeaders: map[string]string{
+			"Authorization": "Bearer top-secret",
+		},
+	})
+	var report *playwright.RedactionReport
+	require.NoError(t, playwright.SetRedaction(context.Tracing(), &playwright.RedactionOptions{
+		Headers:    []string{"authorization"},
+		OnRedacted: func(r *playwright.RedactionReport) { report = r },
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, context.Close())
+
+	data, err := os.ReadFile(harPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "top-secret")
+	require.Contains(t, string(data), "[REDACTED]")
+	require.NotNil(t, report)
+	require.Equal(t, harPath, report.Path)
+	require.NotEmpty(t, report.Redactions)
+	require.Equal(t, "header", report.Redactions[0].Kind)
+}
+
+func TestRedactionShouldMaskPasswordFillsInTrace(t *testing.T) {
+	BeforeEach(t)
+	require.NoError(t, playwright.SetRedaction(context.Tracing(), &playwright.RedactionOptions{}))
+	require.NoError(t, context.Tracing().Start())
+	require.NoError(t, page.SetContent(`<input id="user"><input id="pw" type="password">`))
+	require.NoError(t, page.Locator("#user").Fill("visible-user"))
+	require.NoError(t, page.Locator("#pw").Fill("hunter2-secret"))
+	tracePath := filepath.Join(t.TempDir(), "trace.zip")
+	require.NoError(t, context.Tracing().Stop(tracePath))
+
+	reader, err := zip.OpenReader(tracePath)
+	require.NoError(t, err)
+	defer reader.Close()
+	var events strings.Builder
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".trace") {
+			continue
+		}
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		events.Write(data)
+	}
+	require.Contains(t, events.String(), "visible-user")
+	require.NotContains(t, events.String(), "hunter2-secret")
+}
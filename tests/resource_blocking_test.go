@@ -0,0 +1,44 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockResourcesShouldBlockByResourceTypeAndHost(t *testing.T) {
+	BeforeEach(t)
+
+	blocker, err := playwright.BlockResources(context, playwright.BlockResourcesOptions{
+		ResourceTypes: []string{"image"},
+		Hosts:         []string{"ads.example"},
+	})
+	require.NoError(t, err)
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(`async () => {
+		const loadImage = src => new Promise(resolve => {
+			const img = new Image();
+			img.onload = () => resolve('loaded');
+			img.onerror = () => resolve('blocked');
+			img.src = src;
+		});
+		const ad = await fetch('http://tracker.ads.example/pixel').then(() => 'loaded', () => 'blocked');
+		const style = await fetch('/one-style.css').then(() => 'loaded', () => 'blocked');
+		return [await loadImage('/pptr.png'), ad, style];
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, []any{"blocked", "blocked", "loaded"}, result)
+
+	blocked := blocker.Blocked()
+	require.Equal(t, 2, blocked.Total)
+	require.Equal(t, 1, blocked.ByResourceType["image"])
+	require.Equal(t, 1, blocked.ByHost["tracker.ads.example"])
+
+	require.NoError(t, blocker.Stop())
+	result, err = page.Evaluate(`() => fetch('/pptr.png').then(() => 'loaded', () => 'blocked')`)
+	require.NoError(t, err)
+	require.Equal(t, "loaded", result)
+}
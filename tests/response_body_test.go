@@ -0,0 +1,55 @@
+package playwright_test
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveBodyAsShouldSaveAPIResponseBody(t *testing.T) {
+	BeforeEach(t)
+
+	payload := bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+	server.SetRoute("/large", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	})
+	response, err := page.Request().Get(server.PREFIX + "/large")
+	require.NoError(t, err)
+	defer response.Dispose()
+
+	path := filepath.Join(t.TempDir(), "large.bin")
+	require.NoError(t, playwright.SaveBodyAs(response, path))
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, payload, saved)
+}
+
+func TestSaveBodyAsShouldFailForDisposedAPIResponse(t *testing.T) {
+	BeforeEach(t)
+
+	response, err := page.Request().Get(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, response.Dispose())
+	path := filepath.Join(t.TempDir(), "empty.html")
+	require.ErrorContains(t, playwright.SaveBodyAs(response, path), "response has been disposed")
+	require.NoFileExists(t, path)
+}
+
+func TestSaveBodyAsShouldSaveResponseBody(t *testing.T) {
+	BeforeEach(t)
+
+	response, err := page.Goto(server.PREFIX + "/empty.html")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "page", "empty.html")
+	require.NoError(t, playwright.SaveBodyAs(response, path))
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	expected, err := os.ReadFile(Asset("empty.html"))
+	require.NoError(t, err)
+	require.Equal(t, expected, saved)
+}
@@ -0,0 +1,31 @@
+package playwright_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteFSShouldServeSinglePageApp(t *testing.T) {
+	BeforeEach(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<script src="/app.js"></script><div id="route"></div>`)},
+		"app.js":     {Data: []byte(`document.addEventListener('DOMContentLoaded', () => document.getElementById('route').textContent = location.pathname)`)},
+	}
+	require.NoError(t, playwright.RouteFS(context, "https://app.local", fsys))
+
+	_, err := page.Goto("https://app.local/users/42")
+	require.NoError(t, err)
+	require.NoError(t, expect.Locator(page.Locator("#route")).ToHaveText("/users/42"))
+
+	status, err := page.Evaluate(`() => fetch('/missing.css').then(r => r.status)`)
+	require.NoError(t, err)
+	require.Equal(t, 404, status)
+
+	text, err := page.Evaluate(`() => fetch('/app.js', { headers: { Range: 'bytes=0-7' } }).then(r => r.text())`)
+	require.NoError(t, err)
+	require.Equal(t, "document", text)
+}
@@ -0,0 +1,118 @@
+package playwright_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+const graphQLFetchScript = `async body => {
+	const response = await fetch('/graphql', {
+		method: 'POST',
+		headers: { 'content-type': 'application/json' },
+		body: JSON.stringify(body),
+	});
+	return response.json();
+}`
+
+func TestRouteGraphQLShouldDispatchByOperation(t *testing.T) {
+	BeforeEach(t)
+
+	server.SetRoute("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":{"server":true}}`)
+	})
+	graphql, err := playwright.RouteGraphQL(page, "**/graphql")
+	require.NoError(t, err)
+	graphql.Handle("GetUser", func(request *playwright.GraphQLRequest) (*playwright.GraphQLResponse, error) {
+		return &playwright.GraphQLResponse{Data: map[string]any{"user": map[string]any{"id": request.Variables["id"]}}}, nil
+	})
+	graphql.Handle("DeleteUser", func(request *playwright.GraphQLRequest) (*playwright.GraphQLResponse, error) {
+		return nil, errors.New("not allowed")
+	})
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(graphQLFetchScript, map[string]any{
+		"query":     "query GetUser($id: ID!) { user(id: $id) { id } }",
+		"variables": map[string]any{"id": "42"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"data": map[string]any{"user": map[string]any{"id": "42"}}}, result)
+
+	result, err = page.Evaluate(graphQLFetchScript, map[string]any{
+		"query": "mutation DeleteUser { deleteUser(id: 1) }",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"errors": []any{map[string]any{"message": "not allowed"}}}, result)
+
+	result, err = page.Evaluate(graphQLFetchScript, map[string]any{
+		"query": "query ListUsers { users { id } }",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"data": map[string]any{"server": true}}, result)
+}
+
+func TestRouteGraphQLShouldAnswerBatches(t *testing.T) {
+	BeforeEach(t)
+
+	server.SetRoute("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `[{"data":{"fromServer":"ListUsers"}}]`)
+	})
+	graphql, err := playwright.RouteGraphQL(context, "**/graphql")
+	require.NoError(t, err)
+	graphql.Handle("GetUser", func(request *playwright.GraphQLRequest) (*playwright.GraphQLResponse, error) {
+		return &playwright.GraphQLResponse{Data: map[string]any{"mocked": request.OperationName}}, nil
+	})
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(graphQLFetchScript, []any{
+		map[string]any{"query": "query GetUser { user { id } }"},
+		map[string]any{"query": "query ListUsers { users { id } }"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []any{
+		map[string]any{"data": map[string]any{"mocked": "GetUser"}},
+		map[string]any{"data": map[string]any{"fromServer": "ListUsers"}},
+	}, result)
+}
+
+func TestRouteGraphQLShouldFallBack(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.Route("**/graphql", func(route playwright.Route) {
+		require.NoError(t, route.Fulfill(playwright.RouteFulfillOptions{
+			ContentType: playwright.String("application/json"),
+			Body:        `{"data":{"fallback":true}}`,
+		}))
+	}))
+	graphql, err := playwright.RouteGraphQL(page, "**/graphql")
+	require.NoError(t, err)
+	graphql.Handle("*", func(request *playwright.GraphQLRequest) (*playwright.GraphQLResponse, error) {
+		if request.OperationName == "Skip" {
+			return nil, playwright.ErrGraphQLFallback
+		}
+		return &playwright.GraphQLResponse{Data: map[string]any{"handled": true}}, nil
+	})
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(graphQLFetchScript, map[string]any{"query": "query Skip { a }"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"data": map[string]any{"fallback": true}}, result)
+
+	result, err = page.Evaluate(graphQLFetchScript, map[string]any{"query": "query Other { a }"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"data": map[string]any{"handled": true}}, result)
+
+	require.NoError(t, graphql.Unroute())
+	result, err = page.Evaluate(graphQLFetchScript, map[string]any{"query": "query Other { a }"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"data": map[string]any{"fallback": true}}, result)
+}
@@ -0,0 +1,53 @@
+package playwright_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteToHandlerShouldServeFromHandler(t *testing.T) {
+	BeforeEach(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Seen-Header", r.Header.Get("X-Custom"))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"path": r.URL.Path, "body": string(body)})
+	})
+	require.NoError(t, playwright.RouteToHandler(page, "**/api/**", mux))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+
+	result, err := page.Evaluate(`async () => {
+		const response = await fetch('/api/echo', { method: 'POST', body: 'hello', headers: { 'X-Custom': 'yes' } });
+		return { status: response.status, seen: response.headers.get('x-seen-header'), json: await response.json() };
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"status": 201,
+		"seen":   "yes",
+		"json":   map[string]any{"path": "/api/echo", "body": "hello"},
+	}, result)
+
+	result, err = page.Evaluate(`() => fetch('/api/missing').then(r => r.status)`)
+	require.NoError(t, err)
+	require.Equal(t, 404, result)
+}
+
+func TestRouteToHandlerShouldWorkOnContext(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, playwright.RouteToHandler(context, "**/*", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "<title>from handler</title>")
+	})))
+	_, err := page.Goto("http://fake.playwright/")
+	require.NoError(t, err)
+	require.NoError(t, expect.Page(page).ToHaveTitle("from handler"))
+}
@@ -0,0 +1,68 @@
+package playwright_test
+
+import (
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/mxschmitt/playwright-go/har"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteHARShouldMatchMethodAndFollowRedirects(t *testing.T) {
+	BeforeEach(t)
+
+	archive, err := har.ReadFile(Asset("har-fulfill.har"))
+	require.NoError(t, err)
+	require.NoError(t, playwright.RouteHAR(page, archive))
+	_, err = page.Goto("http://no.playwright/")
+	require.NoError(t, err)
+	// HAR contains a redirect for the script that should be followed automatically.
+	data, err := page.Evaluate(`window.value`)
+	require.NoError(t, err)
+	require.Equal(t, "foo", data)
+	// HAR contains a POST for the css file that should not be used.
+	require.NoError(t, expect.Locator(page.Locator("body")).ToHaveCSS("background-color", "rgb(255, 0, 0)"))
+}
+
+func TestRouteHARShouldIgnoreQueryParams(t *testing.T) {
+	BeforeEach(t)
+
+	archive, err := har.ReadFile(Asset("har-fulfill.har"))
+	require.NoError(t, err)
+	require.NoError(t, playwright.RouteHAR(context, archive, playwright.RouteHAROptions{
+		Matcher: playwright.MatchHARAll(playwright.MatchHARMethod(), playwright.MatchHARURL("cache-buster")),
+	}))
+	_, err = page.Goto("http://no.playwright/?cache-buster=123")
+	require.NoError(t, err)
+	require.NoError(t, expect.Locator(page.Locator("div")).ToHaveText("hello"))
+}
+
+func TestRouteHARShouldServeSequentialMatches(t *testing.T) {
+	BeforeEach(t)
+
+	archive := har.New(har.Creator{Name: "test"})
+	for _, body := range []string{"first", "second"} {
+		archive.Log.Entries = append(archive.Log.Entries, &har.Entry{
+			Request: har.Request{Method: "GET", URL: server.PREFIX + "/poll?ts=1"},
+			Response: har.Response{
+				Status:  200,
+				Headers: []har.NameValue{{Name: "Content-Type", Value: "text/plain"}},
+				Content: har.Content{MimeType: "text/plain", Text: body},
+			},
+		})
+	}
+	require.NoError(t, playwright.RouteHAR(page, archive, playwright.RouteHAROptions{
+		URL:        "**/poll*",
+		Matcher:    playwright.MatchHARURL("ts"),
+		Sequential: playwright.Bool(true),
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	var bodies []any
+	for _, ts := range []string{"7", "8", "9"} {
+		body, err := page.Evaluate(`ts => fetch('/poll?ts=' + ts).then(r => r.text())`, ts)
+		require.NoError(t, err)
+		bodies = append(bodies, body)
+	}
+	require.Equal(t, []any{"first", "second", "second"}, bodies)
+}
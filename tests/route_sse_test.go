@@ -0,0 +1,88 @@
+package playwright_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+const sseListenScript = `() => {
+	window.events = [];
+	window.source = new EventSource('/events');
+	window.source.onmessage = event => window.events.push('message:' + event.data + ':' + event.lastEventId);
+	window.source.addEventListener('update', event => window.events.push('update:' + event.data));
+}`
+
+func TestRouteSSEShouldPushEvents(t *testing.T) {
+	BeforeEach(t)
+
+	sse, err := playwright.RouteSSE(page, "**/events")
+	require.NoError(t, err)
+	defer sse.Close() //nolint:errcheck
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	_, err = page.Evaluate(sseListenScript)
+	require.NoError(t, err)
+
+	conn, err := sse.WaitForConnection(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, server.PREFIX+"/events", conn.Request.URL())
+	require.NoError(t, conn.Send(playwright.SSEEvent{Data: "hello", ID: playwright.String("1")}))
+	require.NoError(t, conn.Send(playwright.SSEEvent{Event: "update", Data: "42"}))
+	_, err = page.WaitForFunction(`() => window.events.length === 2`, nil)
+	require.NoError(t, err)
+	events, err := page.Evaluate(`window.events`)
+	require.NoError(t, err)
+	require.Equal(t, []any{"message:hello:1", "update:42"}, events)
+}
+
+func TestRouteSSEShouldReconnect(t *testing.T) {
+	BeforeEach(t)
+
+	sse, err := playwright.RouteSSE(page, "**/events")
+	require.NoError(t, err)
+	defer sse.Close() //nolint:errcheck
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	_, err = page.Evaluate(sseListenScript)
+	require.NoError(t, err)
+
+	conn, err := sse.WaitForConnection(5 * time.Second)
+	require.NoError(t, err)
+	require.NoError(t, conn.Send(playwright.SSEEvent{Data: "first", ID: playwright.String("7"), Retry: 10 * time.Millisecond}))
+	conn.Close()
+
+	reconnected, err := sse.WaitForConnection(5 * time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "7", reconnected.LastEventID)
+	sse.RejectReconnects()
+	reconnected.Close()
+	_, err = page.WaitForFunction(`() => window.source.readyState === EventSource.CLOSED`, nil)
+	require.NoError(t, err)
+}
+
+func TestRouteSSEShouldScheduleWithClock(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, page.Clock().Install())
+	sse, err := playwright.RouteSSE(page, "**/events")
+	require.NoError(t, err)
+	defer sse.Close() //nolint:errcheck
+	_, err = page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	_, err = page.Evaluate(sseListenScript)
+	require.NoError(t, err)
+	_, err = sse.WaitForConnection(5 * time.Second)
+	require.NoError(t, err)
+
+	require.NoError(t, sse.Schedule(page, time.Minute, playwright.SSEEvent{Data: "later"}))
+	require.NoError(t, page.Clock().RunFor(30_000))
+	events, err := page.Evaluate(`window.events`)
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.NoError(t, page.Clock().RunFor(30_000))
+	_, err = page.WaitForFunction(`() => window.events.length === 1`, nil)
+	require.NoError(t, err)
+}
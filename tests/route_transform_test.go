@@ -0,0 +1,59 @@
+package playwright_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteTransformShouldRewriteJSON(t *testing.T) {
+	BeforeEach(t)
+
+	server.SetRoute("/api/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		_, _ = io.WriteString(gw, `{"items":["a","b"]}`)
+		_ = gw.Close()
+	})
+	require.NoError(t, playwright.RouteTransform(page, "**/api/items", func(ctx *playwright.TransformContext) error {
+		var payload map[string][]string
+		if err := ctx.JSON(&payload); err != nil {
+			return err
+		}
+		payload["items"] = append(payload["items"], "injected")
+		ctx.Status = 203
+		ctx.Headers["x-transformed"] = "1"
+		return ctx.SetJSON(payload)
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	result, err := page.Evaluate(`async () => {
+		const response = await fetch('/api/items');
+		return { status: response.status, header: response.headers.get('x-transformed'), json: await response.json() };
+	}`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"status": 203,
+		"header": "1",
+		"json":   map[string]any{"items": []any{"a", "b", "injected"}},
+	}, result)
+}
+
+func TestRouteTransformShouldInjectHTML(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, playwright.RouteTransform(context, "**/empty.html", func(ctx *playwright.TransformContext) error {
+		ctx.InjectIntoHead(`<title>Injected</title>`)
+		ctx.InjectIntoBody(`<div id="banner">test build</div>`)
+		return nil
+	}))
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	require.NoError(t, expect.Page(page).ToHaveTitle("Injected"))
+	require.NoError(t, expect.Locator(page.Locator("#banner")).ToHaveText("test build"))
+}
@@ -0,0 +1,79 @@
+package playwright_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+// paintUntil changes the background of page until done returns true.
+func paintUntil(t *testing.T, page playwright.Page, done func() bool) {
+	t.Helper()
+	colors := []string{"red", "green", "blue", "yellow", "purple"}
+	deadline := time.Now().Add(10 * time.Second)
+	for i := 0; time.Now().Before(deadline) && !done(); i++ {
+		_, err := page.Evaluate(fmt.Sprintf("() => { document.body.style.backgroundColor = '%s'; }", colors[i%len(colors)]))
+		require.NoError(t, err)
+		_, err = page.Evaluate("() => new Promise(f => requestAnimationFrame(() => requestAnimationFrame(f)))")
+		require.NoError(t, err)
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestScreencastFramesShouldDeliverImages(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	frames, err := playwright.StartScreencastFrames(page, playwright.ScreencastFramesOptions{
+		Overflow: playwright.FrameOverflowDropOldest,
+		Size:     &playwright.Size{Width: 320, Height: 240},
+	})
+	require.NoError(t, err)
+
+	var received []playwright.ScreencastFrame
+	paintUntil(t, page, func() bool {
+		for {
+			select {
+			case frame := <-frames.Frames():
+				received = append(received, frame)
+			default:
+				return len(received) >= 2
+			}
+		}
+	})
+	require.NoError(t, frames.Stop())
+	for range frames.Frames() {
+	}
+	require.GreaterOrEqual(t, len(received), 2)
+	for _, frame := range received {
+		require.LessOrEqual(t, frame.Image.Bounds().Dx(), 320)
+		require.Positive(t, frame.Image.Bounds().Dx())
+		require.WithinDuration(t, time.Now(), frame.Timestamp, time.Minute)
+	}
+}
+
+func TestRecordScreencastAnimationShouldWriteGIF(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.EMPTY_PAGE)
+	require.NoError(t, err)
+	var clip bytes.Buffer
+	recording, err := playwright.RecordScreencastAnimation(page, &clip, playwright.ScreencastAnimationOptions{
+		Size: &playwright.Size{Width: 160, Height: 120},
+	})
+	require.NoError(t, err)
+	start := time.Now()
+	paintUntil(t, page, func() bool { return time.Since(start) > time.Second })
+	require.NoError(t, recording.Stop())
+
+	decoded, err := gif.DecodeAll(&clip)
+	require.NoError(t, err)
+	require.NotEmpty(t, decoded.Image)
+	require.LessOrEqual(t, decoded.Config.Width, 160)
+}
@@ -0,0 +1,30 @@
+package playwright_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreencastViewerShouldListPages(t *testing.T) {
+	BeforeEach(t)
+
+	_, err := page.Goto(server.PREFIX + "/title.html")
+	require.NoError(t, err)
+	viewer := playwright.NewScreencastViewer(browser)
+	defer viewer.Close()
+	viewerServer := httptest.NewServer(viewer)
+	defer viewerServer.Close()
+
+	resp, err := http.Get(viewerServer.URL + "/")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Contains(t, string(body), server.PREFIX+"/title.html")
+	require.Contains(t, string(body), `href="pages/`)
+}
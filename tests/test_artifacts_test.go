@@ -0,0 +1,46 @@
+package playwright_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+// failedT reports the test as failed to exercise the retain-on-failure mode.
+type failedT struct {
+	*testing.T
+}
+
+func (failedT) Failed() bool { return true }
+
+func TestNewTestContextShouldKeepArtifactsOfFailedTest(t *testing.T) {
+	dir := t.TempDir()
+	t.Run("sub", func(t *testing.T) {
+		context, err := playwright.NewTestContext(failedT{t}, browser, playwright.TestContextOptions{Dir: dir})
+		require.NoError(t, err)
+		page, err := context.NewPage()
+		require.NoError(t, err)
+		require.NoError(t, page.SetContent("<h1>Hello</h1>"))
+	})
+	testDir := filepath.Join(dir, "TestNewTestContextShouldKeepArtifactsOfFailedTest-sub")
+	require.FileExists(t, filepath.Join(testDir, "trace.zip"))
+	require.FileExists(t, filepath.Join(testDir, "video.webm"))
+	require.FileExists(t, filepath.Join(testDir, "screenshot.png"))
+}
+
+func TestNewTestContextShouldDiscardArtifactsOfPassedTest(t *testing.T) {
+	dir := t.TempDir()
+	t.Run("sub", func(t *testing.T) {
+		context, err := playwright.NewTestContext(t, browser, playwright.TestContextOptions{Dir: dir})
+		require.NoError(t, err)
+		page, err := context.NewPage()
+		require.NoError(t, err)
+		require.NoError(t, page.SetContent("<h1>Hello</h1>"))
+	})
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
@@ -0,0 +1,76 @@
+package playwright_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mxschmitt/playwright-go"
+	"github.com/mxschmitt/playwright-go/trace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceReaderShouldReadRecordedTrace(t *testing.T) {
+	BeforeEach(t)
+
+	require.NoError(t, context.Tracing().Start(playwright.TracingStartOptions{
+		Screenshots: playwright.Bool(true),
+		Snapshots:   playwright.Bool(true),
+	}))
+	_, err := page.Goto(server.PREFIX + "/one-style.html")
+	require.NoError(t, err)
+	require.NoError(t, page.SetContent(`<button onclick="console.log('clicked')">Click</button>`))
+	require.NoError(t, page.Locator("button").Click())
+	err = page.Locator("#missing").Click(playwright.LocatorClickOptions{Timeout: playwright.Float(100)})
+	require.Error(t, err)
+	tracePath := filepath.Join(t.TempDir(), "trace.zip")
+	require.NoError(t, context.Tracing().Stop(tracePath))
+
+	tr, err := trace.Open(tracePath)
+	require.NoError(t, err)
+	defer tr.Close() //nolint:errcheck
+
+	require.NotEmpty(t, tr.Contexts)
+	var names []string
+	var failed *trace.Action
+	for _, action := range tr.Actions {
+		names = append(names, action.Name())
+		if action.Error != nil {
+			failed = action
+		}
+	}
+	require.Contains(t, names, "Frame.goto")
+	require.Contains(t, names, "Frame.click")
+	require.NotNil(t, failed)
+	require.Contains(t, failed.Error.Message, "Timeout")
+	require.NotEmpty(t, failed.Log)
+
+	var messages []string
+	for _, message := range tr.Console {
+		messages = append(messages, message.Text)
+	}
+	require.Contains(t, messages, "clicked")
+
+	var styleFound bool
+	for _, entry := range tr.Network {
+		if entry.Request.URL == server.PREFIX+"/one-style.css" {
+			styleFound = true
+			body, err := tr.Body(entry)
+			require.NoError(t, err)
+			require.Contains(t, string(body), "pink")
+		}
+	}
+	require.True(t, styleFound)
+
+	for _, action := range tr.Actions {
+		if action.Method == "click" && action.Error == nil && action.Before != nil {
+			html, err := tr.HTML(action.Before)
+			require.NoError(t, err)
+			require.Contains(t, html, "Click</BUTTON>")
+		}
+	}
+	if len(tr.Screenshots) > 0 {
+		image, err := tr.Image(tr.Screenshots[0])
+		require.NoError(t, err)
+		require.NotEmpty(t, image)
+	}
+}
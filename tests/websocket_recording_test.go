@@ -0,0 +1,56 @@
+package playwright_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/mxschmitt/playwright-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketRecordingShouldRecordAndReplay(t *testing.T) {
+	BeforeEach(t)
+
+	path := filepath.Join(t.TempDir(), "ws.json")
+	recorder, err := playwright.RecordWebSockets(page, path)
+	require.NoError(t, err)
+
+	server.SendOnWebSocketConnection(websocket.MessageText, []byte("welcome"))
+	server.OnceWebSocketMessage(func(c *websocket.Conn, r *http.Request, msgType websocket.MessageType, msg []byte) {
+		_ = c.Write(r.Context(), websocket.MessageText, append([]byte("echo:"), msg...))
+	})
+	setupWS(t, page, server.PORT, "blob")
+	_, err = page.Evaluate(`async () => {
+		await window.wsOpened;
+		window.ws.send('hello');
+	}`)
+	require.NoError(t, err)
+	expected := []any{
+		"open",
+		"message: data=welcome origin=ws://localhost:" + server.PORT + " lastEventId=",
+		"message: data=echo:hello origin=ws://localhost:" + server.PORT + " lastEventId=",
+	}
+	assertSlicesEqual(t, expected, func() (any, error) {
+		return page.Evaluate(`window.log`)
+	})
+	require.NoError(t, recorder.Stop())
+
+	recording, err := playwright.ReadWebSocketRecording(path)
+	require.NoError(t, err)
+	require.Len(t, recording.Sessions, 1)
+	require.Equal(t, "ws://localhost:"+server.PORT+"/ws", recording.Sessions[0].URL)
+	require.Len(t, recording.Sessions[0].Frames, 3)
+
+	require.NoError(t, playwright.RouteWebSocketReplay(page, recording))
+	setupWS(t, page, server.PORT, "blob")
+	assertSlicesEqual(t, expected[:2], func() (any, error) {
+		return page.Evaluate(`window.log`)
+	})
+	_, err = page.Evaluate(`() => window.ws.send('hello')`)
+	require.NoError(t, err)
+	assertSlicesEqual(t, expected, func() (any, error) {
+		return page.Evaluate(`window.log`)
+	})
+}
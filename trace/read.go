@@ -0,0 +1,416 @@
+package trace
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mxschmitt/playwright-go/har"
+)
+
+// ErrResourceNotFound is returned by [Trace.Resource] when the archive doesn't
+// hold the requested resource.
+var ErrResourceNotFound = errors.New("trace: resource not found")
+
+// maxEventSize is the size limit of a single event, snapshots of large pages
+// can take several megabytes.
+const maxEventSize = 256 << 20
+
+// Open reads the trace archive at path. Resources are read on demand, so the
+// trace must be closed when no longer needed.
+func Open(path string) (*Trace, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := read(&archive.Reader)
+	if err != nil {
+		archive.Close() //nolint:errcheck
+		return nil, err
+	}
+	t.closer = archive.Close
+	return t, nil
+}
+
+// Read reads a trace archive of the given size from r, which must stay
+// readable for [Trace.Resource].
+func Read(r io.ReaderAt, size int64) (*Trace, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return read(archive)
+}
+
+// Close closes the archive opened by [Open].
+func (t *Trace) Close() error {
+	if t.closer == nil {
+		return nil
+	}
+	return t.closer()
+}
+
+// Resource returns the content of an attached resource, e.g. the image of a
+// [Screenshot].
+func (t *Trace) Resource(sha1 string) ([]byte, error) {
+	file, ok := t.files["resources/"+sha1]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, sha1)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() //nolint:errcheck
+	return io.ReadAll(rc)
+}
+
+// Image returns the JPEG image of screenshot.
+func (t *Trace) Image(screenshot *Screenshot) ([]byte, error) {
+	return t.Resource(screenshot.SHA1)
+}
+
+// Body returns the response body of a network entry.
+func (t *Trace) Body(entry *har.Entry) ([]byte, error) {
+	content := &entry.Response.Content
+	if content.SHA1 != "" {
+		return t.Resource(content.SHA1)
+	}
+	if content.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(content.Text)
+	}
+	return []byte(content.Text), nil
+}
+
+// RequestBody returns the posted body of a network entry, nil if there is none.
+func (t *Trace) RequestBody(entry *har.Entry) ([]byte, error) {
+	postData := entry.Request.PostData
+	if postData == nil {
+		return nil, nil
+	}
+	if postData.SHA1 != "" {
+		return t.Resource(postData.SHA1)
+	}
+	return []byte(postData.Text), nil
+}
+
+func read(archive *zip.Reader) (*Trace, error) {
+	t := &Trace{
+		files:  make(map[string]*zip.File),
+		frames: make(map[[2]string][]*Snapshot),
+	}
+	// Every recorded context or chunk is stored as "<ordinal>.trace" with
+	// its network events in "<ordinal>.network".
+	var ordinals []string
+	for _, file := range archive.File {
+		t.files[file.Name] = file
+		if strings.HasSuffix(file.Name, ".trace") {
+			ordinals = append(ordinals, strings.TrimSuffix(file.Name, ".trace"))
+		}
+	}
+	if len(ordinals) == 0 {
+		return nil, errors.New("trace: no .trace file in archive")
+	}
+	sort.Strings(ordinals)
+	for _, ordinal := range ordinals {
+		p := &parser{trace: t, context: ordinal, actions: make(map[string]*Action)}
+		for _, name := range []string{ordinal + ".trace", ordinal + ".network"} {
+			file, ok := t.files[name]
+			if !ok {
+				continue
+			}
+			if err := p.parseFile(file); err != nil {
+				return nil, fmt.Errorf("trace: could not parse %s: %w", name, err)
+			}
+		}
+	}
+	t.link()
+	return t, nil
+}
+
+// rawEvent holds the fields of all event types of the trace format.
+type rawEvent struct {
+	Type string `json:"type"`
+
+	// context-options
+	Version       int            `json:"version"`
+	BrowserName   string         `json:"browserName"`
+	Platform      string         `json:"platform"`
+	SDKLanguage   string         `json:"sdkLanguage"`
+	WallTime      float64        `json:"wallTime"`
+	MonotonicTime float64        `json:"monotonicTime"`
+	Options       map[string]any `json:"options"`
+
+	// before, input, after and log
+	CallID         string          `json:"callId"`
+	ParentID       string          `json:"parentId"`
+	StepID         string          `json:"stepId"`
+	Class          string          `json:"class"`
+	Method         string          `json:"method"`
+	Title          string          `json:"title"`
+	Params         map[string]any  `json:"params"`
+	Result         any             `json:"result"`
+	Error          json.RawMessage `json:"error"`
+	StartTime      float64         `json:"startTime"`
+	EndTime        float64         `json:"endTime"`
+	Point          *Point          `json:"point"`
+	Stack          []StackFrame    `json:"stack"`
+	BeforeSnapshot string          `json:"beforeSnapshot"`
+	InputSnapshot  string          `json:"inputSnapshot"`
+	AfterSnapshot  string          `json:"afterSnapshot"`
+	Message        string          `json:"message"`
+
+	// console, event and screencast-frame
+	Time        float64         `json:"time"`
+	PageID      string          `json:"pageId"`
+	MessageType string          `json:"messageType"`
+	Text        string          `json:"text"`
+	Args        []ConsoleArg    `json:"args"`
+	Location    ConsoleLocation `json:"location"`
+	SHA1        string          `json:"sha1"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Timestamp   float64         `json:"timestamp"`
+
+	// frame-snapshot and resource-snapshot
+	Snapshot json.RawMessage `json:"snapshot"`
+}
+
+type rawSnapshot struct {
+	CallID       string          `json:"callId"`
+	SnapshotName string          `json:"snapshotName"`
+	PageID       string          `json:"pageId"`
+	FrameID      string          `json:"frameId"`
+	FrameURL     string          `json:"frameUrl"`
+	Doctype      string          `json:"doctype"`
+	HTML         json.RawMessage `json:"html"`
+	Viewport     Size            `json:"viewport"`
+	Timestamp    float64         `json:"timestamp"`
+	IsMainFrame  bool            `json:"isMainFrame"`
+}
+
+// parser reads the events of one context, whose times are relative to the
+// monotonic clock of its context-options event.
+type parser struct {
+	trace    *Trace
+	context  string
+	actions  map[string]*Action
+	wallTime time.Time
+	baseTime float64
+}
+
+func (p *parser) parseFile(file *zip.File) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close() //nolint:errcheck
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var event rawEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			return err
+		}
+		if err := p.parseEvent(&event); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// time converts a monotonic time in milliseconds to wall time.
+func (p *parser) time(monotonic float64) time.Time {
+	return p.wallTime.Add(time.Duration((monotonic - p.baseTime) * float64(time.Millisecond)))
+}
+
+func (p *parser) parseEvent(event *rawEvent) error {
+	t := p.trace
+	switch event.Type {
+	case "context-options":
+		p.wallTime = millisToTime(event.WallTime)
+		p.baseTime = event.MonotonicTime
+		t.Contexts = append(t.Contexts, &ContextOptions{
+			Version:     event.Version,
+			BrowserName: event.BrowserName,
+			Platform:    event.Platform,
+			SDKLanguage: event.SDKLanguage,
+			Title:       event.Title,
+			WallTime:    p.wallTime,
+			Options:     event.Options,
+		})
+	case "before":
+		action := &Action{
+			CallID:         event.CallID,
+			ParentID:       event.ParentID,
+			StepID:         event.StepID,
+			PageID:         event.PageID,
+			Class:          event.Class,
+			Method:         event.Method,
+			Title:          event.Title,
+			Params:         event.Params,
+			Stack:          event.Stack,
+			StartTime:      p.time(event.StartTime),
+			context:        p.context,
+			beforeSnapshot: event.BeforeSnapshot,
+		}
+		p.actions[event.CallID] = action
+		t.Actions = append(t.Actions, action)
+	case "input":
+		if action, ok := p.actions[event.CallID]; ok {
+			action.inputSnapshot = event.InputSnapshot
+			if event.Point != nil {
+				action.Point = event.Point
+			}
+		}
+	case "after":
+		action, ok := p.actions[event.CallID]
+		if !ok {
+			return nil
+		}
+		action.EndTime = p.time(event.EndTime)
+		action.Result = event.Result
+		action.afterSnapshot = event.AfterSnapshot
+		if event.Point != nil {
+			action.Point = event.Point
+		}
+		actionError, err := parseError(event.Error)
+		if err != nil {
+			return err
+		}
+		action.Error = actionError
+	case "log":
+		if action, ok := p.actions[event.CallID]; ok {
+			action.Log = append(action.Log, &LogEntry{Time: p.time(event.Time), Message: event.Message})
+		}
+	case "console":
+		t.Console = append(t.Console, &ConsoleMessage{
+			Time:     p.time(event.Time),
+			PageID:   event.PageID,
+			Type:     event.MessageType,
+			Text:     event.Text,
+			Args:     event.Args,
+			Location: event.Location,
+		})
+	case "event":
+		t.Events = append(t.Events, &Event{
+			Time:   p.time(event.Time),
+			PageID: event.PageID,
+			Class:  event.Class,
+			Method: event.Method,
+			Params: event.Params,
+		})
+	case "screencast-frame":
+		t.Screenshots = append(t.Screenshots, &Screenshot{
+			Time:   p.time(event.Timestamp),
+			PageID: event.PageID,
+			Width:  event.Width,
+			Height: event.Height,
+			SHA1:   event.SHA1,
+		})
+	case "frame-snapshot":
+		var raw rawSnapshot
+		if err := json.Unmarshal(event.Snapshot, &raw); err != nil {
+			return err
+		}
+		frame := [2]string{p.context, raw.FrameID}
+		snapshot := &Snapshot{
+			Time:        p.time(raw.Timestamp),
+			CallID:      raw.CallID,
+			Name:        raw.SnapshotName,
+			PageID:      raw.PageID,
+			FrameID:     raw.FrameID,
+			FrameURL:    raw.FrameURL,
+			IsMainFrame: raw.IsMainFrame,
+			Viewport:    raw.Viewport,
+			Doctype:     raw.Doctype,
+			html:        raw.HTML,
+			context:     p.context,
+			index:       len(t.frames[frame]),
+		}
+		t.frames[frame] = append(t.frames[frame], snapshot)
+		t.Snapshots = append(t.Snapshots, snapshot)
+	case "resource-snapshot":
+		entry := &har.Entry{}
+		if err := json.Unmarshal(event.Snapshot, entry); err != nil {
+			return err
+		}
+		t.Network = append(t.Network, entry)
+	}
+	return nil
+}
+
+// parseError reads the error of an after event, which older versions wrapped
+// in another object.
+func parseError(data json.RawMessage) (*Error, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var raw struct {
+		Error
+		Wrapped *Error `json:"error"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Message == "" && raw.Wrapped != nil {
+		return raw.Wrapped, nil
+	}
+	return &raw.Error, nil
+}
+
+// link resolves the snapshots of the actions and orders the events by time.
+func (t *Trace) link() {
+	mainFrames := make(map[[3]string]*Snapshot)
+	for _, snapshot := range t.Snapshots {
+		if snapshot.IsMainFrame {
+			mainFrames[[3]string{snapshot.context, snapshot.PageID, snapshot.Name}] = snapshot
+		}
+	}
+	lookup := func(action *Action, name string) *Snapshot {
+		if name == "" {
+			return nil
+		}
+		return mainFrames[[3]string{action.context, action.PageID, name}]
+	}
+	for _, action := range t.Actions {
+		action.Before = lookup(action, action.beforeSnapshot)
+		action.Input = lookup(action, action.inputSnapshot)
+		action.After = lookup(action, action.afterSnapshot)
+	}
+	sort.SliceStable(t.Actions, func(i, j int) bool {
+		return t.Actions[i].StartTime.Before(t.Actions[j].StartTime)
+	})
+	sort.SliceStable(t.Console, func(i, j int) bool {
+		return t.Console[i].Time.Before(t.Console[j].Time)
+	})
+	sort.SliceStable(t.Network, func(i, j int) bool {
+		return t.Network[i].StartedDateTime.Before(t.Network[j].StartedDateTime)
+	})
+	sort.SliceStable(t.Screenshots, func(i, j int) bool {
+		return t.Screenshots[i].Time.Before(t.Screenshots[j].Time)
+	})
+	sort.SliceStable(t.Snapshots, func(i, j int) bool {
+		return t.Snapshots[i].Time.Before(t.Snapshots[j].Time)
+	})
+	sort.SliceStable(t.Events, func(i, j int) bool {
+		return t.Events[i].Time.Before(t.Events[j].Time)
+	})
+}
+
+func millisToTime(millis float64) time.Time {
+	seconds, fraction := math.Modf(millis / 1000)
+	return time.Unix(int64(seconds), int64(fraction*1e9))
+}
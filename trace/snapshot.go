@@ -0,0 +1,191 @@
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// autoClosing are the elements without an end tag.
+var autoClosing = map[string]bool{
+	"AREA": true, "BASE": true, "BR": true, "COL": true, "COMMAND": true, "EMBED": true, "HR": true,
+	"IMG": true, "INPUT": true, "KEYGEN": true, "LINK": true, "MENUITEM": true, "META": true,
+	"PARAM": true, "SOURCE": true, "TRACK": true, "WBR": true,
+}
+
+// HTML renders snapshot as an HTML document. The snapshot records the state
+// of form controls, which is rendered as their value, checked and selected
+// attributes. Subresources like images and stylesheets keep their original
+// URLs, their recorded content is in [Trace.Network].
+func (t *Trace) HTML(snapshot *Snapshot) (string, error) {
+	root, err := t.snapshotRoot(snapshot)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if snapshot.Doctype != "" {
+		fmt.Fprintf(&b, "<!DOCTYPE %s>", snapshot.Doctype)
+	}
+	if err := t.render(&b, root, snapshot, ""); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// snapshotRoot returns the decoded root node of snapshot.
+func (t *Trace) snapshotRoot(snapshot *Snapshot) (any, error) {
+	var root any
+	if err := json.Unmarshal(snapshot.html, &root); err != nil {
+		return nil, fmt.Errorf("trace: invalid snapshot %s: %w", snapshot.Name, err)
+	}
+	return root, nil
+}
+
+// snapshotNodes returns the nodes of snapshot in post-order, the order subtree
+// references of later snapshots count in.
+func (t *Trace) snapshotNodes(snapshot *Snapshot) ([]any, error) {
+	if snapshot.nodes != nil {
+		return snapshot.nodes, nil
+	}
+	root, err := t.snapshotRoot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []any{}
+	var visit func(node any)
+	visit = func(node any) {
+		switch node := node.(type) {
+		case string:
+			nodes = append(nodes, node)
+		case []any:
+			if _, _, ok := subtreeReference(node); ok || len(node) == 0 {
+				return
+			}
+			if len(node) > 2 {
+				for _, child := range node[2:] {
+					visit(child)
+				}
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	visit(root)
+	snapshot.nodes = nodes
+	return nodes, nil
+}
+
+// subtreeReference reports whether node is a reference to the node at index
+// of the snapshot taken snapshotsAgo before in the same frame, which the
+// recorder writes for unchanged subtrees.
+func subtreeReference(node []any) (snapshotsAgo, index int, ok bool) {
+	if len(node) != 1 {
+		return 0, 0, false
+	}
+	ref, isRef := node[0].([]any)
+	if !isRef || len(ref) != 2 {
+		return 0, 0, false
+	}
+	ago, ok1 := ref[0].(float64)
+	idx, ok2 := ref[1].(float64)
+	return int(ago), int(idx), ok1 && ok2
+}
+
+func (t *Trace) render(b *strings.Builder, node any, snapshot *Snapshot, parentTag string) error {
+	switch node := node.(type) {
+	case string:
+		if parentTag == "STYLE" || parentTag == "SCRIPT" {
+			b.WriteString(node)
+		} else {
+			b.WriteString(html.EscapeString(node))
+		}
+		return nil
+	case []any:
+		if ago, index, ok := subtreeReference(node); ok {
+			frame := t.frames[[2]string{snapshot.context, snapshot.FrameID}]
+			// References always point to an earlier snapshot, which also
+			// bounds the recursion.
+			referenced := snapshot.index - ago
+			if ago <= 0 || referenced < 0 || referenced >= len(frame) {
+				return errors.New("trace: snapshot references a missing snapshot")
+			}
+			nodes, err := t.snapshotNodes(frame[referenced])
+			if err != nil {
+				return err
+			}
+			if index < 0 || index >= len(nodes) {
+				return errors.New("trace: snapshot references a missing node")
+			}
+			return t.render(b, nodes[index], frame[referenced], parentTag)
+		}
+		if len(node) == 0 {
+			return nil
+		}
+		name, _ := node[0].(string)
+		if name == "" {
+			return nil
+		}
+		var attrs map[string]any
+		if len(node) > 1 {
+			attrs, _ = node[1].(map[string]any)
+		}
+		b.WriteString("<" + name)
+		writeAttributes(b, name, attrs)
+		b.WriteString(">")
+		if len(node) > 2 {
+			for _, child := range node[2:] {
+				if err := t.render(b, child, snapshot, name); err != nil {
+					return err
+				}
+			}
+		}
+		if !autoClosing[name] {
+			b.WriteString("</" + name + ">")
+		}
+	}
+	return nil
+}
+
+// writeAttributes writes the attributes of an element in a stable order,
+// replacing the recorder's state attributes with their HTML counterpart.
+func writeAttributes(b *strings.Builder, tag string, attrs map[string]any) {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := fmt.Sprint(attrs[name])
+		switch name {
+		case "__playwright_value_":
+			if tag == "TEXTAREA" {
+				continue
+			}
+			name = "value"
+		case "__playwright_checked_":
+			if value != "true" {
+				continue
+			}
+			name, value = "checked", ""
+		case "__playwright_selected_":
+			if value != "true" {
+				continue
+			}
+			name, value = "selected", ""
+		case "value":
+			if _, ok := attrs["__playwright_value_"]; ok && tag != "TEXTAREA" {
+				continue
+			}
+		default:
+			if strings.HasPrefix(name, "__playwright") {
+				continue
+			}
+		}
+		b.WriteString(" " + name)
+		if value != "" {
+			b.WriteString(`="` + html.EscapeString(value) + `"`)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package trace reads the trace archives written by Tracing.Stop and
+// Tracing.StopChunk into typed events, so traces can be summarized or asserted
+// on without the JavaScript trace viewer. It understands the event format of the
+// bundled driver, version 6 and later, and ignores events it doesn't know.
+package trace
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"time"
+
+	"github.com/mxschmitt/playwright-go/har"
+)
+
+// Trace is the content of a trace archive. A trace of several chunks or a
+// merged trace holds one ContextOptions per recorded context, all other events
+// are combined and ordered by time.
+type Trace struct {
+	Contexts    []*ContextOptions
+	Actions     []*Action
+	Console     []*ConsoleMessage
+	Network     []*har.Entry
+	Screenshots []*Screenshot
+	Snapshots   []*Snapshot
+	Events      []*Event
+
+	// files are the entries of the archive by name, for resources.
+	files map[string]*zip.File
+	// frames holds the snapshots of every frame by context and frame ID in
+	// recording order, which subtree references of the markup are relative to.
+	frames map[[2]string][]*Snapshot
+	closer func() error
+}
+
+// ContextOptions describes the browser context a trace was recorded in.
+type ContextOptions struct {
+	Version     int
+	BrowserName string
+	Platform    string
+	SDKLanguage string
+	Title       string
+	// WallTime is the time the recording started.
+	WallTime time.Time
+	// Options are the options the context was created with.
+	Options map[string]any
+}
+
+// Action is a call of the client, e.g. a click or a navigation.
+type Action struct {
+	CallID string
+	// ParentID is the CallID of the enclosing group, see Tracing.Group.
+	ParentID string
+	StepID   string
+	PageID   string
+	// Class and Method name the protocol call, e.g. "Frame" and "goto".
+	Class  string
+	Method string
+	// Title is set for groups and for calls with a custom title.
+	Title  string
+	Params map[string]any
+	Result any
+	Error  *Error
+	// Log holds the progress messages of the call, e.g. "waiting for locator".
+	Log       []*LogEntry
+	Stack     []StackFrame
+	StartTime time.Time
+	// EndTime is zero for calls that didn't finish before the recording stopped.
+	EndTime time.Time
+	// Point is where the mouse acted, for input actions.
+	Point *Point
+	// Before, Input and After are the snapshots of the main frame taken for
+	// the action, nil if snapshots weren't recorded.
+	Before *Snapshot
+	Input  *Snapshot
+	After  *Snapshot
+
+	// context is the ordinal of the recorded context within the archive.
+	context        string
+	beforeSnapshot string
+	inputSnapshot  string
+	afterSnapshot  string
+}
+
+// Name returns the title of the action or otherwise its protocol call, e.g.
+// "Frame.goto".
+func (a *Action) Name() string {
+	if a.Title != "" {
+		return a.Title
+	}
+	return a.Class + "." + a.Method
+}
+
+// Duration returns how long the action took, or zero if it didn't finish.
+func (a *Action) Duration() time.Duration {
+	if a.EndTime.IsZero() {
+		return 0
+	}
+	return a.EndTime.Sub(a.StartTime)
+}
+
+// Error is the error an action failed with.
+type Error struct {
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+	Stack   string `json:"stack,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// LogEntry is a progress message of an action.
+type LogEntry struct {
+	Time    time.Time
+	Message string
+}
+
+// StackFrame is a frame of the client stack an action was called from.
+type StackFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Function string `json:"function,omitempty"`
+}
+
+// Point is a position in CSS pixels.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ConsoleMessage is a message logged to the console of a page.
+type ConsoleMessage struct {
+	Time   time.Time
+	PageID string
+	// Type is the console method, e.g. "log" or "error".
+	Type     string
+	Text     string
+	Args     []ConsoleArg
+	Location ConsoleLocation
+}
+
+// ConsoleArg is an argument of a console call.
+type ConsoleArg struct {
+	Preview string `json:"preview"`
+	Value   any    `json:"value"`
+}
+
+// ConsoleLocation is the source location of a console call.
+type ConsoleLocation struct {
+	URL          string `json:"url"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
+}
+
+// Screenshot is a frame of the screencast recorded with the Screenshots option.
+type Screenshot struct {
+	Time   time.Time
+	PageID string
+	Width  int
+	Height int
+	// SHA1 names the JPEG image, see [Trace.Resource].
+	SHA1 string
+}
+
+// Snapshot is the DOM of a frame, taken before, during or after an action.
+type Snapshot struct {
+	Time   time.Time
+	CallID string
+	// Name is referenced by the action, e.g. "before@call@12".
+	Name        string
+	PageID      string
+	FrameID     string
+	FrameURL    string
+	IsMainFrame bool
+	Viewport    Size
+	Doctype     string
+
+	html    json.RawMessage
+	context string
+	// index is the position of the snapshot among those of its frame.
+	index int
+	// nodes caches the nodes of html in post-order for subtree references.
+	nodes []any
+}
+
+// Size is a size in CSS pixels.
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Event is an event of the client, e.g. a dialog or a new page.
+type Event struct {
+	Time   time.Time
+	PageID string
+	Class  string
+	Method string
+	Params map[string]any
+}
@@ -0,0 +1,163 @@
+package trace
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTraceEvents = `{"version":8,"type":"context-options","browserName":"chromium","platform":"linux","sdkLanguage":"javascript","wallTime":1700000000000,"monotonicTime":1000,"options":{"viewport":{"width":1280,"height":720}}}
+{"type":"before","callId":"call@1","startTime":1100,"class":"Frame","method":"goto","params":{"url":"https://example.com/"},"pageId":"page@1","beforeSnapshot":"before@call@1","stack":[{"file":"main.go","line":12,"column":3}]}
+{"type":"log","callId":"call@1","time":1110,"message":"navigating to \"https://example.com/\""}
+{"type":"frame-snapshot","snapshot":{"callId":"call@1","snapshotName":"before@call@1","pageId":"page@1","frameId":"frame@1","frameUrl":"about:blank","doctype":"html","html":["HTML",{},["BODY",{},["P",{"class":"a"},"x < y"],["INPUT",{"value":"old","__playwright_value_":"typed"}]]],"viewport":{"width":1280,"height":720},"timestamp":1105,"isMainFrame":true}}
+{"type":"console","time":1200,"pageId":"page@1","messageType":"warning","text":"hello 1","args":[{"preview":"hello","value":"hello"},{"preview":"1","value":1}],"location":{"url":"https://example.com/","lineNumber":3,"columnNumber":9}}
+{"type":"screencast-frame","pageId":"page@1","sha1":"page@1-1.jpeg","width":640,"height":360,"timestamp":1250}
+{"type":"after","callId":"call@1","endTime":1350,"afterSnapshot":"after@call@1","result":{"response":"<Response>"}}
+{"type":"frame-snapshot","snapshot":{"callId":"call@1","snapshotName":"after@call@1","pageId":"page@1","frameId":"frame@1","frameUrl":"https://example.com/","doctype":"html","html":["HTML",{},["BODY",{},[[1,1]],["DIV",{},"new"]]],"viewport":{"width":1280,"height":720},"timestamp":1340,"isMainFrame":true}}
+{"type":"before","callId":"call@2","parentId":"call@0","startTime":1400,"class":"Frame","method":"click","params":{"selector":"#missing"},"pageId":"page@1"}
+{"type":"input","callId":"call@2","point":{"x":10,"y":20}}
+{"type":"after","callId":"call@2","endTime":1700,"error":{"name":"TimeoutError","message":"Timeout 300ms exceeded."}}
+{"type":"before","callId":"call@0","startTime":1050,"class":"Tracing","method":"tracingGroup","title":"login"}
+{"type":"event","time":1500,"class":"Page","method":"dialog","params":{"message":"hi"},"pageId":"page@1"}
+{"type":"unknown-event","foo":1}
+`
+
+const testNetworkEvents = `{"type":"resource-snapshot","snapshot":{"startedDateTime":"2023-11-14T22:13:20.150Z","time":20,"request":{"method":"POST","url":"https://example.com/api","httpVersion":"HTTP/1.1","cookies":[],"headers":[],"queryString":[],"postData":{"mimeType":"text/plain","text":"abc"},"headersSize":-1,"bodySize":3},"response":{"status":200,"statusText":"OK","httpVersion":"HTTP/1.1","cookies":[],"headers":[],"content":{"size":5,"mimeType":"text/html","_sha1":"body.html"},"headersSize":-1,"bodySize":5,"redirectURL":""},"cache":{},"timings":{"send":-1,"wait":-1,"receive":-1},"pageref":"page@1","_monotonicTime":1150}}
+`
+
+func writeTestTrace(t *testing.T, files map[string]string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	path := filepath.Join(t.TempDir(), "trace.zip")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path
+}
+
+func TestOpen(t *testing.T) {
+	tr, err := Open(writeTestTrace(t, map[string]string{
+		"trace.trace":             testTraceEvents,
+		"trace.network":           testNetworkEvents,
+		"resources/page@1-1.jpeg": "jpeg",
+		"resources/body.html":     "hello",
+	}))
+	require.NoError(t, err)
+	defer tr.Close() //nolint:errcheck
+
+	require.Len(t, tr.Contexts, 1)
+	require.Equal(t, "chromium", tr.Contexts[0].BrowserName)
+	require.Equal(t, 8, tr.Contexts[0].Version)
+	start := time.UnixMilli(1700000000000)
+	require.True(t, start.Equal(tr.Contexts[0].WallTime))
+
+	require.Len(t, tr.Actions, 3)
+	group, goTo, click := tr.Actions[0], tr.Actions[1], tr.Actions[2]
+	require.Equal(t, "login", group.Name())
+	require.Equal(t, time.Duration(0), group.Duration())
+	require.Equal(t, "Frame.goto", goTo.Name())
+	require.Equal(t, "https://example.com/", goTo.Params["url"])
+	require.True(t, start.Add(100*time.Millisecond).Equal(goTo.StartTime))
+	require.Equal(t, 250*time.Millisecond, goTo.Duration())
+	require.Nil(t, goTo.Error)
+	require.Len(t, goTo.Log, 1)
+	require.Equal(t, `navigating to "https://example.com/"`, goTo.Log[0].Message)
+	require.Equal(t, []StackFrame{{File: "main.go", Line: 12, Column: 3}}, goTo.Stack)
+	require.NotNil(t, goTo.Before)
+	require.NotNil(t, goTo.After)
+	require.Nil(t, goTo.Input)
+	require.Equal(t, "call@0", click.ParentID)
+	require.Equal(t, &Point{X: 10, Y: 20}, click.Point)
+	require.EqualError(t, click.Error, "Timeout 300ms exceeded.")
+	require.Equal(t, "TimeoutError", click.Error.Name)
+
+	require.Len(t, tr.Console, 1)
+	require.Equal(t, "warning", tr.Console[0].Type)
+	require.Equal(t, "hello 1", tr.Console[0].Text)
+	require.Len(t, tr.Console[0].Args, 2)
+	require.Equal(t, 3, tr.Console[0].Location.LineNumber)
+
+	require.Len(t, tr.Events, 1)
+	require.Equal(t, "dialog", tr.Events[0].Method)
+
+	require.Len(t, tr.Screenshots, 1)
+	require.Equal(t, 640, tr.Screenshots[0].Width)
+	image, err := tr.Image(tr.Screenshots[0])
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", string(image))
+	_, err = tr.Resource("missing")
+	require.ErrorIs(t, err, ErrResourceNotFound)
+
+	require.Len(t, tr.Network, 1)
+	entry := tr.Network[0]
+	require.Equal(t, "https://example.com/api", entry.Request.URL)
+	body, err := tr.Body(entry)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(body))
+	requestBody, err := tr.RequestBody(entry)
+	require.NoError(t, err)
+	require.Equal(t, "abc", string(requestBody))
+}
+
+func TestSnapshotHTML(t *testing.T) {
+	tr, err := Open(writeTestTrace(t, map[string]string{"trace.trace": testTraceEvents}))
+	require.NoError(t, err)
+	defer tr.Close() //nolint:errcheck
+	require.Len(t, tr.Snapshots, 2)
+
+	before, err := tr.HTML(tr.Actions[1].Before)
+	require.NoError(t, err)
+	require.Equal(t, `<!DOCTYPE html><HTML><BODY><P class="a">x &lt; y</P><INPUT value="typed"></BODY></HTML>`, before)
+
+	// The after snapshot references the paragraph of the before snapshot.
+	after, err := tr.HTML(tr.Actions[1].After)
+	require.NoError(t, err)
+	require.Equal(t, `<!DOCTYPE html><HTML><BODY><P class="a">x &lt; y</P><DIV>new</DIV></BODY></HTML>`, after)
+}
+
+func TestSnapshotHTMLRejectsSelfReference(t *testing.T) {
+	events := strings.ReplaceAll(testTraceEvents, `[[1,1]]`, `[[0,1]]`)
+	tr, err := Open(writeTestTrace(t, map[string]string{"trace.trace": events}))
+	require.NoError(t, err)
+	defer tr.Close() //nolint:errcheck
+	_, err = tr.HTML(tr.Actions[1].After)
+	require.EqualError(t, err, "trace: snapshot references a missing snapshot")
+}
+
+func TestOpenMergedTrace(t *testing.T) {
+	second := strings.ReplaceAll(testTraceEvents, `"wallTime":1700000000000`, `"wallTime":1600000000000`)
+	tr, err := Open(writeTestTrace(t, map[string]string{
+		"0-trace.trace": testTraceEvents,
+		"1-trace.trace": second,
+	}))
+	require.NoError(t, err)
+	defer tr.Close() //nolint:errcheck
+	require.Len(t, tr.Contexts, 2)
+	require.Len(t, tr.Actions, 6)
+	// Actions are ordered by time across contexts.
+	require.Equal(t, 1600000000050, int(tr.Actions[0].StartTime.UnixMilli()))
+	for _, action := range tr.Actions {
+		if action.After != nil {
+			html, err := tr.HTML(action.After)
+			require.NoError(t, err)
+			require.Contains(t, html, "x &lt; y")
+		}
+	}
+}
+
+func TestOpenWithoutTrace(t *testing.T) {
+	_, err := Open(writeTestTrace(t, map[string]string{"other.txt": "x"}))
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package playwright
 
 import (
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -14,6 +15,7 @@ type tracingImpl struct {
 	tracesDir         string
 	harRecorders      map[string]harRecordingMetadata
 	additionalSources map[string]struct{}
+	redactor          *redactor
 }
 
 func (t *tracingImpl) Start(options ...TracingStartOptions) error {
@@ -100,6 +102,15 @@ func (t *tracingImpl) doStopChunk(filePath string) (err error) {
 		return err
 	}
 
+	// The unredacted trace is written next to filePath and only the redacted
+	// one is moved there.
+	target := filePath
+	r := t.currentRedactor()
+	if r != nil {
+		filePath = redactionTempPath(target)
+		defer os.Remove(filePath) //nolint:errcheck
+	}
+
 	isLocal := !t.connection.isRemote
 	if isLocal {
 		result, err := t.channel.SendReturnAsDict("tracingStopChunk", map[string]any{
@@ -120,7 +131,10 @@ func (t *tracingImpl) doStopChunk(filePath string) (err error) {
 			IncludeSources:    t.includeSources,
 			AdditionalSources: additionalSources,
 		})
-		return err
+		if err != nil {
+			return err
+		}
+		return redactTrace(r, filePath, target)
 	}
 
 	result, err := t.channel.SendReturnAsDict("tracingStopChunk", map[string]any{
@@ -156,7 +170,35 @@ func (t *tracingImpl) doStopChunk(filePath string) (err error) {
 		IncludeSources:    t.includeSources,
 		AdditionalSources: additionalSources,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	return redactTrace(r, filePath, target)
+}
+
+// currentRedactor returns the rules set via SetRedaction, nil if there are none.
+func (t *tracingImpl) currentRedactor() *redactor {
+	t.RLock()
+	defer t.RUnlock()
+	return t.redactor
+}
+
+// redactTrace applies r, if not nil, to the trace written to src and writes
+// the result to target.
+func redactTrace(r *redactor, src, target string) error {
+	if r == nil {
+		return nil
+	}
+	return r.redactTraceFile(src, target)
+}
+
+// redactHar applies the rules set via SetRedaction to a written HAR.
+func (t *tracingImpl) redactHar(path string) error {
+	r := t.currentRedactor()
+	if r == nil {
+		return nil
+	}
+	return r.redactHarFile(path)
 }
 
 func (t *tracingImpl) startCollectingStacks(name string) (err error) {
@@ -252,6 +294,9 @@ func (t *tracingImpl) exportAllHars() error {
 				return err
 			}
 			if !needCompressed {
+				if err := t.redactHar(harMetaData.Path); err != nil {
+					return err
+				}
 				continue
 			}
 			entries, ok := response["entries"].([]any)
@@ -265,6 +310,9 @@ func (t *tracingImpl) exportAllHars() error {
 			}); err != nil {
 				return err
 			}
+			if err := t.redactHar(harMetaData.Path); err != nil {
+				return err
+			}
 			continue
 		}
 		overrides["mode"] = "archive"
@@ -289,6 +337,9 @@ func (t *tracingImpl) exportAllHars() error {
 		if err := artifact.Delete(); err != nil {
 			return err
 		}
+		if err := t.redactHar(harMetaData.Path); err != nil {
+			return err
+		}
 	}
 	return nil
 }
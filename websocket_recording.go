@@ -0,0 +1,573 @@
+package playwright
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// WebSocketFrameDirection tells whether a recorded frame was sent by the page or
+// received from the server.
+type WebSocketFrameDirection string
+
+const (
+	WebSocketFrameSent     WebSocketFrameDirection = "sent"
+	WebSocketFrameReceived WebSocketFrameDirection = "received"
+)
+
+// WebSocketFrame is a recorded WebSocket message.
+type WebSocketFrame struct {
+	Direction WebSocketFrameDirection `json:"direction"`
+	Timestamp time.Time               `json:"timestamp"`
+	// Text is set for text frames.
+	Text *string `json:"text,omitempty"`
+	// Binary is set for binary frames, it is base64 encoded in JSON.
+	Binary []byte `json:"binary,omitempty"`
+	// Close is set for close frames.
+	Close *WebSocketCloseFrame `json:"close,omitempty"`
+}
+
+// WebSocketCloseFrame is the status of a recorded close frame.
+type WebSocketCloseFrame struct {
+	// Code is the close code, nil if the frame had none or the connection was
+	// closed without a reported close frame.
+	Code   *int   `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Message returns the payload as accepted by [WebSocketRoute.Send]: a string for
+// text frames and a []byte for binary frames. It is nil for close frames.
+func (f *WebSocketFrame) Message() any {
+	if f.Close != nil {
+		return nil
+	}
+	if f.Text != nil {
+		return *f.Text
+	}
+	return f.Binary
+}
+
+// WebSocketSession is a recorded WebSocket connection.
+type WebSocketSession struct {
+	URL    string           `json:"url"`
+	Start  time.Time        `json:"start"`
+	Frames []WebSocketFrame `json:"frames"`
+}
+
+// WebSocketRecording is a set of WebSocket connections recorded by
+// [RecordWebSockets], in the order they were opened.
+type WebSocketRecording struct {
+	Sessions []*WebSocketSession `json:"sessions"`
+}
+
+// ReadWebSocketRecording reads a recording written by [RecordWebSockets] or
+// [WebSocketRecording.WriteFile].
+func ReadWebSocketRecording(path string) (*WebSocketRecording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	recording := &WebSocketRecording{}
+	if err := json.Unmarshal(data, recording); err != nil {
+		return nil, fmt.Errorf("could not parse WebSocket recording %s: %w", path, err)
+	}
+	return recording, nil
+}
+
+// WriteFile writes the recording to path as indented JSON, creating missing
+// directories.
+func (r *WebSocketRecording) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// RecordWebSocketsOptions are the options for [RecordWebSockets].
+type RecordWebSocketsOptions struct {
+	// Only connections to URLs matching this glob pattern, regular expression or
+	// predicate are recorded. Defaults to all connections.
+	URL any
+}
+
+// WebSocketRecorder records the WebSocket connections of a page, see
+// [RecordWebSockets].
+type WebSocketRecorder struct {
+	page      Page
+	path      string
+	matcher   *urlMatcher
+	listener  func(WebSocket)
+	mu        sync.Mutex
+	stopped   bool
+	recording WebSocketRecording
+	// detach removes the frame and close listeners of the recorded connections.
+	detach []func()
+}
+
+// RecordWebSockets records the frames sent and received by the WebSocket
+// connections the page opens from now on, with their timestamps. The recording
+// is written to path by [WebSocketRecorder.Stop] and can be replayed with
+// [RouteWebSocketReplay]:
+//
+//	recorder, _ := playwright.RecordWebSockets(page, "testdata/dashboard.ws.json")
+//	// ... drive the page against the real server ...
+//	err := recorder.Stop()
+//
+// Frames are recorded as text or binary by their opcode. Close frames are
+// recorded too, and a connection that closes without a reported close frame
+// gets a received close frame without code, so replay closes it as well.
+// Only WebSockets of pages created by this package are recorded.
+func RecordWebSockets(page Page, path string, options ...RecordWebSocketsOptions) (*WebSocketRecorder, error) {
+	recorder := &WebSocketRecorder{
+		page:      page,
+		path:      path,
+		recording: WebSocketRecording{Sessions: []*WebSocketSession{}},
+	}
+	if len(options) == 1 && options[0].URL != nil {
+		matcher, err := newWebSocketURLMatcher(page, options[0].URL)
+		if err != nil {
+			return nil, err
+		}
+		recorder.matcher = matcher
+	}
+	recorder.listener = recorder.onWebSocket
+	page.OnWebSocket(recorder.listener)
+	return recorder, nil
+}
+
+// newWebSocketURLMatcher matches WebSocket URLs the way RouteWebSocket does on
+// the page or context, resolving globs against its base URL.
+func newWebSocketURLMatcher(router any, url any) (*urlMatcher, error) {
+	switch url.(type) {
+	case string, *regexp.Regexp, func(string) bool:
+	default:
+		return nil, fmt.Errorf("invalid URL pattern: %v", url)
+	}
+	var baseURL *string
+	switch r := router.(type) {
+	case *pageImpl:
+		baseURL = r.browserContext.options.BaseURL
+	case *browserContextImpl:
+		baseURL = r.options.BaseURL
+	}
+	return newURLMatcher(url, baseURL, true), nil
+}
+
+func (r *WebSocketRecorder) onWebSocket(ws WebSocket) {
+	if r.matcher != nil && !r.matcher.Matches(ws.URL()) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+	impl, ok := ws.(*webSocketImpl)
+	if !ok {
+		return
+	}
+	session := &WebSocketSession{URL: ws.URL(), Start: time.Now(), Frames: []WebSocketFrame{}}
+	r.recording.Sessions = append(r.recording.Sessions, session)
+	// The protocol events carry the opcode, which the payloads passed to
+	// OnFrameSent and OnFrameReceived don't.
+	record := func(direction WebSocketFrameDirection) func(map[string]any) {
+		return func(params map[string]any) {
+			opcode, _ := params["opcode"].(float64)
+			data, _ := params["data"].(string)
+			frame, ok := newRecordedFrame(direction, int(opcode), data)
+			if !ok {
+				return
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if !r.stopped {
+				session.Frames = append(session.Frames, frame)
+			}
+		}
+	}
+	onSent, onReceived := record(WebSocketFrameSent), record(WebSocketFrameReceived)
+	impl.channel.On("frameSent", onSent)
+	impl.channel.On("frameReceived", onReceived)
+	onClose := func(WebSocket) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.stopped {
+			return
+		}
+		if n := len(session.Frames); n > 0 && session.Frames[n-1].Close != nil {
+			return
+		}
+		session.Frames = append(session.Frames, WebSocketFrame{
+			Direction: WebSocketFrameReceived,
+			Timestamp: time.Now(),
+			Close:     &WebSocketCloseFrame{},
+		})
+	}
+	ws.OnClose(onClose)
+	r.detach = append(r.detach, func() {
+		impl.channel.RemoveListener("frameSent", onSent)
+		impl.channel.RemoveListener("frameReceived", onReceived)
+		impl.RemoveListener("close", onClose)
+	})
+}
+
+// newRecordedFrame converts a frame event of the protocol. Text frames carry
+// their payload as is, binary and close frames base64 encoded. Control frames
+// other than close are skipped.
+func newRecordedFrame(direction WebSocketFrameDirection, opcode int, data string) (WebSocketFrame, bool) {
+	frame := WebSocketFrame{Direction: direction, Timestamp: time.Now()}
+	switch opcode {
+	case 1:
+		frame.Text = String(data)
+	case 2, 8:
+		payload, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			logger.Error("could not decode WebSocket frame payload", "error", err)
+			return frame, false
+		}
+		if opcode == 2 {
+			frame.Binary = payload
+			break
+		}
+		frame.Close = &WebSocketCloseFrame{}
+		if len(payload) >= 2 {
+			frame.Close.Code = Int(int(payload[0])<<8 | int(payload[1]))
+			frame.Close.Reason = string(payload[2:])
+		}
+	default:
+		return frame, false
+	}
+	return frame, true
+}
+
+// Recording returns a copy of what has been recorded so far.
+func (r *WebSocketRecorder) Recording() *WebSocketRecording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recording := &WebSocketRecording{Sessions: make([]*WebSocketSession, len(r.recording.Sessions))}
+	for i, session := range r.recording.Sessions {
+		copied := *session
+		copied.Frames = append([]WebSocketFrame{}, session.Frames...)
+		recording.Sessions[i] = &copied
+	}
+	return recording
+}
+
+// Stop stops recording and writes the recording to the file passed to
+// [RecordWebSockets]. Calling it again rewrites the file.
+func (r *WebSocketRecorder) Stop() error {
+	r.mu.Lock()
+	detach := r.detach
+	if !r.stopped {
+		r.stopped = true
+		r.detach = nil
+		r.page.RemoveListener("websocket", r.listener)
+	}
+	r.mu.Unlock()
+	for _, fn := range detach {
+		fn()
+	}
+	return r.Recording().WriteFile(r.path)
+}
+
+// WebSocketMessageMatcher reports whether a message sent by the page
+// corresponds to a recorded frame. message is a string or []byte as passed to
+// [WebSocketRoute.OnMessage].
+type WebSocketMessageMatcher func(message any, frame *WebSocketFrame) bool
+
+// MatchWebSocketMessage matches messages with exactly the recorded payload.
+func MatchWebSocketMessage() WebSocketMessageMatcher {
+	return func(message any, frame *WebSocketFrame) bool {
+		switch m := message.(type) {
+		case string:
+			return frame.Text != nil && *frame.Text == m
+		case []byte:
+			return frame.Text == nil && bytes.Equal(frame.Binary, m)
+		}
+		return false
+	}
+}
+
+// MatchWebSocketJSON matches JSON messages equal to the recorded ones, ignoring
+// the values at ignorePaths, e.g. "$.requestId" or "$.meta.timestamp". Messages
+// that are not JSON are compared as is. It returns an error if a path is
+// invalid.
+func MatchWebSocketJSON(ignorePaths ...string) (WebSocketMessageMatcher, error) {
+	paths := make([]jsonPath, 0, len(ignorePaths))
+	for _, source := range ignorePaths {
+		path, err := parseJSONPath(source)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	normalize := func(payload []byte) (any, bool) {
+		var value any
+		if err := json.Unmarshal(payload, &value); err != nil {
+			return nil, false
+		}
+		for _, path := range paths {
+			path.apply(value, path.segments, "")
+		}
+		return value, true
+	}
+	exact := MatchWebSocketMessage()
+	return func(message any, frame *WebSocketFrame) bool {
+		text, ok := message.(string)
+		if !ok || frame.Text == nil {
+			return exact(message, frame)
+		}
+		messageValue, ok := normalize([]byte(text))
+		if !ok {
+			return exact(message, frame)
+		}
+		frameValue, ok := normalize([]byte(*frame.Text))
+		return ok && reflect.DeepEqual(messageValue, frameValue)
+	}, nil
+}
+
+// WebSocketRouter is implemented by [Page] and [BrowserContext].
+type WebSocketRouter interface {
+	RouteWebSocket(url any, handler func(WebSocketRoute)) error
+}
+
+var (
+	_ WebSocketRouter = (*pageImpl)(nil)
+	_ WebSocketRouter = (*browserContextImpl)(nil)
+)
+
+// RouteWebSocketReplayOptions are the options for [RouteWebSocketReplay].
+type RouteWebSocketReplayOptions struct {
+	// Connections to URLs matching this glob pattern, regular expression or
+	// predicate are replayed. Defaults to the URLs of the recorded sessions.
+	// Connections whose URL no session was recorded for, e.g. because it carries
+	// a token, are served by the sessions whose URL matches the pattern.
+	URL any
+	// What to do with connections no session was recorded for. Defaults to
+	// [HarNotFoundAbort], which closes them; [HarNotFoundFallback] connects them
+	// to the server.
+	NotFound *HarNotFound
+	// Decides which recorded frame a message sent by the page corresponds to.
+	// Defaults to [MatchWebSocketMessage].
+	Matcher WebSocketMessageMatcher
+	// Wait between received frames as long as while recording. By default they
+	// are sent right away.
+	PreserveTiming *bool
+}
+
+// RouteWebSocketReplay answers WebSocket connections from a recording instead of
+// the server. Each connection is served by the next recorded session with the
+// same URL, or matching the URL option, reusing the last one once they are
+// exhausted.
+//
+// Frames the server sent before the page's first message are sent on connect.
+// When the page sends a message, the next recorded message it matches is looked
+// up and the frames the server sent in response, up to the following page
+// message, are replayed. Messages no recorded frame matches are ignored. A
+// recorded close frame received from the server closes the connection.
+func RouteWebSocketReplay(router WebSocketRouter, recording *WebSocketRecording, options ...RouteWebSocketReplayOptions) error {
+	option := RouteWebSocketReplayOptions{}
+	if len(options) == 1 {
+		option = options[0]
+	}
+	r := &webSocketReplayer{
+		recording:      recording,
+		matcher:        option.Matcher,
+		notFound:       HarNotFoundAbort,
+		preserveTiming: option.PreserveTiming != nil && *option.PreserveTiming,
+		calls:          make(map[string]int),
+	}
+	if r.matcher == nil {
+		r.matcher = MatchWebSocketMessage()
+	}
+	if option.NotFound != nil {
+		r.notFound = option.NotFound
+	}
+	var pattern any = func(u string) bool {
+		_, sessions := r.sessions(u)
+		return len(sessions) > 0
+	}
+	if option.URL != nil {
+		matcher, err := newWebSocketURLMatcher(router, option.URL)
+		if err != nil {
+			return err
+		}
+		r.pattern = matcher
+		pattern = option.URL
+	}
+	return router.RouteWebSocket(pattern, r.handle)
+}
+
+type webSocketReplayer struct {
+	recording *WebSocketRecording
+	// pattern is the URL option the route was installed with, if any.
+	pattern        *urlMatcher
+	matcher        WebSocketMessageMatcher
+	notFound       *HarNotFound
+	preserveTiming bool
+
+	mu sync.Mutex
+	// calls counts the connections served per key returned by sessions.
+	calls map[string]int
+}
+
+// sessions returns the sessions recorded for url, or if there are none and url
+// matches the pattern, those whose URL matches it too. key identifies the returned set, so that
+// connections served by the same sessions take turns on them.
+func (r *webSocketReplayer) sessions(url string) (key string, sessions []*WebSocketSession) {
+	for _, session := range r.recording.Sessions {
+		if session.URL == url {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) > 0 || r.pattern == nil || !r.pattern.Matches(url) {
+		return url, sessions
+	}
+	for _, session := range r.recording.Sessions {
+		if r.pattern.Matches(session.URL) {
+			sessions = append(sessions, session)
+		}
+	}
+	// URLs don't contain spaces, so this can't collide with a recorded URL.
+	return "pattern match", sessions
+}
+
+// next returns the session answering the next connection to url.
+func (r *webSocketReplayer) next(url string) *WebSocketSession {
+	key, sessions := r.sessions(url)
+	if len(sessions) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	call := r.calls[key]
+	r.calls[key]++
+	return sessions[min(call, len(sessions)-1)]
+}
+
+func (r *webSocketReplayer) handle(ws WebSocketRoute) {
+	session := r.next(ws.URL())
+	if session == nil {
+		if *r.notFound == *HarNotFoundFallback {
+			if _, err := ws.ConnectToServer(); err != nil {
+				logger.Error("Error connecting WebSocket to server", "error", err)
+			}
+			return
+		}
+		ws.Close(WebSocketRouteCloseOptions{Code: Int(1011), Reason: String("no recorded WebSocket session")})
+		return
+	}
+	replay := &webSocketReplay{replayer: r, route: ws, frames: session.Frames}
+	ws.OnMessage(replay.onMessage)
+	replay.sendReceived(0)
+}
+
+// webSocketReplay is the state of one replayed connection.
+type webSocketReplay struct {
+	replayer *webSocketReplayer
+	route    WebSocketRoute
+	frames   []WebSocketFrame
+
+	mu sync.Mutex
+	// cursor is the index of the first frame not replayed or matched yet.
+	cursor int
+	// queue holds the indexes of frames waiting to be sent with PreserveTiming,
+	// sending tells whether a goroutine is draining it.
+	queue   []int
+	sending bool
+}
+
+func (p *webSocketReplay) onMessage(message any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := p.cursor; i < len(p.frames); i++ {
+		frame := &p.frames[i]
+		if frame.Direction == WebSocketFrameSent && frame.Close == nil && p.replayer.matcher(message, frame) {
+			p.sendReceived(i + 1)
+			return
+		}
+	}
+}
+
+// sendReceived replays the received frames from start up to the next sent
+// frame. It must be called with p.mu held or before messages arrive.
+func (p *webSocketReplay) sendReceived(start int) {
+	end := start
+	for end < len(p.frames) && p.frames[end].Direction == WebSocketFrameReceived {
+		end++
+	}
+	p.cursor = end
+	if !p.replayer.preserveTiming {
+		for i := start; i < end; i++ {
+			p.send(&p.frames[i])
+		}
+		return
+	}
+	for i := start; i < end; i++ {
+		p.queue = append(p.queue, i)
+	}
+	if !p.sending && len(p.queue) > 0 {
+		p.sending = true
+		go p.drain()
+	}
+}
+
+// drain sends the queued frames, waiting before each as long as it followed the
+// previous frame while recording.
+func (p *webSocketReplay) drain() {
+	for {
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.sending = false
+			p.mu.Unlock()
+			return
+		}
+		i := p.queue[0]
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+		if i > 0 {
+			if delay := p.frames[i].Timestamp.Sub(p.frames[i-1].Timestamp); delay > 0 {
+				time.Sleep(delay)
+			}
+		}
+		p.send(&p.frames[i])
+	}
+}
+
+// orderedWebSocketSender is implemented by routes that can send a message
+// synchronously. [WebSocketRoute.Send] sends in the background, so frames sent
+// one after another, or a close following them, could overtake each other.
+type orderedWebSocketSender interface {
+	sendInOrder(message any) error
+}
+
+// send replays a received frame, closing the connection for close frames.
+func (p *webSocketReplay) send(frame *WebSocketFrame) {
+	if frame.Close == nil {
+		sender, ok := p.route.(orderedWebSocketSender)
+		if !ok {
+			p.route.Send(frame.Message())
+			return
+		}
+		if err := sender.sendInOrder(frame.Message()); err != nil {
+			logger.Error("could not replay WebSocket frame", "error", err)
+		}
+		return
+	}
+	options := WebSocketRouteCloseOptions{Code: frame.Close.Code}
+	if frame.Close.Reason != "" {
+		options.Reason = String(frame.Close.Reason)
+	}
+	p.route.Close(options)
+}
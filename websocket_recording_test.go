@@ -0,0 +1,264 @@
+package playwright
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebSocketRoute struct {
+	WebSocketRoute
+	url       string
+	mu        sync.Mutex
+	sent      []any
+	onMessage func(any)
+	closed    *WebSocketRouteCloseOptions
+}
+
+func (r *fakeWebSocketRoute) URL() string                 { return r.url }
+func (r *fakeWebSocketRoute) OnMessage(handler func(any)) { r.onMessage = handler }
+func (r *fakeWebSocketRoute) Send(message any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, message)
+}
+
+func (r *fakeWebSocketRoute) Close(options ...WebSocketRouteCloseOptions) {
+	r.closed = &options[0]
+}
+
+func (r *fakeWebSocketRoute) messages() []any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]any{}, r.sent...)
+}
+
+func testWebSocketSession(url string) *WebSocketSession {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	frame := func(direction WebSocketFrameDirection, offset time.Duration, text string) WebSocketFrame {
+		return WebSocketFrame{Direction: direction, Timestamp: start.Add(offset), Text: String(text)}
+	}
+	return &WebSocketSession{
+		URL:   url,
+		Start: start,
+		Frames: []WebSocketFrame{
+			frame(WebSocketFrameReceived, 0, "welcome"),
+			frame(WebSocketFrameSent, 10*time.Millisecond, `{"op":"subscribe","id":1}`),
+			frame(WebSocketFrameReceived, 20*time.Millisecond, "subscribed"),
+			frame(WebSocketFrameReceived, 30*time.Millisecond, "tick 1"),
+			frame(WebSocketFrameSent, 40*time.Millisecond, "ping"),
+			frame(WebSocketFrameReceived, 50*time.Millisecond, "pong"),
+			{Direction: WebSocketFrameReceived, Timestamp: start.Add(60 * time.Millisecond), Binary: []byte{0xff, 0x00}},
+		},
+	}
+}
+
+func TestWebSocketRecordingRoundTrip(t *testing.T) {
+	recording := &WebSocketRecording{Sessions: []*WebSocketSession{testWebSocketSession("wss://example.com/live")}}
+	path := filepath.Join(t.TempDir(), "nested", "live.json")
+	require.NoError(t, recording.WriteFile(path))
+	read, err := ReadWebSocketRecording(path)
+	require.NoError(t, err)
+	require.Equal(t, recording, read)
+	require.Equal(t, []byte{0xff, 0x00}, read.Sessions[0].Frames[6].Message())
+	require.Equal(t, "pong", read.Sessions[0].Frames[5].Message())
+}
+
+func TestWebSocketReplay(t *testing.T) {
+	recording := &WebSocketRecording{Sessions: []*WebSocketSession{testWebSocketSession("wss://example.com/live")}}
+	matcher, err := MatchWebSocketJSON("$.id")
+	require.NoError(t, err)
+	replayer := &webSocketReplayer{
+		recording: recording,
+		matcher:   matcher,
+		notFound:  HarNotFoundAbort,
+		calls:     map[string]int{},
+	}
+	route := &fakeWebSocketRoute{url: "wss://example.com/live"}
+	replayer.handle(route)
+	require.Equal(t, []any{"welcome"}, route.messages())
+
+	route.onMessage("unknown")
+	require.Equal(t, []any{"welcome"}, route.messages())
+	route.onMessage(`{"id":7,"op":"subscribe"}`)
+	require.Equal(t, []any{"welcome", "subscribed", "tick 1"}, route.messages())
+	route.onMessage(`{"id":7,"op":"subscribe"}`)
+	require.Len(t, route.messages(), 3)
+	route.onMessage("ping")
+	require.Equal(t, []any{"welcome", "subscribed", "tick 1", "pong", []byte{0xff, 0x00}}, route.messages())
+
+	unknown := &fakeWebSocketRoute{url: "wss://example.com/other"}
+	replayer.handle(unknown)
+	require.NotNil(t, unknown.closed)
+	require.Equal(t, 1011, *unknown.closed.Code)
+}
+
+func TestNewRecordedFrame(t *testing.T) {
+	// Text frames are recorded by their opcode even if the payload isn't UTF-8.
+	frame, ok := newRecordedFrame(WebSocketFrameReceived, 1, "caf\xe9")
+	require.True(t, ok)
+	require.Equal(t, "caf\xe9", *frame.Text)
+
+	frame, ok = newRecordedFrame(WebSocketFrameSent, 2, base64.StdEncoding.EncodeToString([]byte("ok")))
+	require.True(t, ok)
+	require.Nil(t, frame.Text)
+	require.Equal(t, []byte("ok"), frame.Binary)
+
+	frame, ok = newRecordedFrame(WebSocketFrameReceived, 8, base64.StdEncoding.EncodeToString([]byte("\x0f\xa0bye")))
+	require.True(t, ok)
+	require.Equal(t, &WebSocketCloseFrame{Code: Int(4000), Reason: "bye"}, frame.Close)
+	require.Nil(t, frame.Message())
+
+	_, ok = newRecordedFrame(WebSocketFrameReceived, 9, "")
+	require.False(t, ok)
+}
+
+func TestWebSocketReplayClose(t *testing.T) {
+	session := testWebSocketSession("wss://example.com/live")
+	session.Frames = append(session.Frames[:2], WebSocketFrame{
+		Direction: WebSocketFrameReceived,
+		Close:     &WebSocketCloseFrame{Code: Int(4000), Reason: "bye"},
+	})
+	replayer := &webSocketReplayer{
+		recording: &WebSocketRecording{Sessions: []*WebSocketSession{session}},
+		matcher:   MatchWebSocketMessage(),
+		notFound:  HarNotFoundAbort,
+		calls:     map[string]int{},
+	}
+	route := &fakeWebSocketRoute{url: "wss://example.com/live"}
+	replayer.handle(route)
+	require.Nil(t, route.closed)
+	route.onMessage(`{"op":"subscribe","id":1}`)
+	require.Equal(t, []any{"welcome"}, route.messages())
+	require.NotNil(t, route.closed)
+	require.Equal(t, 4000, *route.closed.Code)
+	require.Equal(t, "bye", *route.closed.Reason)
+}
+
+func TestWebSocketReplayPreserveTiming(t *testing.T) {
+	recording := &WebSocketRecording{Sessions: []*WebSocketSession{testWebSocketSession("wss://example.com/live")}}
+	replayer := &webSocketReplayer{
+		recording:      recording,
+		matcher:        MatchWebSocketMessage(),
+		notFound:       HarNotFoundAbort,
+		preserveTiming: true,
+		calls:          map[string]int{},
+	}
+	route := &fakeWebSocketRoute{url: "wss://example.com/live"}
+	started := time.Now()
+	replayer.handle(route)
+	route.onMessage(`{"op":"subscribe","id":1}`)
+	require.Eventually(t, func() bool { return len(route.messages()) == 3 }, time.Second, time.Millisecond)
+	require.GreaterOrEqual(t, time.Since(started), 20*time.Millisecond)
+	require.Equal(t, []any{"welcome", "subscribed", "tick 1"}, route.messages())
+}
+
+func TestWebSocketReplayerSessions(t *testing.T) {
+	first := testWebSocketSession("wss://example.com/live")
+	second := testWebSocketSession("wss://example.com/live")
+	replayer := &webSocketReplayer{
+		recording: &WebSocketRecording{Sessions: []*WebSocketSession{first, testWebSocketSession("wss://example.com/other"), second}},
+		calls:     map[string]int{},
+	}
+	require.Same(t, first, replayer.next("wss://example.com/live"))
+	require.Same(t, second, replayer.next("wss://example.com/live"))
+	require.Same(t, second, replayer.next("wss://example.com/live"))
+	require.Nil(t, replayer.next("wss://example.com/missing"))
+}
+
+func TestWebSocketReplayerSessionsByPattern(t *testing.T) {
+	first := testWebSocketSession("wss://example.com/live?token=a")
+	second := testWebSocketSession("wss://example.com/live?token=b")
+	replayer := &webSocketReplayer{
+		recording: &WebSocketRecording{Sessions: []*WebSocketSession{first, testWebSocketSession("wss://example.com/other"), second}},
+		pattern:   newURLMatcher("**/live?*", nil, true),
+		calls:     map[string]int{},
+	}
+	require.Same(t, first, replayer.next("wss://example.com/live?token=c"))
+	require.Same(t, second, replayer.next("wss://example.com/live?token=d"))
+	require.Same(t, second, replayer.next("wss://example.com/live?token=e"))
+	// Sessions recorded for the exact URL are preferred.
+	require.Same(t, first, replayer.next("wss://example.com/live?token=a"))
+	require.Nil(t, replayer.next("wss://example.com/missing"))
+}
+
+type fakeOrderedWebSocketRoute struct {
+	fakeWebSocketRoute
+}
+
+func (r *fakeOrderedWebSocketRoute) Send(message any) {
+	panic("replay must send in order")
+}
+
+func (r *fakeOrderedWebSocketRoute) sendInOrder(message any) error {
+	r.fakeWebSocketRoute.Send(message)
+	return nil
+}
+
+func TestWebSocketReplaySendsInOrder(t *testing.T) {
+	session := testWebSocketSession("wss://example.com/live")
+	session.Frames = append(session.Frames, WebSocketFrame{
+		Direction: WebSocketFrameReceived,
+		Close:     &WebSocketCloseFrame{Code: Int(1000)},
+	})
+	replayer := &webSocketReplayer{
+		recording: &WebSocketRecording{Sessions: []*WebSocketSession{session}},
+		matcher:   MatchWebSocketMessage(),
+		notFound:  HarNotFoundAbort,
+		calls:     map[string]int{},
+	}
+	route := &fakeOrderedWebSocketRoute{fakeWebSocketRoute{url: "wss://example.com/live"}}
+	replayer.handle(route)
+	route.onMessage(`{"op":"subscribe","id":1}`)
+	route.onMessage("ping")
+	require.Equal(t, []any{"welcome", "subscribed", "tick 1", "pong", []byte{0xff, 0x00}}, route.messages())
+	require.Equal(t, 1000, *route.closed.Code)
+}
+
+type fakeRecorderPage struct {
+	Page
+}
+
+func (p *fakeRecorderPage) RemoveListener(name string, handler any) {}
+
+func TestWebSocketRecorderStopRemovesListeners(t *testing.T) {
+	recorder := &WebSocketRecorder{
+		page:      &fakeRecorderPage{},
+		path:      filepath.Join(t.TempDir(), "ws.json"),
+		recording: WebSocketRecording{Sessions: []*WebSocketSession{}},
+	}
+	ws := &webSocketImpl{}
+	ws.initializer = map[string]any{"url": "wss://example.com/live"}
+	ws.channel = &channel{}
+	recorder.onWebSocket(ws)
+	ws.channel.Emit("frameSent", map[string]any{"opcode": float64(1), "data": "hi"})
+	require.Equal(t, 1, ws.channel.ListenerCount("frameSent"))
+	require.Equal(t, 1, ws.ListenerCount("close"))
+
+	require.NoError(t, recorder.Stop())
+	require.Zero(t, ws.channel.ListenerCount("frameSent"))
+	require.Zero(t, ws.channel.ListenerCount("frameReceived"))
+	require.Zero(t, ws.ListenerCount("close"))
+	recording, err := ReadWebSocketRecording(recorder.path)
+	require.NoError(t, err)
+	require.Equal(t, "hi", recording.Sessions[0].Frames[0].Message())
+}
+
+func TestMatchWebSocketMessage(t *testing.T) {
+	match := MatchWebSocketMessage()
+	require.True(t, match("a", &WebSocketFrame{Text: String("a")}))
+	require.False(t, match([]byte("a"), &WebSocketFrame{Text: String("a")}))
+	require.True(t, match([]byte{1}, &WebSocketFrame{Binary: []byte{1}}))
+
+	matchJSON, err := MatchWebSocketJSON("$.meta.ts")
+	require.NoError(t, err)
+	require.True(t, matchJSON(`{"a":1,"meta":{"ts":2}}`, &WebSocketFrame{Text: String(`{"meta":{"ts":1},"a":1}`)}))
+	require.False(t, matchJSON(`{"a":2,"meta":{"ts":2}}`, &WebSocketFrame{Text: String(`{"meta":{"ts":1},"a":1}`)}))
+	require.True(t, matchJSON("plain", &WebSocketFrame{Text: String("plain")}))
+	_, err = MatchWebSocketJSON("$.items[")
+	require.Error(t, err)
+}
@@ -118,6 +118,17 @@ func (r *webSocketRouteImpl) Send(message any) {
 	go r.channel.SendNoReply("sendToPage", data)
 }
 
+// sendInOrder sends message to the page before returning, so that messages sent
+// one after another arrive in that order.
+func (r *webSocketRouteImpl) sendInOrder(message any) error {
+	data, err := transformWebSocketMessage(message)
+	if err != nil {
+		return fmt.Errorf("Could not encode WebSocket message: %w", err)
+	}
+	r.channel.SendNoReply("sendToPage", data)
+	return nil
+}
+
 func (r *webSocketRouteImpl) URL() string {
 	return r.initializer["url"].(string)
 }
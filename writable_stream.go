@@ -16,24 +16,35 @@ func (s *writableStream) Copy(file string) error {
 		return err
 	}
 	defer f.Close() //nolint:errcheck
+	return s.CopyFrom(f)
+}
 
+// CopyFrom writes everything read from r to the stream in chunks and closes it.
+func (s *writableStream) CopyFrom(r io.Reader) error {
+	buf := make([]byte, defaultCopyBufSize)
 	for {
-		buf := make([]byte, defaultCopyBufSize)
-		n, err := f.Read(buf)
-		if err != nil && err != io.EOF {
-			return err
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			_, sendErr := s.channel.Send("write", map[string]any{
+				"binary": base64.StdEncoding.EncodeToString(buf[:n]),
+			})
+			if sendErr != nil {
+				return sendErr
+			}
 		}
-		if n == 0 {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
 			break
 		}
-		_, err = s.channel.Send("write", map[string]any{
-			"binary": base64.StdEncoding.EncodeToString(buf[:n]),
-		})
 		if err != nil {
 			return err
 		}
 	}
-	_, err = s.channel.Send("close")
+	return s.Close()
+}
+
+// Close closes the stream without writing to it.
+func (s *writableStream) Close() error {
+	_, err := s.channel.Send("close")
 	return err
 }
 